@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/coderefs"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+func runCodeRefs(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("coderefs", flag.ExitOnError)
+	dir := fs.String("dir", ".", "source tree to scan")
+	repository := fs.String("repository", "", "name the references are stored under (default the directory's name)")
+	link := fs.String("link", "", "URL of a line in a code browser, with {path} and {line} placeholders")
+	all := fs.Bool("all", false, "keep keys that are not stored flags")
+	dryRun := fs.Bool("dry-run", false, "show the references without storing them")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	if *repository == "" {
+		abs, err := filepath.Abs(*dir)
+		if err != nil {
+			return err
+		}
+		*repository = filepath.Base(abs)
+	}
+	if *repository == "" || *repository == string(filepath.Separator) {
+		return errors.New("-repository is required when scanning the root directory")
+	}
+
+	found, err := coderefs.Scan(*dir)
+	if err != nil {
+		return fmt.Errorf("scanning %s: %w", *dir, err)
+	}
+	flags, err := ofClient.GetAllFlags(ctx)
+	if err != nil {
+		return err
+	}
+	references := []client.CodeReference{}
+	unknown := map[string]bool{}
+	for _, ref := range found {
+		// Keys that are not flags are usually other calls that look alike.
+		if _, ok := flags[ref.FlagName]; !ok && !*all {
+			unknown[ref.FlagName] = true
+			continue
+		}
+		references = append(references, client.CodeReference{
+			FlagName:   ref.FlagName,
+			Repository: *repository,
+			Path:       ref.Path,
+			Line:       ref.Line,
+			Snippet:    ref.Snippet,
+			URL:        codeURL(*link, ref),
+		})
+	}
+	for _, name := range sortedKeys(unknown) {
+		fmt.Fprintf(os.Stderr, "skipped %q, which is not a flag\n", name)
+	}
+
+	if !*dryRun {
+		if err := ofClient.ReplaceCodeReferences(ctx, *repository, references); err != nil {
+			return err
+		}
+	}
+	if format == outputJSON {
+		return printJSON(references)
+	}
+	rows := make([][]string, 0, len(references))
+	for _, ref := range references {
+		rows = append(rows, []string{ref.FlagName, ref.Path + ":" + strconv.Itoa(ref.Line), ref.Snippet})
+	}
+	if err := printTable([]string{"FLAG", "LOCATION", "CODE"}, rows); err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Println("Dry run, nothing was written.")
+	}
+	return nil
+}
+
+// codeURL fills in a link template for a reference.
+func codeURL(template string, ref coderefs.Reference) string {
+	if template == "" {
+		return ""
+	}
+	return strings.NewReplacer("{path}", ref.Path, "{line}", strconv.Itoa(ref.Line)).Replace(template)
+}
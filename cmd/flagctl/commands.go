@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	offlag "github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+	flagusage "github.com/zackarysantana/mongo-openfeature-go/src/usage"
+)
+
+func runGet(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	output := outputFlag(fs, outputJSON, outputJSON, outputYAML, outputTable)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := requireOne(positional, "flag name")
+	if err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+
+	def, err := ofClient.GetFlag(ctx, name)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case outputYAML:
+		return printYAML(def)
+	case outputTable:
+		return printDefinitionTable(def)
+	default:
+		return printJSON(def)
+	}
+}
+
+func printDefinitionTable(def *offlag.Definition) error {
+	rows := [][]string{
+		{"Name", def.FlagName},
+		{"Category", def.Category},
+		{"Default value", formatValue(def.DefaultValue)},
+		{"Default variant", def.DefaultVariant},
+		{"Revision", strconv.FormatInt(def.Revision, 10)},
+	}
+	for i, r := range def.Rules {
+		rows = append(rows, []string{fmt.Sprintf("Rule %d", i), formatValue(r)})
+	}
+	for _, env := range sortedKeys(def.Environments) {
+		rows = append(rows, []string{"Environment " + env, formatValue(def.Environments[env])})
+	}
+	return printTable([]string{"FIELD", "VALUE"}, rows)
+}
+
+func runList(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	category := fs.String("category", "", "only list flags in this category")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+
+	flags, err := ofClient.GetAllFlags(ctx)
+	if err != nil {
+		return err
+	}
+	definitions := []offlag.Definition{}
+	for _, name := range sortedKeys(flags) {
+		if *category == "" || flags[name].Category == *category {
+			definitions = append(definitions, flags[name])
+		}
+	}
+
+	if format == outputJSON {
+		return printJSON(definitions)
+	}
+	rows := make([][]string, 0, len(definitions))
+	for _, def := range definitions {
+		rows = append(rows, []string{
+			def.FlagName,
+			def.Category,
+			formatValue(def.DefaultValue),
+			def.DefaultVariant,
+			strconv.Itoa(len(def.Rules)),
+			strings.Join(sortedKeys(def.Environments), ","),
+			strconv.FormatInt(def.Revision, 10),
+		})
+	}
+	return printTable([]string{"NAME", "CATEGORY", "DEFAULT", "VARIANT", "RULES", "ENVIRONMENTS", "REVISION"}, rows)
+}
+
+func runSet(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("set", flag.ExitOnError)
+	path, inputFormat := inputFlags(fs)
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	data, fileFormat, err := readInput(*path, *inputFormat)
+	if err != nil {
+		return err
+	}
+	def, err := flagset.DecodeDefinition(bytes.NewReader(data), fileFormat)
+	if err != nil {
+		return err
+	}
+
+	// A definition with a revision, e.g. one saved with "get", is only
+	// written if the flag has not changed since.
+	if err := ofClient.SetFlag(ctx, *def); err != nil {
+		return err
+	}
+	saved, err := ofClient.GetFlag(ctx, def.FlagName)
+	if err != nil {
+		return err
+	}
+	if format == outputJSON {
+		return printJSON(saved)
+	}
+	fmt.Printf("Saved flag %q at revision %d\n", saved.FlagName, saved.Revision)
+	return nil
+}
+
+func runDelete(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := requireOne(positional, "flag name")
+	if err != nil {
+		return err
+	}
+	if err := ofClient.DeleteFlag(ctx, name); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted flag %q\n", name)
+	return nil
+}
+
+type evaluationResult struct {
+	FlagName    string `json:"flagName"`
+	Environment string `json:"environment,omitempty"`
+	Value       any    `json:"value"`
+	Variant     string `json:"variant,omitempty"`
+	Reason      string `json:"reason"`
+	// Rule is the index of the matching rule, or -1 for the default.
+	Rule int `json:"rule"`
+}
+
+func runEvaluate(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("evaluate", flag.ExitOnError)
+	rawContext := fs.String("context", "{}", "evaluation context as a JSON object, or @file to read it from a file")
+	environment := fs.String("env", "", "environment to evaluate in")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := requireOne(positional, "flag name")
+	if err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	evalCtx, err := parseContext(*rawContext)
+	if err != nil {
+		return err
+	}
+
+	def, err := ofClient.GetFlag(ctx, name)
+	if err != nil {
+		return err
+	}
+	resolved := def.ForEnvironment(*environment)
+	match := resolved.EvaluateWithMatch(evalCtx)
+	result := evaluationResult{
+		FlagName:    name,
+		Environment: *environment,
+		Value:       match.Value,
+		Variant:     match.Detail.Variant,
+		Reason:      string(match.Detail.Reason),
+		Rule:        match.MatchedRuleIndex,
+	}
+
+	if format == outputJSON {
+		return printJSON(result)
+	}
+	rule := "default"
+	if result.Rule >= 0 {
+		rule = strconv.Itoa(result.Rule)
+	}
+	return printTable([]string{"FLAG", "VALUE", "VARIANT", "REASON", "RULE"}, [][]string{
+		{result.FlagName, formatValue(result.Value), result.Variant, result.Reason, rule},
+	})
+}
+
+// parseContext parses an evaluation context given inline or as @file.
+func parseContext(raw string) (map[string]any, error) {
+	data := []byte(raw)
+	if path, ok := strings.CutPrefix(raw, "@"); ok {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("reading context: %w", err)
+		}
+	}
+	evalCtx := map[string]any{}
+	if err := json.Unmarshal(data, &evalCtx); err != nil {
+		return nil, fmt.Errorf("parsing context: %w", err)
+	}
+	return evalCtx, nil
+}
+
+func runDiff(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	path, inputFormat := inputFlags(fs)
+	mode := fs.String("mode", string(flagset.ModeMerge), "for flag sets, merge or replace")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	data, fileFormat, err := readInput(*path, *inputFormat)
+	if err != nil {
+		return err
+	}
+
+	var set *flagset.Set
+	if flagset.IsSet(data, fileFormat) {
+		if set, err = flagset.Decode(bytes.NewReader(data), fileFormat); err != nil {
+			return err
+		}
+	} else {
+		// A single definition is diffed in merge mode, as "set" would
+		// leave every other flag alone.
+		def, err := flagset.DecodeDefinition(bytes.NewReader(data), fileFormat)
+		if err != nil {
+			return err
+		}
+		set = flagset.New([]offlag.Definition{*def})
+		*mode = string(flagset.ModeMerge)
+	}
+	importMode, err := flagset.ParseMode(*mode)
+	if err != nil {
+		return err
+	}
+
+	plan, err := ofClient.Import(ctx, set, client.ImportOptions{Mode: importMode, DryRun: true})
+	if err != nil {
+		return err
+	}
+	return printPlan(plan, format)
+}
+
+func runExport(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	path := fs.String("f", "", "file to write (default standard output)")
+	formatName := fs.String("format", "", "json or yaml (default from the file extension, or json)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format := flagset.FormatFromPath(*path)
+	if *formatName != "" {
+		var err error
+		if format, err = flagset.ParseFormat(*formatName); err != nil {
+			return err
+		}
+	}
+
+	set, err := ofClient.Export(ctx)
+	if err != nil {
+		return err
+	}
+	if *path == "" || *path == "-" {
+		return flagset.Encode(os.Stdout, set, format)
+	}
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := flagset.Encode(file, set, format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d flags to %s\n", len(set.Flags), *path)
+	return nil
+}
+
+func runImport(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	path, inputFormat := inputFlags(fs)
+	mode := fs.String("mode", string(flagset.ModeMerge), "merge keeps flags missing from the file, replace deletes them")
+	dryRun := fs.Bool("dry-run", false, "show the changes without applying them")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	importMode, err := flagset.ParseMode(*mode)
+	if err != nil {
+		return err
+	}
+	data, fileFormat, err := readInput(*path, *inputFormat)
+	if err != nil {
+		return err
+	}
+	set, err := flagset.Decode(bytes.NewReader(data), fileFormat)
+	if err != nil {
+		return err
+	}
+
+	plan, err := ofClient.Import(ctx, set, client.ImportOptions{Mode: importMode, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	if err := printPlan(plan, format); err != nil {
+		return err
+	}
+	if format == outputTable && *dryRun && plan.HasChanges() {
+		fmt.Println("Dry run, nothing was written.")
+	}
+	return nil
+}
+
+func printPlan(plan *flagset.Plan, format string) error {
+	if format == outputJSON {
+		return printJSON(plan)
+	}
+	fmt.Print(plan)
+	return nil
+}
+
+func runHistory(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := requireOne(positional, "flag name")
+	if err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+
+	versions, err := ofClient.ListVersions(ctx, name)
+	if err != nil {
+		return err
+	}
+	if format == outputJSON {
+		return printJSON(versions)
+	}
+	if len(versions) == 0 {
+		return errors.New("no versions recorded")
+	}
+	rows := make([][]string, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []string{
+			strconv.Itoa(v.Version),
+			v.Timestamp.Local().Format(time.DateTime),
+			string(v.Operation),
+			v.Author,
+			strconv.Itoa(len(v.Diff)),
+		})
+	}
+	return printTable([]string{"VERSION", "TIME", "OPERATION", "AUTHOR", "CHANGES"}, rows)
+}
+
+func runStale(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("stale", flag.ExitOnError)
+	days := fs.Int("days", 30, "report flags not evaluated, or with a single variant, in this many days")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return errors.New("-days must be positive")
+	}
+
+	candidates, err := flagusage.StaleFlags(ctx, ofClient, time.Duration(*days)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	if format == outputJSON {
+		return printJSON(candidates)
+	}
+	rows := make([][]string, 0, len(candidates))
+	for _, c := range candidates {
+		lastEvaluated := "never"
+		if !c.LastEvaluated.IsZero() {
+			lastEvaluated = c.LastEvaluated.Local().Format(time.DateTime)
+		}
+		rows = append(rows, []string{c.FlagName, string(c.Reason), lastEvaluated, c.Variant})
+	}
+	return printTable([]string{"NAME", "REASON", "LAST EVALUATED", "VARIANT"}, rows)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
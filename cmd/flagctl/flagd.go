@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	offlag "github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagd"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+)
+
+func runImportFlagd(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("import-flagd", flag.ExitOnError)
+	path := fs.String("f", "", "flagd JSON file to read, or - for standard input")
+	mode := fs.String("mode", string(flagset.ModeMerge), "merge keeps flags missing from the file, replace deletes them")
+	dryRun := fs.Bool("dry-run", false, "show the changes without applying them")
+	output := outputFlag(fs, outputTable, outputJSON, outputTable)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output()
+	if err != nil {
+		return err
+	}
+	importMode, err := flagset.ParseMode(*mode)
+	if err != nil {
+		return err
+	}
+	data, _, err := readInput(*path, "json")
+	if err != nil {
+		return err
+	}
+	file, err := flagd.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	definitions, issues := flagd.Import(file)
+	printIssues(issues)
+	if len(issues) > 0 && importMode == flagset.ModeReplace {
+		// Replacing would delete the stored copies of the skipped flags.
+		return fmt.Errorf("%d flags could not be translated, fix them or import in merge mode", len(issues))
+	}
+	plan, err := ofClient.Import(ctx, flagset.New(definitions), client.ImportOptions{Mode: importMode, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	return printPlan(plan, format)
+}
+
+func runExportFlagd(ctx context.Context, ofClient *client.Client, args []string) error {
+	fs := flag.NewFlagSet("export-flagd", flag.ExitOnError)
+	path := fs.String("f", "", "file to write (default standard output)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	flags, err := ofClient.GetAllFlags(ctx)
+	if err != nil {
+		return err
+	}
+	definitions := make([]offlag.Definition, 0, len(flags))
+	for _, def := range flags {
+		definitions = append(definitions, def)
+	}
+	file, issues := flagd.Export(definitions)
+	printIssues(issues)
+
+	if *path == "" || *path == "-" {
+		return flagd.Encode(os.Stdout, file)
+	}
+	out, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := flagd.Encode(out, file); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// printIssues reports the flags that could not be translated. They are left
+// out rather than translated partially.
+func printIssues(issues []flagd.Issue) {
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "skipped %s\n", issue)
+	}
+}
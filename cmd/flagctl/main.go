@@ -0,0 +1,119 @@
+// flagctl manages feature flags from scripts and CI pipelines. The MongoDB
+// connection is configured with the same MONGODB_* environment variables as
+// the editor and MCP server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, ofClient *client.Client, args []string) error
+}
+
+var commands = []command{
+	{"get", "get <flag> [-o json|yaml|table]", "Show a flag definition", runGet},
+	{"list", "list [-category name] [-o json|table]", "List all flags", runList},
+	{"set", "set -f file [-o json|table]", "Create or replace a flag from a JSON or YAML file", runSet},
+	{"delete", "delete <flag>", "Delete a flag", runDelete},
+	{"evaluate", "evaluate <flag> [--context json|@file] [-env name] [-o json|table]", "Evaluate a flag against a context", runEvaluate},
+	{"diff", "diff -f file [-mode merge|replace] [-o json|table]", "Show what setting or importing a file would change", runDiff},
+	{"export", "export [-f file] [-format json|yaml]", "Export every flag as a flag set", runExport},
+	{"import", "import -f file [-mode merge|replace] [-dry-run] [-o json|table]", "Import a flag set", runImport},
+	{"history", "history <flag> [-o json|table]", "List the recorded versions of a flag", runHistory},
+	{"stale", "stale [-days 30] [-o json|table]", "List flags that are no longer evaluated or always resolve to one variant", runStale},
+	{"coderefs", "coderefs [-dir .] [-repository name] [-link url] [-all] [-dry-run] [-o json|table]", "Scan source code for flag evaluations and store where each flag is used", runCodeRefs},
+	{"import-flagd", "import-flagd -f flags.json [-mode merge|replace] [-dry-run] [-o json|table]", "Import flags from a flagd definition file", runImportFlagd},
+	{"export-flagd", "export-flagd [-f flags.json]", "Export flags as a flagd definition file", runExportFlagd},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage()
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "flagctl: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	_, ofClient, cleanup, err := internal.GetConnections(false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "flagctl: getting connections: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx = client.WithActor(ctx, client.Actor{Name: actorName(), Source: client.ActorSourceCLI})
+	err = cmd.run(ctx, ofClient, os.Args[2:])
+	stop()
+	cleanup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "flagctl %s: %v\n", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: flagctl <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(os.Stderr, "                flagctl %s\n", cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Writes are attributed to FLAGCTL_ACTOR, or USER when it is not set.")
+}
+
+// actorName is recorded in the audit log for writes made by flagctl.
+func actorName() string {
+	if name := os.Getenv("FLAGCTL_ACTOR"); name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
+
+// parseArgs parses fs from args and returns the positional arguments. Unlike
+// fs.Parse on its own, flags may also follow positional arguments, so both
+// "get -o json name" and "get name -o json" work.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// requireOne returns the single positional argument a command expects.
+func requireOne(positional []string, what string) (string, error) {
+	if len(positional) != 1 {
+		return "", fmt.Errorf("expected exactly one %s", what)
+	}
+	return positional[0], nil
+}
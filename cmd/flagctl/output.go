@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+// outputFlag registers the -o flag on fs with the given default and allowed
+// formats.
+func outputFlag(fs *flag.FlagSet, def string, allowed ...string) func() (string, error) {
+	output := fs.String("o", def, "output format: "+strings.Join(allowed, ", "))
+	return func() (string, error) {
+		for _, format := range allowed {
+			if *output == format {
+				return format, nil
+			}
+		}
+		return "", fmt.Errorf("unknown output format '%s', expected one of %s", *output, strings.Join(allowed, ", "))
+	}
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printYAML prints v with the field names of its JSON form.
+func printYAML(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// printTable prints rows under header, aligned in columns.
+func printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// formatValue renders a flag value compactly for tables.
+func formatValue(v any) string {
+	if v == nil {
+		return "-"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// inputFlags registers the -f and -format flags used to read definitions.
+func inputFlags(fs *flag.FlagSet) (path, format *string) {
+	path = fs.String("f", "", "JSON or YAML file to read, or - for standard input")
+	format = fs.String("format", "", "input format, json or yaml (default from the file extension)")
+	return path, format
+}
+
+// readInput reads a file, or standard input for "-", in the given format or
+// the one its extension implies.
+func readInput(path, format string) ([]byte, flagset.Format, error) {
+	if path == "" {
+		return nil, "", fmt.Errorf("a file is required (-f)")
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	if format == "" {
+		return data, flagset.FormatFromPath(path), nil
+	}
+	parsed, err := flagset.ParseFormat(format)
+	return data, parsed, err
+}
@@ -0,0 +1,72 @@
+// flagdsync serves the flags as a flagd configuration so flagd instances,
+// such as sidecars in other clusters, can use them as an HTTP sync source.
+// Changes seen by watching the collection are pushed to long-polling and
+// streaming clients as they happen.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/flagdsync"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+)
+
+// resyncInterval bounds how stale the configuration can get if a change
+// event is dropped.
+const resyncInterval = 30 * time.Second
+
+func main() {
+	mongoClient, ofClient, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	if os.Getenv("USE_TESTCONTAINER") == "true" {
+		if err = internal.InsertExampleData(ofClient); err != nil {
+			log.Fatalf("FATAL: inserting example data: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	eventHandler, err := eventhandler.New(eventhandler.NewOptions(
+		eventhandler.CreateDroppedEventLogger(slog.Default(), "flagdsync"),
+	))
+	if err != nil {
+		log.Fatalf("FATAL: creating event handler: %v", err)
+	}
+	flagCache, err := internal.GetWatchedCache(ctx, mongoClient, ofClient, os.Getenv("FLAGD_SYNC_ENVIRONMENT"), eventHandler)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	tokens := httputil.ParseTokens(os.Getenv("FLAGD_SYNC_TOKENS"))
+	if len(tokens) == 0 {
+		log.Println("WARNING: FLAGD_SYNC_TOKENS is not set, sync requests are not authenticated")
+	}
+	server, err := flagdsync.New(flagdsync.NewOptions(flagCache).WithTokens(tokens...))
+	if err != nil {
+		log.Fatalf("FATAL: creating flagd sync server: %v", err)
+	}
+
+	go internal.RefreshOnChange(ctx, eventHandler, resyncInterval, server.Refresh)
+
+	port := ":8015"
+	if envPort := os.Getenv("FLAGD_SYNC_PORT"); envPort != "" {
+		port = ":" + envPort
+	}
+	log.Println("Starting flagd sync server on http://localhost" + port + flagdsync.FlagsPath)
+	if err = internal.ListenAndServe(ctx, port, server); err != nil {
+		log.Printf("ERROR serving flagd sync: %v", err)
+	}
+}
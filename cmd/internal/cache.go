@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/watchhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// GetWatchedCache loads every flag into a cache for environment and keeps it
+// up to date by watching the collection until ctx is done. If eventHandler is
+// not nil, a ProviderConfigChange event is published to it after every change.
+func GetWatchedCache(ctx context.Context, mongoClient *mongo.Client, ofClient *client.Client, environment string, eventHandler *eventhandler.EventHandler) (*cache.Cache, error) {
+	flagCache := cache.NewForEnvironment(environment)
+	watchHandler, err := watchhandler.New(watchhandler.NewOptions(mongoClient, GetMongoDatabaseName(), GetMongoCollectionName(), flagCache).
+		WithDocumentID(GetMongoDocumentID()).
+		WithEventHandler(eventHandler).
+		WithParentContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating watch handler: %w", err)
+	}
+	// Watching starts before the flags are loaded so no change made in
+	// between is missed.
+	go watchHandler.Watch()
+
+	flags, err := ofClient.GetAllFlags(ctx)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		watchHandler.Close()
+		return nil, fmt.Errorf("loading flags: %w", err)
+	}
+	if err = flagCache.SetAll(flags); err != nil {
+		watchHandler.Close()
+		return nil, fmt.Errorf("caching flags: %w", err)
+	}
+	return flagCache, nil
+}
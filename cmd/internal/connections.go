@@ -64,11 +64,12 @@ func GetConnections(outputTestContainerEndpoint bool) (*mongo.Client, *client.Cl
 		cleanup()
 		return nil, nil, nil, fmt.Errorf("connecting to MongoDB: %w", err)
 	}
+	containerCleanup := cleanup
 	cleanup = func() {
-		cleanup()
 		if err := mongoClient.Disconnect(context.Background()); err != nil {
 			log.Printf("Error disconnecting MongoDB client: %v", err)
 		}
+		containerCleanup()
 	}
 
 	ofClient, err := client.New(client.NewOptions(mongoClient, database, collection).WithDocumentID(documentID))
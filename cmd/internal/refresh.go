@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+)
+
+// RefreshOnChange calls refresh after every event published to eventHandler
+// until ctx is done. It also calls it every interval, which bounds how stale
+// the result can get if an event is dropped.
+func RefreshOnChange(ctx context.Context, eventHandler *eventhandler.EventHandler, interval time.Duration, refresh func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-eventHandler.EventChannel():
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := refresh(); err != nil {
+			log.Printf("ERROR refreshing: %v", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+// The source layout is configured through the usual MONGODB_* environment
+// variables. The target is configured with flags and must be a different
+// collection, or a different document in a single-document collection.
+func main() {
+	targetDatabase := flag.String("target-database", internal.GetMongoDatabaseName(), "database of the target layout")
+	targetCollection := flag.String("target-collection", "", "collection of the target layout (required)")
+	targetDocumentID := flag.String("target-document-id", "", "document ID for a single-document target; empty for multi-document")
+	verifyOnly := flag.Bool("verify", false, "only compare the source and target, without copying")
+	mirror := flag.Duration("mirror", 0, "keep copying at this interval until interrupted, then copy once more, for a cutover while providers are running")
+	flag.Parse()
+
+	if *targetCollection == "" {
+		log.Fatal("FATAL: -target-collection is required")
+	}
+
+	_, source, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	// History, audit, scheduled changes and the rest stay in the source's
+	// collections so they carry over to the new layout.
+	target, err := source.MigrationTarget(*targetDatabase, *targetCollection, *targetDocumentID)
+	if err != nil {
+		log.Fatalf("FATAL: creating target client: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch {
+	case *verifyOnly:
+		mismatches, err := source.VerifyMigration(ctx, target)
+		if err != nil {
+			log.Fatalf("FATAL: verifying migration: %v", err)
+		}
+		printJSON(mismatches)
+		if len(mismatches) > 0 {
+			os.Exit(1)
+		}
+	case *mirror > 0:
+		log.Printf("Mirroring flags to %s every %s. Freeze writes, then interrupt for a final run", *targetCollection, *mirror)
+		report, err := source.MirrorTo(ctx, target, *mirror, func(report *client.MigrationReport) {
+			if report.Written > 0 || report.Deleted > 0 || !report.Verified() {
+				log.Printf("Mirrored %d flags: %d written, %d deleted, %d mismatches", report.Flags, report.Written, report.Deleted, len(report.Mismatches))
+			}
+		})
+		if err != nil {
+			log.Fatalf("FATAL: mirroring flags: %v", err)
+		}
+		printJSON(report)
+		if !report.Verified() {
+			os.Exit(1)
+		}
+	default:
+		report, err := source.Migrate(ctx, target)
+		if err != nil {
+			log.Fatalf("FATAL: migrating flags: %v", err)
+		}
+		printJSON(report)
+		if !report.Verified() {
+			os.Exit(1)
+		}
+	}
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "encoding output: %v\n", err)
+	}
+}
@@ -0,0 +1,56 @@
+// ofrep serves the OpenFeature Remote Evaluation Protocol so applications
+// in any language can evaluate flags over HTTP. Flags are evaluated from an
+// in-memory cache that is kept up to date by watching the collection.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/ofrep"
+)
+
+func main() {
+	mongoClient, ofClient, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	if os.Getenv("USE_TESTCONTAINER") == "true" {
+		if err = internal.InsertExampleData(ofClient); err != nil {
+			log.Fatalf("FATAL: inserting example data: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	flagCache, err := internal.GetWatchedCache(ctx, mongoClient, ofClient, os.Getenv("OFREP_ENVIRONMENT"), nil)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	tokens := httputil.ParseTokens(os.Getenv("OFREP_TOKENS"))
+	if len(tokens) == 0 {
+		log.Println("WARNING: OFREP_TOKENS is not set, evaluation requests are not authenticated")
+	}
+	handler, err := ofrep.New(ofrep.NewOptions(flagCache).WithTokens(tokens...))
+	if err != nil {
+		log.Fatalf("FATAL: creating OFREP server: %v", err)
+	}
+
+	port := ":8016"
+	if envPort := os.Getenv("OFREP_PORT"); envPort != "" {
+		port = ":" + envPort
+	}
+	log.Println("Starting OFREP server on http://localhost" + port)
+	if err = internal.ListenAndServe(ctx, port, handler); err != nil {
+		log.Printf("ERROR serving OFREP: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/reconciler"
+)
+
+func main() {
+	directory := flag.String("dir", "", "directory of JSON and YAML flag definitions (required)")
+	owner := flag.String("owner", "", `ownership marker for managed flags (default "reconciler")`)
+	adopt := flag.Bool("adopt", false, "take over flags with the same name that were created by hand")
+	dryRun := flag.Bool("dry-run", false, "print the changes without applying them")
+	watch := flag.Bool("watch", false, "keep running and reconcile whenever the directory changes")
+	output := flag.String("o", "text", "output format of a one-shot run: text or json")
+	flag.Parse()
+
+	if *directory == "" {
+		log.Fatal("FATAL: -dir is required")
+	}
+
+	_, ofClient, cleanup, err := internal.GetConnections(*watch)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	r, err := reconciler.New(reconciler.NewOptions(ofClient, *directory).
+		WithOwner(*owner).
+		WithAdopt(*adopt).
+		WithDryRun(*dryRun).
+		WithParentContext(ctx))
+	if err != nil {
+		log.Fatalf("FATAL: creating reconciler: %v", err)
+	}
+
+	if *watch {
+		log.Printf("Reconciling flags from %s", *directory)
+		r.Run()
+		return
+	}
+
+	result, err := r.Reconcile(ctx)
+	if err != nil {
+		log.Fatalf("FATAL: reconciling flags: %v", err)
+	}
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			log.Fatalf("FATAL: encoding result: %v", err)
+		}
+		return
+	}
+	if len(result.Actions) == 0 {
+		fmt.Println("No drift, nothing to do.")
+		return
+	}
+	for _, action := range result.Actions {
+		fmt.Print(action)
+	}
+	if !result.Applied && result.Changed() > 0 {
+		fmt.Println("Dry run, nothing was written.")
+	}
+}
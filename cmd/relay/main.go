@@ -0,0 +1,70 @@
+// relay watches the flag collection once and serves snapshots and deltas to
+// any number of relayprovider instances, so services don't each open their
+// own change stream.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/relay"
+)
+
+// resyncInterval bounds how stale the relay can get if a change event is
+// dropped.
+const resyncInterval = 30 * time.Second
+
+func main() {
+	mongoClient, ofClient, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	if os.Getenv("USE_TESTCONTAINER") == "true" {
+		if err = internal.InsertExampleData(ofClient); err != nil {
+			log.Fatalf("FATAL: inserting example data: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	eventHandler, err := eventhandler.New(eventhandler.NewOptions(
+		eventhandler.CreateDroppedEventLogger(slog.Default(), "relay"),
+	))
+	if err != nil {
+		log.Fatalf("FATAL: creating event handler: %v", err)
+	}
+	flagCache, err := internal.GetWatchedCache(ctx, mongoClient, ofClient, os.Getenv("RELAY_ENVIRONMENT"), eventHandler)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	tokens := httputil.ParseTokens(os.Getenv("RELAY_TOKENS"))
+	if len(tokens) == 0 {
+		log.Println("WARNING: RELAY_TOKENS is not set, relay requests are not authenticated")
+	}
+	server, err := relay.New(relay.NewOptions(flagCache).WithTokens(tokens...))
+	if err != nil {
+		log.Fatalf("FATAL: creating relay server: %v", err)
+	}
+	go internal.RefreshOnChange(ctx, eventHandler, resyncInterval, server.Refresh)
+
+	port := ":8017"
+	if envPort := os.Getenv("RELAY_PORT"); envPort != "" {
+		port = ":" + envPort
+	}
+	log.Println("Starting flag relay on http://localhost" + port)
+	if err = internal.ListenAndServe(ctx, port, server); err != nil {
+		log.Printf("ERROR serving relay: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/scheduler"
+)
+
+func main() {
+	_, ofClient, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	opts := scheduler.NewOptions(ofClient)
+	if interval := os.Getenv("SCHEDULER_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			log.Fatalf("FATAL: parsing SCHEDULER_INTERVAL: %v", err)
+		}
+		opts = opts.WithInterval(d)
+	}
+
+	s, err := scheduler.New(opts)
+	if err != nil {
+		log.Fatalf("FATAL: creating scheduler: %v", err)
+	}
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		s.Close()
+	}()
+
+	log.Println("Starting flag change scheduler")
+	s.Run()
+}
@@ -0,0 +1,63 @@
+// webhooks watches the flag collection and sends a signed HTTP POST to every
+// configured webhook covering a flag that changed. Run a single instance per
+// collection, or each change is delivered more than once.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/cmd/internal"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/webhook"
+)
+
+// resyncInterval bounds how late a delivery can be if a change event is
+// dropped.
+const resyncInterval = 30 * time.Second
+
+func main() {
+	mongoClient, ofClient, cleanup, err := internal.GetConnections(true)
+	if err != nil {
+		log.Fatalf("FATAL: getting connections: %v", err)
+	}
+	defer cleanup()
+
+	if os.Getenv("USE_TESTCONTAINER") == "true" {
+		if err = internal.InsertExampleData(ofClient); err != nil {
+			log.Fatalf("FATAL: inserting example data: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	eventHandler, err := eventhandler.New(eventhandler.NewOptions(
+		eventhandler.CreateDroppedEventLogger(slog.Default(), "webhooks"),
+	))
+	if err != nil {
+		log.Fatalf("FATAL: creating event handler: %v", err)
+	}
+	// The cache is only watched for its change events; the dispatcher reads
+	// every environment's definitions from the client itself.
+	if _, err = internal.GetWatchedCache(ctx, mongoClient, ofClient, "", eventHandler); err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	dispatcher, err := webhook.New(webhook.NewOptions(ofClient).WithParentContext(ctx))
+	if err != nil {
+		log.Fatalf("FATAL: creating webhook dispatcher: %v", err)
+	}
+	defer dispatcher.Close()
+	if err = dispatcher.Check(); err != nil {
+		log.Fatalf("FATAL: loading flags: %v", err)
+	}
+
+	log.Println("Dispatching webhooks for flag changes")
+	internal.RefreshOnChange(ctx, eventHandler, resyncInterval, dispatcher.Check)
+}
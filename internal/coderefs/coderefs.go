@@ -0,0 +1,164 @@
+// Package coderefs finds the flag keys referenced by OpenFeature client calls
+// in a source tree, so a flag's uses can be checked before it is removed.
+// Calls are found with patterns per language rather than by parsing, so only
+// keys written as string literals in the call are found.
+package coderefs
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxFileSize is the largest file scanned; larger files are most likely
+// generated or data.
+const maxFileSize = 1 << 20
+
+// maxSnippetLength is the longest line kept as a reference's snippet.
+const maxSnippetLength = 200
+
+// Reference is a flag key used in a source file.
+type Reference struct {
+	FlagName string
+	// Path is relative to the scanned directory, with forward slashes.
+	Path    string
+	Line    int
+	Snippet string
+}
+
+// keyFirst matches a quoted flag key as the first argument, optionally
+// named as in Ruby's flag_key: and Swift's key:.
+const keyFirst = `\(\s*(?:(?:flag_key|key):\s*)?["'\x60]([^"'\x60\s]+)["'\x60]`
+
+// contextFirst matches a quoted flag key following a context argument, as
+// the Go SDK takes. Requiring the context keeps calls such as
+// slog.String("key", value) from matching.
+const contextFirst = `\(\s*[A-Za-z_][\w.]*(?:\(\))?\s*,\s*["\x60]([^"\x60\s]+)["\x60]`
+
+// patterns match the OpenFeature evaluation calls of each language, by file
+// extension.
+var patterns = map[string]*regexp.Regexp{}
+
+func init() {
+	languages := []struct {
+		extensions []string
+		methods    string
+		arguments  string
+	}{
+		// Go: client.BooleanValue(ctx, "key", ...) and client.Boolean(ctx, "key", ...).
+		{[]string{".go"}, `(?:Boolean|String|Int|Float|Object)(?:Value)?(?:Details)?`, contextFirst},
+		// JavaScript and TypeScript, including the React hooks.
+		{[]string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".vue", ".svelte"}, `(?:get(?:Boolean|String|Number|Object)(?:Value|Details)|use(?:Boolean|String|Number|Object)?Flag(?:Value|Details)?)`, keyFirst},
+		// Python.
+		{[]string{".py"}, `get_(?:boolean|string|integer|float|object)_(?:value|details)`, keyFirst},
+		// Java, Kotlin and PHP.
+		{[]string{".java", ".kt", ".php"}, `get(?:Boolean|String|Integer|Double|Object)(?:Value|Details)`, keyFirst},
+		// .NET.
+		{[]string{".cs"}, `Get(?:Boolean|String|Integer|Double|Object)(?:Value|Details)Async`, keyFirst},
+		// Ruby.
+		{[]string{".rb"}, `fetch_(?:boolean|string|number|integer|float|object)_(?:value|details)`, keyFirst},
+		// Rust.
+		{[]string{".rs"}, `get_(?:bool|string|int|float|struct)_(?:value|details)`, keyFirst},
+		// Swift.
+		{[]string{".swift"}, `get(?:Boolean|String|Integer|Double|Object)(?:Value|Details)`, keyFirst},
+	}
+	for _, language := range languages {
+		pattern := regexp.MustCompile(`\b` + language.methods + language.arguments)
+		for _, extension := range language.extensions {
+			patterns[extension] = pattern
+		}
+	}
+}
+
+// skippedDirs are never scanned.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	"__pycache__":  true,
+}
+
+// Scan walks dir and returns every reference found, ordered by path and
+// line. Hidden directories and dependency directories are skipped.
+func Scan(dir string) ([]Reference, error) {
+	var references []Reference
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		pattern, ok := patterns[strings.ToLower(filepath.Ext(path))]
+		if !ok || !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		references = append(references, scanFile(filepath.ToSlash(rel), content, pattern)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(references, func(i, j int) bool {
+		if references[i].Path != references[j].Path {
+			return references[i].Path < references[j].Path
+		}
+		return references[i].Line < references[j].Line
+	})
+	return references, nil
+}
+
+// scanFile returns the references in a file's content.
+func scanFile(path string, content []byte, pattern *regexp.Regexp) []Reference {
+	var references []Reference
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		seen := map[string]bool{}
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			if seen[match[1]] {
+				continue
+			}
+			seen[match[1]] = true
+			references = append(references, Reference{
+				FlagName: match[1],
+				Path:     path,
+				Line:     line,
+				Snippet:  snippet(text),
+			})
+		}
+	}
+	return references
+}
+
+func snippet(line string) string {
+	line = strings.TrimSpace(line)
+	if len(line) > maxSnippetLength {
+		line = line[:maxSnippetLength] + "…"
+	}
+	return line
+}
@@ -0,0 +1,81 @@
+package coderefs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", `package main
+
+func handler() {
+	if client.Boolean(ctx, "new_checkout", false, evalCtx) {
+		version := client.StringValue(r.Context(), "v2_enabled", "v1", evalCtx)
+		slog.Info("serving", slog.String("version", version))
+	}
+	details, _ := client.FloatValueDetails(ctx, `+"`discount`"+`, 0, evalCtx)
+}
+`)
+	writeFile(t, dir, "web/app.tsx", `const enabled = useBooleanFlagValue('dark_mode', false);
+const details = client.getStringDetails("banner", "none");
+`)
+	writeFile(t, dir, "api/service.py", `limit = client.get_integer_value("rate_limit", 10)
+`)
+	writeFile(t, dir, "api/Service.java", `boolean on = client.getBooleanValue("new_checkout", false);
+`)
+	writeFile(t, dir, "app/flags.rb", `client.fetch_boolean_value(flag_key: "beta", default_value: false)
+`)
+	writeFile(t, dir, "App/Flags.cs", `var on = await client.GetBooleanValueAsync("beta", false);
+`)
+	writeFile(t, dir, "node_modules/sdk/index.js", `client.getBooleanValue("ignored", false);
+`)
+	writeFile(t, dir, ".git/hooks/pre-commit.py", `client.get_boolean_value("ignored", False)
+`)
+	writeFile(t, dir, "README.md", `client.Boolean(ctx, "ignored", false, evalCtx)
+`)
+
+	references, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	type found struct {
+		FlagName string
+		Path     string
+		Line     int
+	}
+	var got []found
+	for _, ref := range references {
+		got = append(got, found{ref.FlagName, ref.Path, ref.Line})
+	}
+	want := []found{
+		{"beta", "App/Flags.cs", 1},
+		{"new_checkout", "api/Service.java", 1},
+		{"rate_limit", "api/service.py", 1},
+		{"beta", "app/flags.rb", 1},
+		{"new_checkout", "main.go", 4},
+		{"v2_enabled", "main.go", 5},
+		{"discount", "main.go", 8},
+		{"dark_mode", "web/app.tsx", 1},
+		{"banner", "web/app.tsx", 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan found\n%v\nwant\n%v", got, want)
+	}
+	if want := `version := client.StringValue(r.Context(), "v2_enabled", "v1", evalCtx)`; references[5].Snippet != want {
+		t.Errorf("Snippet = %q, want %q", references[5].Snippet, want)
+	}
+}
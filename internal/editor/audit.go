@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+// auditDateLayout is the format of the date inputs on the audit log page.
+const auditDateLayout = "2006-01-02"
+
+// auditEntryView is a single row on the audit log page.
+type auditEntryView struct {
+	Timestamp string
+	Actor     string
+	Source    string
+	Address   string
+	Operation string
+	FlagName  string
+	Revision  int64
+	Detail    string
+	Changes   []string
+}
+
+func buildAuditEntryViews(entries []client.AuditEntry) []auditEntryView {
+	views := make([]auditEntryView, len(entries))
+	for i, e := range entries {
+		changes := make([]string, len(e.Diff))
+		for j, c := range e.Diff {
+			changes[j] = c.String()
+		}
+		actor := e.Actor.Name
+		if actor == "" {
+			actor = "unknown"
+		}
+		views[i] = auditEntryView{
+			Timestamp: e.Timestamp.Local().Format(time.DateTime),
+			Actor:     actor,
+			Source:    string(e.Actor.Source),
+			Address:   e.Actor.Address,
+			Operation: string(e.Operation),
+			FlagName:  e.FlagName,
+			Revision:  e.Revision,
+			Detail:    e.Detail,
+			Changes:   changes,
+		}
+	}
+	return views
+}
+
+// parseAuditQuery reads the audit log filters from the query string. Dates
+// are whole days in the server's time zone; "until" includes the given day.
+func parseAuditQuery(values url.Values) (client.AuditQuery, error) {
+	query := client.AuditQuery{
+		FlagName: strings.TrimSpace(values.Get("flag")),
+		Actor:    strings.TrimSpace(values.Get("actor")),
+		Source:   client.ActorSource(strings.TrimSpace(values.Get("source"))),
+		Limit:    200,
+	}
+	if since := values.Get("since"); since != "" {
+		t, err := time.ParseInLocation(auditDateLayout, since, time.Local)
+		if err != nil {
+			return query, err
+		}
+		query.Since = t
+	}
+	if until := values.Get("until"); until != "" {
+		t, err := time.ParseInLocation(auditDateLayout, until, time.Local)
+		if err != nil {
+			return query, err
+		}
+		query.Until = t.AddDate(0, 0, 1)
+	}
+	return query, nil
+}
+
+// HandleAuditLog shows flag mutations, newest first, filtered by the query
+// string (flag, actor, source, since, until).
+func (h *WebHandler) HandleAuditLog(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query, err := parseAuditQuery(values)
+	if err != nil {
+		http.Error(w, "Invalid date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.client.ListAuditEntries(r.Context(), query)
+	if err != nil {
+		log.Printf("ERROR listing audit entries: %v", err)
+		http.Error(w, "Failed to load audit log", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, "audit", map[string]any{
+		"Filters": map[string]string{
+			"Flag":   values.Get("flag"),
+			"Actor":  values.Get("actor"),
+			"Source": values.Get("source"),
+			"Since":  values.Get("since"),
+			"Until":  values.Get("until"),
+		},
+		"Sources": []client.ActorSource{
+			client.ActorSourceEditor,
+			client.ActorSourceAssistant,
+			client.ActorSourceMCP,
+			client.ActorSourceScheduler,
+			client.ActorSourceCLI,
+			client.ActorSourceReconciler,
+			client.ActorSourceClient,
+		},
+		"Entries": buildAuditEntryViews(entries),
+		"Limit":   query.Limit,
+	})
+}
@@ -0,0 +1,108 @@
+package editor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestParseAuditQuery(t *testing.T) {
+	query, err := parseAuditQuery(url.Values{
+		"flag":   {" checkout "},
+		"actor":  {"alice"},
+		"source": {"editor"},
+		"since":  {"2025-03-01"},
+		"until":  {"2025-03-02"},
+	})
+	if err != nil {
+		t.Fatalf("parseAuditQuery: %v", err)
+	}
+	if query.FlagName != "checkout" || query.Actor != "alice" || query.Source != client.ActorSourceEditor {
+		t.Fatalf("unexpected filters: %+v", query)
+	}
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local); !query.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", query.Since, want)
+	}
+	// The until date is inclusive, so the bound is the start of the next day.
+	if want := time.Date(2025, 3, 3, 0, 0, 0, 0, time.Local); !query.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", query.Until, want)
+	}
+
+	if _, err := parseAuditQuery(url.Values{"since": {"yesterday"}}); err == nil {
+		t.Errorf("expected an error for an invalid date")
+	}
+}
+
+func TestAuditPage(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	entries := buildAuditEntryViews([]client.AuditEntry{
+		{
+			Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			Actor:     client.Actor{Name: "alice", Source: client.ActorSourceEditor, Address: "10.0.0.7"},
+			Operation: client.OperationSet,
+			FlagName:  "checkout",
+			Revision:  3,
+			Diff:      []flag.Change{{Kind: flag.ChangeChanged, Path: "DefaultVariant", Old: "off", New: "on"}},
+		},
+		{
+			Actor:     client.Actor{Source: client.ActorSourceClient},
+			Operation: client.OperationSchedule,
+			FlagName:  "checkout",
+			Detail:    "delete scheduled for 2025-04-01T00:00:00Z",
+		},
+	})
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Filters": map[string]string{"Source": "editor"},
+		"Sources": []client.ActorSource{client.ActorSourceEditor, client.ActorSourceMCP},
+		"Entries": entries,
+		"Limit":   200,
+	}
+	if err := h.templates["audit"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering audit page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		"alice",
+		"editor · 10.0.0.7",
+		"revision 3",
+		`~ DefaultVariant: &#34;off&#34; -&gt; &#34;on&#34;`,
+		"unknown",
+		"delete scheduled for",
+		`<option value="editor" selected>`,
+		`href="/history/checkout"`,
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected audit page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+}
+
+func TestRequestActor(t *testing.T) {
+	r := httptest.NewRequest("POST", "/save", nil)
+	r.RemoteAddr = "10.0.0.2:51234"
+	r.Header.Set("X-Forwarded-User", "alice")
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	h := NewWebHandler(nil)
+	got := h.requestActor(r, client.ActorSourceEditor)
+	want := client.Actor{Name: "anonymous", Source: client.ActorSourceEditor, Address: "10.0.0.2"}
+	if got != want {
+		t.Errorf("without a user header, requestActor = %+v, want %+v", got, want)
+	}
+
+	h.userHeader = "X-Forwarded-User"
+	got = h.requestActor(r, client.ActorSourceEditor)
+	want = client.Actor{Name: "alice", Source: client.ActorSourceEditor, Address: "203.0.113.7"}
+	if got != want {
+		t.Errorf("behind a proxy, requestActor = %+v, want %+v", got, want)
+	}
+}
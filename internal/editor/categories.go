@@ -10,7 +10,13 @@ import (
 // flagListSection groups flags for display on the home page.
 type flagListSection struct {
 	Name  string
-	Flags []flag.Definition
+	Flags []flagRow
+}
+
+// flagRow is a flag on the home page with its usage, when usage is counted.
+type flagRow struct {
+	flag.Definition
+	Usage *flagUsageView
 }
 
 // normalizeFlagName ensures FlagName is set when flags come from a map key.
@@ -51,20 +57,20 @@ func BuildFlagListSections(flags map[string]flag.Definition) []flagListSection {
 		return nil
 	}
 
-	byCategory := make(map[string][]flag.Definition)
-	var uncategorized []flag.Definition
+	byCategory := make(map[string][]flagRow)
+	var uncategorized []flagRow
 
 	for name, def := range flags {
 		def = normalizeFlagName(name, def)
 		cat := strings.TrimSpace(def.Category)
 		if cat == "" {
-			uncategorized = append(uncategorized, def)
+			uncategorized = append(uncategorized, flagRow{Definition: def})
 		} else {
-			byCategory[cat] = append(byCategory[cat], def)
+			byCategory[cat] = append(byCategory[cat], flagRow{Definition: def})
 		}
 	}
 
-	sortFlags := func(list []flag.Definition) {
+	sortFlags := func(list []flagRow) {
 		sort.Slice(list, func(i, j int) bool {
 			return list[i].FlagName < list[j].FlagName
 		})
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
 )
 
 const (
@@ -121,7 +123,7 @@ func (h *WebHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	referer := openRouterReferer(r)
 	sess := defaultStreamRegistry.Create()
 
-	go h.runChatTurn(sess, apiKey, model, req, referer)
+	go h.runChatTurn(sess, apiKey, model, req, referer, h.requestActor(r, client.ActorSourceAssistant))
 
 	writeJSON(w, http.StatusOK, map[string]string{"streamId": sess.id})
 }
@@ -188,11 +190,11 @@ func (h *WebHandler) HandleChatStream(w http.ResponseWriter, r *http.Request) {
 // runChatTurn owns the lifetime of a single assistant turn. It runs in its own
 // goroutine and is decoupled from the HTTP request that started it, so the
 // stream survives client disconnects.
-func (h *WebHandler) runChatTurn(sess *streamSession, apiKey, model string, req chatRequest, referer string) {
+func (h *WebHandler) runChatTurn(sess *streamSession, apiKey, model string, req chatRequest, referer string, actor client.Actor) {
 	defer sess.Finish()
 
 	send := sess.Append
-	ctx := context.Background()
+	ctx := client.WithActor(context.Background(), actor)
 
 	messages := append([]chatMessage{
 		{Role: "system", Content: assistantSystemPrompt(req.CurrentFlag)},
@@ -0,0 +1,61 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestEditPageCodeReferences(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	render := func(references []client.CodeReference) string {
+		var buf bytes.Buffer
+		data := map[string]any{
+			"Flag":                 &flag.Definition{FlagName: "checkout", Revision: 2},
+			"RulesJSON":            "[]",
+			"DefaultValueJSON":     `""`,
+			"ContextKeyFieldsJSON": `[]`,
+			"CodeReferences":       references,
+			"TestResult":           testResultData{},
+		}
+		if err := h.templates["edit"].ExecuteTemplate(&buf, "layout", data); err != nil {
+			t.Fatalf("rendering edit page: %v", err)
+		}
+		return buf.String()
+	}
+
+	got := render([]client.CodeReference{
+		{
+			FlagName:   "checkout",
+			Repository: "shop",
+			Path:       "cmd/server/main.go",
+			Line:       42,
+			Snippet:    `client.Boolean(ctx, "checkout", false, evalCtx)`,
+			URL:        "https://example.com/shop/blob/main/cmd/server/main.go#L42",
+			ScannedAt:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{FlagName: "checkout", Repository: "web", Path: "src/App.tsx", Line: 7},
+	})
+	for _, fragment := range []string{
+		`href="https://example.com/shop/blob/main/cmd/server/main.go#L42"`,
+		"shop/cmd/server/main.go:42",
+		`client.Boolean(ctx, &#34;checkout&#34;, false, evalCtx)`,
+		`<span class="code-refs__location">web/src/App.tsx:7</span>`,
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected edit page to contain %q", fragment)
+		}
+	}
+	if strings.Contains(got, "No references found") {
+		t.Errorf("did not expect the empty message with references")
+	}
+
+	if got := render(nil); !strings.Contains(got, "No references found") {
+		t.Errorf("expected the empty message without references")
+	}
+}
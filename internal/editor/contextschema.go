@@ -0,0 +1,251 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// contextKeyView is a declared key on the context schema page.
+type contextKeyView struct {
+	Name        string
+	Type        string
+	Description string
+	// Flags are the flags with rules reading the key.
+	Flags []string
+}
+
+// observedKeyView is a key seen in sampled contexts, with one row per type
+// it was sent with.
+type observedKeyView struct {
+	Key      string
+	Type     string
+	Count    int64
+	LastSeen string
+	// Problem is empty for keys sent as declared.
+	Problem string
+}
+
+// flagIssuesView lists the schema issues of one flag's rules.
+type flagIssuesView struct {
+	FlagName string
+	Issues   []string
+}
+
+func buildContextKeyViews(schema contextschema.Schema, flags map[string]flag.Definition) []contextKeyView {
+	readers := map[string][]string{}
+	for name, def := range flags {
+		keys := map[string]bool{}
+		for _, key := range rule.CollectContextKeys(def.Rules) {
+			keys[key] = true
+		}
+		for _, env := range def.Environments {
+			for _, key := range rule.CollectContextKeys(env.Rules) {
+				keys[key] = true
+			}
+		}
+		for key := range keys {
+			readers[key] = append(readers[key], name)
+		}
+	}
+
+	views := make([]contextKeyView, len(schema))
+	for i, key := range schema {
+		flagNames := readers[key.Name]
+		sort.Strings(flagNames)
+		views[i] = contextKeyView{
+			Name:        key.Name,
+			Type:        string(key.Type),
+			Description: key.Description,
+			Flags:       flagNames,
+		}
+	}
+	return views
+}
+
+// buildFlagIssueViews checks every flag against the schema, for flags saved
+// before the keys they read were declared.
+func buildFlagIssueViews(schema contextschema.Schema, flags map[string]flag.Definition) []flagIssuesView {
+	var views []flagIssuesView
+	for name, def := range flags {
+		issues := schema.CheckDefinition(def)
+		if len(issues) == 0 {
+			continue
+		}
+		view := flagIssuesView{FlagName: name}
+		for _, issue := range issues {
+			view.Issues = append(view.Issues, issue.String())
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].FlagName < views[j].FlagName })
+	return views
+}
+
+func buildObservedKeyViews(schema contextschema.Schema, observations []contextschema.Observation) []observedKeyView {
+	unknown := map[string]contextschema.Issue{}
+	mistyped := map[string]bool{}
+	for _, issue := range schema.CheckObservations(observations) {
+		if issue.Kind == contextschema.IssueUnknownKey {
+			unknown[issue.Key] = issue
+		} else {
+			mistyped[issue.Key+"/"+string(issue.Actual)] = true
+		}
+	}
+
+	views := make([]observedKeyView, len(observations))
+	for i, o := range observations {
+		view := observedKeyView{
+			Key:      o.Key,
+			Type:     string(o.Type),
+			Count:    o.Count,
+			LastSeen: o.LastSeen.Local().Format(time.DateTime),
+		}
+		if view.Type == "" {
+			view.Type = "null"
+		}
+		if issue, ok := unknown[o.Key]; ok {
+			view.Problem = "not declared"
+			if issue.Suggestion != "" {
+				view.Problem += fmt.Sprintf(", did you mean %s?", issue.Suggestion)
+			}
+		} else if mistyped[o.Key+"/"+string(o.Type)] {
+			key, _ := schema.Lookup(o.Key)
+			view.Problem = "declared as " + string(key.Type)
+		}
+		views[i] = view
+	}
+	return views
+}
+
+// contextSchemaIssues describes why a flag was rejected by the context
+// schema, for a toast.
+func contextSchemaIssues(err *client.ContextSchemaError) string {
+	messages := make([]string, len(err.Issues))
+	for i, issue := range err.Issues {
+		messages[i] = issue.String()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// parseContextKeyForm reads a key from the form on the context schema page.
+func parseContextKeyForm(values url.Values) (contextschema.Key, error) {
+	key := contextschema.Key{
+		Name:        strings.TrimSpace(values.Get("name")),
+		Type:        contextschema.Type(values.Get("type")),
+		Description: strings.TrimSpace(values.Get("description")),
+	}
+	return key, key.Validate()
+}
+
+// HandleContextSchema lists the declared context keys, the flags whose rules
+// do not match them, and the keys seen in sampled contexts.
+func (h *WebHandler) HandleContextSchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := h.client.GetContextSchema(r.Context())
+	if err != nil {
+		log.Printf("ERROR getting context schema: %v", err)
+		http.Error(w, "Failed to load the context schema", http.StatusInternalServerError)
+		return
+	}
+	observations, err := h.client.ListContextObservations(r.Context())
+	if err != nil {
+		// The page is still useful without the observations.
+		log.Printf("ERROR listing context observations: %v", err)
+	}
+	flags, err := h.client.GetAllFlags(r.Context())
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("ERROR fetching flags: %v", err)
+	}
+
+	h.renderTemplate(w, "contextschema", map[string]any{
+		"Keys":       buildContextKeyViews(schema, flags),
+		"Types":      contextschema.Types,
+		"FlagIssues": buildFlagIssueViews(schema, flags),
+		"Observed":   buildObservedKeyViews(schema, observations),
+	})
+}
+
+// HandleSaveContextKey adds or replaces a key from the form on the context
+// schema page.
+func (h *WebHandler) HandleSaveContextKey(w http.ResponseWriter, r *http.Request) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	key, err := parseContextKeyForm(r.PostForm)
+	if err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Invalid key", Body: err.Error()})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.SetContextKey(r.Context(), key); err != nil {
+		log.Printf("ERROR saving context key '%s': %v", key.Name, err)
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Save failed", Body: "Could not save the key. Check server logs."})
+			return
+		}
+		http.Error(w, "Failed to save context key", http.StatusInternalServerError)
+		return
+	}
+
+	if htmx {
+		w.Header().Set("HX-Redirect", "/context-schema")
+		h.writeToast(w, http.StatusOK, toastData{Title: "Key saved", Body: fmt.Sprintf("%q is declared as %s.", key.Name, key.Type)})
+		return
+	}
+	http.Redirect(w, r, "/context-schema", http.StatusSeeOther)
+}
+
+// HandleDeleteContextKey removes a key from the context schema.
+func (h *WebHandler) HandleDeleteContextKey(w http.ResponseWriter, r *http.Request) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	name := r.FormValue("name")
+	if err := h.client.DeleteContextKey(r.Context(), name); err != nil {
+		log.Printf("ERROR deleting context key '%s': %v", name, err)
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Delete failed", Body: "Could not delete the key."})
+			return
+		}
+		http.Error(w, "Failed to delete context key", http.StatusInternalServerError)
+		return
+	}
+
+	if htmx {
+		w.Header().Set("HX-Redirect", "/context-schema")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, "/context-schema", http.StatusSeeOther)
+}
@@ -0,0 +1,102 @@
+package editor
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func TestParseContextKeyForm(t *testing.T) {
+	key, err := parseContextKeyForm(url.Values{
+		"name":        {" user_id "},
+		"type":        {"string"},
+		"description": {"The signed-in user"},
+	})
+	if err != nil {
+		t.Fatalf("parseContextKeyForm: %v", err)
+	}
+	if key.Name != "user_id" || key.Type != contextschema.TypeString || key.Description != "The signed-in user" {
+		t.Errorf("unexpected key: %+v", key)
+	}
+
+	if _, err := parseContextKeyForm(url.Values{"name": {"age"}, "type": {"integer"}}); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+}
+
+func TestContextSchemaPage(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	schema := contextschema.Schema{
+		{Name: "age", Type: contextschema.TypeNumber},
+		{Name: "user_id", Type: contextschema.TypeString, Description: "The signed-in user"},
+	}
+	flags := map[string]flag.Definition{
+		"checkout": {FlagName: "checkout", Rules: []rule.ConcreteRule{
+			{ExactMatchRule: &rule.ExactMatchRule{Key: "user_id"}},
+		}},
+		"legacy": {FlagName: "legacy", Environments: map[string]flag.Environment{
+			"prod": {Rules: []rule.ConcreteRule{{PrefixRule: &rule.PrefixRule{Key: "userId"}}}},
+		}},
+	}
+	seen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	observations := []contextschema.Observation{
+		{Key: "age", Type: contextschema.TypeString, Count: 4, LastSeen: seen},
+		{Key: "user_id", Type: contextschema.TypeString, Count: 90, LastSeen: seen},
+		{Key: "userId", Type: contextschema.TypeString, Count: 10, LastSeen: seen},
+	}
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Keys":       buildContextKeyViews(schema, flags),
+		"Types":      contextschema.Types,
+		"FlagIssues": buildFlagIssueViews(schema, flags),
+		"Observed":   buildObservedKeyViews(schema, observations),
+	}
+	if err := h.templates["contextschema"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering context schema page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		"The signed-in user",
+		`<a href="/edit/checkout" class="chip chip--mono">checkout</a>`,
+		`<option value="time">time</option>`,
+		`<a href="/edit/legacy" class="input--mono">legacy</a>`,
+		"rule #1 prefixRule in prod reads unknown key &#39;userId&#39;, did you mean &#39;user_id&#39;?",
+		"declared as number",
+		"not declared, did you mean user_id?",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected context schema page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+	if strings.Count(got, "chip--danger") != 2 {
+		t.Errorf("expected only the mistyped and unknown observations to be flagged")
+	}
+}
+
+func TestEditPageSuggestsContextKeys(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Flag":                 &flag.Definition{FlagName: "checkout", Revision: 2},
+		"RulesJSON":            "[]",
+		"DefaultValueJSON":     `""`,
+		"ContextKeyFieldsJSON": `[]`,
+		"ContextSchema":        contextschema.Schema{{Name: "user_id", Type: contextschema.TypeString, Description: "The signed-in user"}},
+		"TestResult":           testResultData{},
+	}
+	if err := h.templates["edit"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering edit page: %v", err)
+	}
+	if want := `<option value="user_id">string · The signed-in user</option>`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected edit page to contain %q", want)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
 	"github.com/zackarysantana/mongo-openfeature-go/src/client"
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
 	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
@@ -23,12 +26,26 @@ type WebHandler struct {
 	templates  map[string]*template.Template
 	toast      *template.Template
 	testResult *template.Template
+	conflict   *template.Template
+
+	// environments are the configured environment names, in promotion order.
+	environments []string
+	// userHeader is the request header an authenticating proxy sets to the
+	// signed-in user. Empty when the editor is not behind one.
+	userHeader string
 }
 
 func NewWebHandler(c *client.Client) *WebHandler {
 	templates := make(map[string]*template.Template)
 	layout := template.Must(template.ParseFiles("internal/editor/layout.tmpl"))
 	templates["index"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/index.tmpl"))
+	templates["history"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/history.tmpl"))
+	templates["audit"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/audit.tmpl"))
+	templates["promote"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/promote.tmpl"))
+	templates["webhooks"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/webhooks.tmpl"))
+	templates["experiments"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/experiments.tmpl"))
+	templates["stale"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/stale.tmpl"))
+	templates["contextschema"] = template.Must(template.Must(layout.Clone()).ParseFiles("internal/editor/contextschema.tmpl"))
 	// The edit page renders the test-result partial as a placeholder for the
 	// inline tester, so parse it into the same tree.
 	templates["edit"] = template.Must(template.Must(layout.Clone()).ParseFiles(
@@ -37,11 +54,13 @@ func NewWebHandler(c *client.Client) *WebHandler {
 	))
 	toast := template.Must(template.ParseFiles("internal/editor/_toast.tmpl"))
 	testResult := template.Must(template.ParseFiles("internal/editor/_test_result.tmpl"))
+	conflict := template.Must(template.ParseFiles("internal/editor/_conflict.tmpl"))
 	return &WebHandler{
 		client:     c,
 		templates:  templates,
 		toast:      toast,
 		testResult: testResult,
+		conflict:   conflict,
 	}
 }
 
@@ -81,8 +100,16 @@ func (h *WebHandler) HandleListFlags(w http.ResponseWriter, r *http.Request) {
 		flagNames = append(flagNames, f.FlagName)
 	}
 	flagNamesJSON, _ := json.Marshal(flagNames)
+	sections := BuildFlagListSections(flags)
+	now := time.Now()
+	flagUsage, err := h.client.FlagUsage(r.Context(), now.Add(-usageWindow))
+	if err != nil {
+		// The list is still useful without usage.
+		log.Printf("ERROR fetching flag usage: %v", err)
+	}
+	applyUsage(sections, flagUsage, now)
 	data := map[string]any{
-		"FlagSections":  BuildFlagListSections(flags),
+		"FlagSections":  sections,
 		"HasFlags":      len(flags) > 0,
 		"FlagNamesJSON": string(flagNamesJSON),
 	}
@@ -108,21 +135,47 @@ func (h *WebHandler) HandleEditFlag(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	rulesJSON, _ := json.MarshalIndent(def.Rules, "", "  ")
-	defaultValueJSON, _ := json.Marshal(def.DefaultValue)
-	contextKeyFieldsJSON, _ := json.Marshal(rule.CollectContextKeyFields(def.Rules))
+	// The form edits a single environment; the others are kept as they are
+	// when it is saved.
+	environment := r.URL.Query().Get("env")
+	view := def.ForEnvironment(environment)
+
+	rulesJSON, _ := json.MarshalIndent(view.Rules, "", "  ")
+	defaultValueJSON, _ := json.Marshal(view.DefaultValue)
+	contextKeyFields := rule.CollectContextKeyFields(view.Rules)
+	contextKeyFieldsJSON, _ := json.Marshal(contextKeyFields)
+	testerFields, targetingKeyField := splitTargetingKeyField(contextKeyFields)
 
 	if string(defaultValueJSON) == "null" {
 		defaultValueJSON = []byte(`""`)
 	}
 
+	schema, err := h.client.GetContextSchema(r.Context())
+	if err != nil {
+		// Keys are only suggested from the schema, so the page works without it.
+		log.Printf("ERROR getting context schema: %v", err)
+	}
+	references, err := h.client.ListCodeReferences(r.Context(), flagName)
+	if err != nil {
+		// The page is still useful without the references.
+		log.Printf("ERROR listing code references of '%s': %v", flagName, err)
+	}
+
 	viewData := map[string]any{
-		"Flag":                 def,
+		"Flag":                 &view,
+		"Environment":          environment,
+		"EnvironmentLabel":     environmentLabel(environment),
+		"Environments":         h.environmentOptions(def, environment),
+		"InheritsBase":         environment != "" && !def.HasEnvironment(environment),
+		"NextEnvironment":      h.nextEnvironment(environment),
 		"Categories":           h.listCategories(r.Context()),
 		"RulesJSON":            string(rulesJSON),
 		"DefaultValueJSON":     string(defaultValueJSON),
-		"ContextKeyFields":     rule.CollectContextKeyFields(def.Rules),
+		"ContextKeyFields":     testerFields,
 		"ContextKeyFieldsJSON": string(contextKeyFieldsJSON),
+		"TargetingKeyField":    targetingKeyField,
+		"CodeReferences":       references,
+		"ContextSchema":        schema,
 		// Pre-render the tester output region with an empty placeholder so the
 		// layout reserves space on first paint and doesn't shift after Run test.
 		"TestResult": testResultData{},
@@ -130,6 +183,21 @@ func (h *WebHandler) HandleEditFlag(w http.ResponseWriter, r *http.Request) {
 	h.renderTemplate(w, "edit", viewData)
 }
 
+// splitTargetingKeyField separates the targeting key, which has its own field
+// in the tester, from the other context keys the rules read.
+func splitTargetingKeyField(fields []rule.ContextKeyField) ([]rule.ContextKeyField, *rule.ContextKeyField) {
+	var targetingKey *rule.ContextKeyField
+	rest := make([]rule.ContextKeyField, 0, len(fields))
+	for _, field := range fields {
+		if field.Key == rule.TargetingKey {
+			targetingKey = &field
+			continue
+		}
+		rest = append(rest, field)
+	}
+	return rest, targetingKey
+}
+
 // HandleSaveFlag processes the form submission from the edit page.
 // htmx requests get a toast partial back; classic form posts redirect to "/".
 func (h *WebHandler) HandleSaveFlag(w http.ResponseWriter, r *http.Request) {
@@ -164,16 +232,55 @@ func (h *WebHandler) HandleSaveFlag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The revision the page was loaded at; empty for new flags. Saving is
+	// rejected if someone else has written the flag since.
+	var revision int64
+	if raw := r.FormValue("revision"); raw != "" {
+		var err error
+		if revision, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			if htmx {
+				h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Invalid revision."})
+				return
+			}
+			http.Error(w, "Invalid revision", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The form holds one environment's configuration, so it is merged into
+	// the stored flag to keep the other environments.
 	flagName := r.FormValue("flagName")
-	def := flag.Definition{
-		FlagName:       flagName,
-		DefaultVariant: r.FormValue("defaultVariant"),
+	def := flag.Definition{FlagName: flagName}
+	if current, err := h.client.GetFlag(r.Context(), flagName); err == nil {
+		def = *current
+	}
+	def.Category = strings.TrimSpace(r.FormValue("category"))
+	def.Revision = revision
+	environment := r.FormValue("environment")
+	def.SetEnvironment(environment, flag.Environment{
 		DefaultValue:   defaultValue,
-		Category:       strings.TrimSpace(r.FormValue("category")),
+		DefaultVariant: r.FormValue("defaultVariant"),
 		Rules:          rules,
-	}
+	})
 
-	if err := h.client.SetFlag(r.Context(), def); err != nil {
+	if err := h.client.SetFlag(client.WithActor(r.Context(), h.requestActor(r, client.ActorSourceEditor)), def); err != nil {
+		if errors.Is(err, mongoopenfeature.ErrRevisionConflict) {
+			if htmx {
+				h.writeConflict(w, r, def)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		var schemaErr *client.ContextSchemaError
+		if errors.As(err, &schemaErr) {
+			if htmx {
+				h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Rules do not match the context schema", Body: contextSchemaIssues(schemaErr)})
+				return
+			}
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
 		log.Printf("ERROR saving flag: %v", err)
 		if htmx {
 			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Save failed", Body: "Could not save the flag. Check server logs."})
@@ -186,6 +293,10 @@ func (h *WebHandler) HandleSaveFlag(w http.ResponseWriter, r *http.Request) {
 	if htmx {
 		if r.FormValue("afterSave") == "list" {
 			w.Header().Set("HX-Redirect", "/")
+		} else if saved, err := h.client.GetFlag(r.Context(), flagName); err == nil {
+			// Keep the page's revision in step so the next save from the
+			// same page isn't reported as a conflict.
+			w.Header().Set("HX-Trigger", fmt.Sprintf(`{"flagSaved":{"revision":%d}}`, saved.Revision))
 		}
 		h.writeToast(w, http.StatusOK, toastData{
 			Title: "Flag saved",
@@ -197,6 +308,40 @@ func (h *WebHandler) HandleSaveFlag(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
+// conflictData is the payload rendered by the _conflict.tmpl partial.
+type conflictData struct {
+	FlagName string
+	// Revision is the flag's current revision, or 0 if it was deleted.
+	Revision int64
+	// Changes lists what saving the draft would change in the current flag.
+	Changes []string
+	// ReloadURL opens the latest version in the environment being edited.
+	ReloadURL string
+}
+
+// writeConflict renders the conflict dialog into the edit page in place of
+// the usual toast, showing how the draft differs from the latest version.
+func (h *WebHandler) writeConflict(w http.ResponseWriter, r *http.Request, draft flag.Definition) {
+	data := conflictData{FlagName: draft.FlagName, ReloadURL: editURL(draft.FlagName, r.FormValue("environment"))}
+	current, err := h.client.GetFlag(r.Context(), draft.FlagName)
+	if err != nil {
+		current = nil
+	} else {
+		data.Revision = current.Revision
+	}
+	for _, change := range flag.Diff(current, &draft) {
+		data.Changes = append(data.Changes, change.String())
+	}
+
+	w.Header().Set("HX-Retarget", "#conflict-region")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := h.conflict.ExecuteTemplate(w, "conflict", data); err != nil {
+		log.Printf("ERROR rendering conflict: %v", err)
+	}
+}
+
 // HandleDeleteFlag processes the delete request.
 // htmx requests get an empty body + HX-Trigger toast event; classic form posts redirect to "/".
 func (h *WebHandler) HandleDeleteFlag(w http.ResponseWriter, r *http.Request) {
@@ -221,7 +366,7 @@ func (h *WebHandler) HandleDeleteFlag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.client.DeleteFlag(r.Context(), flagName); err != nil {
+	if err := h.client.DeleteFlag(client.WithActor(r.Context(), h.requestActor(r, client.ActorSourceEditor)), flagName); err != nil {
 		log.Printf("ERROR deleting flag: %v", err)
 		if htmx {
 			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Delete failed", Body: "Could not delete the flag."})
@@ -249,6 +394,37 @@ func (h *WebHandler) HandleDeleteFlag(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
+// requestActor identifies who made an editor request, for the audit log and
+// version history. The editor has no accounts of its own, so when the
+// operator names a user header set by an authenticating proxy in front of it,
+// the user and the forwarded client address are taken from the request.
+// Otherwise any client could set those headers, so the actor is anonymous and
+// the address is the connection's.
+func (h *WebHandler) requestActor(r *http.Request, source client.ActorSource) client.Actor {
+	actor := client.Actor{Name: "anonymous", Source: source, Address: remoteHost(r)}
+	if h.userHeader == "" {
+		return actor
+	}
+	if user := strings.TrimSpace(r.Header.Get(h.userHeader)); user != "" {
+		actor.Name = user
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if first = strings.TrimSpace(first); first != "" {
+			actor.Address = first
+		}
+	}
+	return actor
+}
+
+// remoteHost returns the host of the connection the request came in on.
+func remoteHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
 // containsEditPath reports whether the given URL path includes the edit prefix.
 func containsEditPath(url string) bool {
 	return strings.Contains(url, "/edit")
@@ -318,11 +494,6 @@ func (h *WebHandler) HandleEvaluateFlag(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Date/cron/etc. rules expect time.Time, but JSON only carries strings.
-	// Best-effort: any string that parses as RFC3339 is upgraded to time.Time
-	// so time-based rules behave the same way they would in real Go callers.
-	convertTimestamps(ctx)
-
 	match := def.EvaluateWithMatch(ctx)
 
 	valueJSON, marshalErr := json.MarshalIndent(match.Value, "", "  ")
@@ -361,7 +532,8 @@ func (h *WebHandler) loadFlagForEvaluation(r *http.Request, flagName string) (*f
 		if err != nil {
 			return nil, fmt.Errorf("flag not found: %s", flagName)
 		}
-		return def, nil
+		resolved := def.ForEnvironment(r.FormValue("environment"))
+		return &resolved, nil
 	case "draft":
 		return parseDraftDefinition(r, flagName)
 	default:
@@ -416,39 +588,6 @@ func (h *WebHandler) writeTestResult(w http.ResponseWriter, result testResultDat
 	}
 }
 
-// convertTimestamps walks a decoded JSON map and replaces any string that parses
-// as RFC3339 with the corresponding time.Time. This lets date-based rules
-// (DateTimeRule, CronRule) be tested through the JSON context input.
-func convertTimestamps(m map[string]any) {
-	for k, v := range m {
-		switch val := v.(type) {
-		case string:
-			if t, err := time.Parse(time.RFC3339, val); err == nil {
-				m[k] = t
-			}
-		case map[string]any:
-			convertTimestamps(val)
-		case []any:
-			convertTimestampsSlice(val)
-		}
-	}
-}
-
-func convertTimestampsSlice(s []any) {
-	for i, v := range s {
-		switch val := v.(type) {
-		case string:
-			if t, err := time.Parse(time.RFC3339, val); err == nil {
-				s[i] = t
-			}
-		case map[string]any:
-			convertTimestamps(val)
-		case []any:
-			convertTimestampsSlice(val)
-		}
-	}
-}
-
 // listCategories returns sorted category names from all saved flags.
 func (h *WebHandler) listCategories(ctx context.Context) []string {
 	if h.client == nil {
@@ -69,6 +69,21 @@
     ];
 
     const COMPOSITE_TYPES = new Set(["andRule", "orRule", "notRule"]);
+    // Rule fields holding a context key, which suggest the declared keys.
+    const CONTEXT_KEY_FIELDS = ["Key", "LatKey", "LngKey"];
+    // The context key the OpenFeature targeting key is flattened into, and
+    // the rules that read it when their Key is empty.
+    const TARGETING_KEY = "targetingKey";
+    const TARGETING_KEY_RULES = new Set([
+        "exactMatchRule",
+        "regexRule",
+        "existsRule",
+        "fractionalRule",
+        "inListRule",
+        "prefixRule",
+        "suffixRule",
+        "containsRule",
+    ]);
     const DOC_BASE =
         "https://github.com/ZackarySantana/mongo-openfeature-go?tab=readme-ov-file";
 
@@ -157,7 +172,8 @@
                     evt.preventDefault();
                     evt.stopImmediatePropagation();
                     btn.dataset.confirm = "armed";
-                    btn.textContent = "Click again to delete";
+                    btn.textContent =
+                        btn.dataset.confirmText || "Click again to delete";
                     armTimer = setTimeout(disarm, 3000);
                     return;
                 }
@@ -675,16 +691,19 @@
         const emptyEl = card.querySelector("[data-tester-empty]");
         const subtitleEl = card.querySelector("[data-tester-subtitle]");
         const hintEl = card.querySelector("[data-tester-hint]");
+        const targetingRefsHost = card.querySelector(
+            "[data-tester-targeting-refs]",
+        );
         if (!rowsHost) return;
 
         const copy = {
             saved: {
                 subtitle: "Uses the version stored on the server",
-                empty: "No context keys in the saved rules for this flag. Run test to evaluate with an empty context.",
+                empty: "No other context keys in the saved rules for this flag. Run test to evaluate with only the targeting key.",
             },
             draft: {
                 subtitle: "Uses your unsaved changes from the editor",
-                empty: "No context keys in your current rule edits. Run test to evaluate with an empty context.",
+                empty: "No other context keys in your current rule edits. Run test to evaluate with only the targeting key.",
             },
         };
 
@@ -698,9 +717,46 @@
             return values;
         }
 
+        function renderContextRefs(field) {
+            const refs = document.createElement("ul");
+            refs.className = "tester-field__refs";
+            refs.setAttribute("aria-label", "Rules that use " + field.key);
+            field.rules.forEach(function (ref) {
+                const li = document.createElement("li");
+                const link = document.createElement("a");
+                link.href = "#";
+                link.className = "tester-field__ref";
+                link.setAttribute("data-rule-link", "");
+                link.setAttribute("data-rule-index", String(ref.topLevelIndex));
+                link.title = "Scroll to this rule";
+                link.textContent = ref.label;
+                li.appendChild(link);
+                refs.appendChild(li);
+            });
+            return refs;
+        }
+
+        // The targeting key has its own field above the rows, so it is
+        // listed there with the rules that read it.
+        function renderTargetingKeyRefs(fields) {
+            if (!targetingRefsHost) return;
+            targetingRefsHost.innerHTML = "";
+            const field = fields.find(function (f) {
+                return f.key === TARGETING_KEY;
+            });
+            if (field && field.rules && field.rules.length) {
+                targetingRefsHost.appendChild(renderContextRefs(field));
+                wireTestResultLinks(targetingRefsHost);
+            }
+        }
+
         function renderContextRows(fields) {
             const values = collectContextValues();
             rowsHost.innerHTML = "";
+            renderTargetingKeyRefs(fields);
+            fields = fields.filter(function (f) {
+                return f.key !== TARGETING_KEY;
+            });
 
             if (!fields.length) {
                 rowsHost.hidden = true;
@@ -739,28 +795,7 @@
                 row.appendChild(input);
 
                 if (field.rules && field.rules.length) {
-                    const refs = document.createElement("ul");
-                    refs.className = "tester-field__refs";
-                    refs.setAttribute(
-                        "aria-label",
-                        "Rules that use " + field.key,
-                    );
-                    field.rules.forEach(function (ref) {
-                        const li = document.createElement("li");
-                        const link = document.createElement("a");
-                        link.href = "#";
-                        link.className = "tester-field__ref";
-                        link.setAttribute("data-rule-link", "");
-                        link.setAttribute(
-                            "data-rule-index",
-                            String(ref.topLevelIndex),
-                        );
-                        link.title = "Scroll to this rule";
-                        link.textContent = ref.label;
-                        li.appendChild(link);
-                        refs.appendChild(li);
-                    });
-                    row.appendChild(refs);
+                    row.appendChild(renderContextRefs(field));
                 }
 
                 rowsHost.appendChild(row);
@@ -776,8 +811,8 @@
             if (hintEl) {
                 hintEl.innerHTML =
                     source === "saved"
-                        ? "Context keys come from the saved rules. Only fields you fill in are sent. Leave a value empty to simulate that key missing from the context. Values are parsed as JSON when possible (<code>42</code>, <code>true</code>, <code>[1,2]</code>). RFC3339 strings become timestamps."
-                        : "Context keys come from your current rule edits. Only fields you fill in are sent. Leave a value empty to simulate that key missing from the context. Values are parsed as JSON when possible (<code>42</code>, <code>true</code>, <code>[1,2]</code>). RFC3339 strings become timestamps.";
+                        ? "Context keys come from the saved rules. Only fields you fill in are sent. Leave a value empty to simulate that key missing from the context. Values are parsed as JSON when possible (<code>42</code>, <code>true</code>, <code>[1,2]</code>). Times can be RFC3339 strings or Unix epoch numbers."
+                        : "Context keys come from your current rule edits. Only fields you fill in are sent. Leave a value empty to simulate that key missing from the context. Values are parsed as JSON when possible (<code>42</code>, <code>true</code>, <code>[1,2]</code>). Times can be RFC3339 strings or Unix epoch numbers.";
             }
 
             const fields =
@@ -843,9 +878,14 @@
         });
 
         // Enter inside a value field is a power-user shortcut for "Run test".
-        rowsHost.addEventListener("keydown", function (evt) {
+        card.addEventListener("keydown", function (evt) {
             if (evt.key !== "Enter") return;
-            if (!evt.target.closest("[data-tester-value]")) return;
+            if (
+                !evt.target.closest(
+                    "[data-tester-value], [data-tester-targeting-key]",
+                )
+            )
+                return;
             evt.preventDefault();
             const runBtn = card.querySelector(".tester-run");
             if (runBtn) runBtn.click();
@@ -903,14 +943,19 @@
     // only rows with a non-empty value are included in the context object.
     // Values are parsed as JSON when possible (so `42`, `true`, `[1,2]` all
     // become their typed values) and otherwise treated as plain strings.
-    // RFC3339 strings get upgraded to time.Time by the server so date/cron
-    // rules work. Exposed on `window` so it's reachable from htmx's
-    // `hx-vals='js:...'` attribute.
+    // The targeting key field is always sent as a string. Exposed on
+    // `window` so it's reachable from htmx's `hx-vals='js:...'` attribute.
     window.buildTesterContext = function buildTesterContext() {
         const rows = document.querySelectorAll(
             ".tester-card [data-tester-row]",
         );
         const obj = {};
+        const targetingKeyEl = document.querySelector(
+            ".tester-card [data-tester-targeting-key]",
+        );
+        if (targetingKeyEl && targetingKeyEl.value.trim() !== "") {
+            obj[TARGETING_KEY] = targetingKeyEl.value.trim();
+        }
         rows.forEach(function (row) {
             const key = row.getAttribute("data-context-key");
             const valEl = row.querySelector("[data-tester-value]");
@@ -942,16 +987,11 @@
 
     function directContextKeys(ruleTypeKey, rule) {
         if (!rule) return [];
+        if (TARGETING_KEY_RULES.has(ruleTypeKey)) {
+            return [rule.Key || TARGETING_KEY];
+        }
         switch (ruleTypeKey) {
-            case "exactMatchRule":
-            case "regexRule":
-            case "existsRule":
-            case "fractionalRule":
             case "rangeRule":
-            case "inListRule":
-            case "prefixRule":
-            case "suffixRule":
-            case "containsRule":
             case "ipRangeRule":
             case "dateTimeRule":
             case "semVerRule":
@@ -1359,6 +1399,11 @@
             input.type = "text";
             input.value = obj[key] != null ? obj[key] : "";
             if (opts.placeholder) input.placeholder = opts.placeholder;
+            if (CONTEXT_KEY_FIELDS.indexOf(key) !== -1) {
+                // Suggest the keys declared in the context schema.
+                input.setAttribute("list", "context-key-options");
+                input.autocomplete = "off";
+            }
             input.addEventListener("input", function () {
                 obj[key] = input.value;
                 notifyChange();
@@ -1375,6 +1420,15 @@
             return wrap;
         }
 
+        // targetingKeyField is the Key field of a rule that reads the
+        // targeting key when Key is left empty.
+        function targetingKeyField(rule) {
+            return textField("Key", rule, "Key", {
+                placeholder: TARGETING_KEY,
+                hint: "Leave empty to use the targeting key.",
+            });
+        }
+
         function numberField(label, obj, key, opts) {
             opts = opts || {};
             const input = document.createElement("input");
@@ -1419,8 +1473,12 @@
             return wrap;
         }
 
+        // GO_ZERO_TIME is how a Go time.Time left unset is serialized. For
+        // date rules it means the bound is open, so it is shown as empty.
+        const GO_ZERO_TIME = "0001-01-01T00:00:00Z";
+
         function rfc3339ToDateTimeParts(value) {
-            if (value == null || value === "") {
+            if (value == null || value === "" || value === GO_ZERO_TIME) {
                 return { date: "", time: "" };
             }
             const d = new Date(String(value));
@@ -1468,7 +1526,7 @@
             const hint = document.createElement("span");
             hint.className = "field__hint";
             hint.textContent =
-                "UTC. Time is optional and defaults to midnight.";
+                "UTC. Time is optional and defaults to midnight. Leave the date empty for an open-ended range.";
 
             function syncFromObj() {
                 const parts = rfc3339ToDateTimeParts(obj[key]);
@@ -2546,18 +2604,18 @@
             // Per-type body fields
             switch (ruleTypeKey) {
                 case "exactMatchRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(textField("KeyValue", rule, "KeyValue"));
                     break;
                 case "regexRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(regexPatternField(rule));
                     break;
                 case "existsRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     break;
                 case "fractionalRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(
                         percentageField("Percentage", rule, "Percentage"),
                     );
@@ -2567,7 +2625,7 @@
                     body.appendChild(rangeField(rule));
                     break;
                 case "inListRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(
                         listField("Items", rule, "Items", {
                             parseItem: true,
@@ -2577,15 +2635,15 @@
                     );
                     break;
                 case "prefixRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(textField("Prefix", rule, "Prefix"));
                     break;
                 case "suffixRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(textField("Suffix", rule, "Suffix"));
                     break;
                 case "containsRule":
-                    body.appendChild(textField("Key", rule, "Key"));
+                    body.appendChild(targetingKeyField(rule));
                     body.appendChild(textField("Substring", rule, "Substring"));
                     break;
                 case "ipRangeRule":
@@ -2993,6 +3051,48 @@
         }
     }
 
+    /* ============================================================
+       Edit page: revision tracking and save conflicts
+       ============================================================ */
+
+    function setupRevisionTracking() {
+        const input = document.getElementById("revision");
+        if (!input) return;
+        document.body.addEventListener("flagSaved", function (evt) {
+            if (evt.detail && evt.detail.revision != null) {
+                input.value = evt.detail.revision;
+            }
+        });
+    }
+
+    function openConflictDialog(root) {
+        const dialog = root.querySelector("[data-conflict-dialog]");
+        if (!dialog) return;
+        const form = document.querySelector("form[data-flag-form]");
+        const revision = document.getElementById("revision");
+
+        function close() {
+            dialog.close();
+            dialog.remove();
+        }
+
+        dialog
+            .querySelector("[data-conflict-cancel]")
+            .addEventListener("click", close);
+        dialog
+            .querySelector("[data-conflict-overwrite]")
+            .addEventListener("click", function (evt) {
+                // Save again against the revision we were just shown.
+                if (revision) revision.value = evt.currentTarget.dataset.revision;
+                close();
+                if (form) form.requestSubmit();
+            });
+        dialog.addEventListener("click", function (evt) {
+            if (evt.target === dialog) close();
+        });
+        dialog.showModal();
+    }
+
     /* ============================================================
        Boot
        ============================================================ */
@@ -3007,6 +3107,7 @@
         setupFormDirtyGuard();
         setupRuleBuilder();
         setupTester();
+        setupRevisionTracking();
         wireTestResultLinks(document);
     }
 
@@ -3022,6 +3123,9 @@
             wireAllConfirmButtons(evt.detail.target);
             wireAllFlagRows(evt.detail.target);
             wireTestResultLinks(evt.detail.target);
+            if (evt.detail.target.id === "conflict-region") {
+                openConflictDialog(evt.detail.target);
+            }
         }
     });
 })();
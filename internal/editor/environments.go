@@ -0,0 +1,198 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// baseEnvironmentLabel is shown for the base definition, which every
+// environment without an override falls back to.
+const baseEnvironmentLabel = "default"
+
+// parseEnvironments splits a comma separated list of environment names, in
+// promotion order (e.g. "dev,staging,prod").
+func parseEnvironments(raw string) []string {
+	var environments []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(environments, name) {
+			environments = append(environments, name)
+		}
+	}
+	return environments
+}
+
+func environmentLabel(name string) string {
+	if name == "" {
+		return baseEnvironmentLabel
+	}
+	return name
+}
+
+// environmentOption is one entry in the environment switcher.
+type environmentOption struct {
+	Name  string
+	Label string
+	// Active marks the environment being edited.
+	Active bool
+	// Overridden reports whether the flag has its own configuration for the
+	// environment rather than falling back to the base definition.
+	Overridden bool
+}
+
+// flagEnvironments returns the environments to offer for def: the configured
+// ones in promotion order, then any others the flag overrides, sorted.
+func (h *WebHandler) flagEnvironments(def *flag.Definition) []string {
+	environments := slices.Clone(h.environments)
+	var extra []string
+	for name := range def.Environments {
+		if !slices.Contains(environments, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(environments, extra...)
+}
+
+// environmentOptions builds the switcher entries, starting with the base
+// definition.
+func (h *WebHandler) environmentOptions(def *flag.Definition, active string) []environmentOption {
+	options := []environmentOption{{Name: "", Label: baseEnvironmentLabel, Active: active == "", Overridden: true}}
+	for _, name := range h.flagEnvironments(def) {
+		options = append(options, environmentOption{
+			Name:       name,
+			Label:      name,
+			Active:     name == active,
+			Overridden: def.HasEnvironment(name),
+		})
+	}
+	return options
+}
+
+// nextEnvironment returns the environment after name in the configured
+// promotion order, or an empty string if there is none.
+func (h *WebHandler) nextEnvironment(name string) string {
+	i := slices.Index(h.environments, name)
+	if i < 0 || i+1 >= len(h.environments) {
+		return ""
+	}
+	return h.environments[i+1]
+}
+
+// editURL links to the edit page of a flag in an environment.
+func editURL(flagName, environment string) string {
+	u := "/edit/" + url.PathEscape(flagName)
+	if environment != "" {
+		u += "?env=" + url.QueryEscape(environment)
+	}
+	return u
+}
+
+// HandlePromotePreview shows what promoting a flag from one environment to
+// another would change, with a button to apply it.
+func (h *WebHandler) HandlePromotePreview(w http.ResponseWriter, r *http.Request) {
+	flagName := r.PathValue("name")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	def, err := h.client.GetFlag(r.Context(), flagName)
+	if err != nil {
+		log.Printf("ERROR loading flag '%s' for promotion: %v", flagName, err)
+		http.Error(w, "Flag not found", http.StatusNotFound)
+		return
+	}
+
+	var changes []string
+	if from != to {
+		promoted := def.Promote(from, to)
+		for _, change := range flag.Diff(def, &promoted) {
+			changes = append(changes, change.String())
+		}
+	}
+
+	var targets []environmentOption
+	for _, name := range append([]string{""}, h.flagEnvironments(def)...) {
+		if name != from {
+			targets = append(targets, environmentOption{Name: name, Label: environmentLabel(name), Active: name == to})
+		}
+	}
+
+	h.renderTemplate(w, "promote", map[string]any{
+		"FlagName":  flagName,
+		"From":      from,
+		"FromLabel": environmentLabel(from),
+		"To":        to,
+		"ToLabel":   environmentLabel(to),
+		"Targets":   targets,
+		"Revision":  def.Revision,
+		"Changes":   changes,
+		"SameEnv":   from == to,
+		"BackURL":   editURL(flagName, from),
+	})
+}
+
+// HandlePromoteFlag applies a promotion reviewed on the preview page.
+// htmx requests get a toast partial back; classic form posts redirect to the
+// edit page of the target environment.
+func (h *WebHandler) HandlePromoteFlag(w http.ResponseWriter, r *http.Request) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	flagName := r.FormValue("flagName")
+	from, to := r.FormValue("from"), r.FormValue("to")
+	revision, err := strconv.ParseInt(r.FormValue("revision"), 10, 64)
+	if flagName == "" || err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "A flag name and revision are required."})
+			return
+		}
+		http.Error(w, "Missing flag name or revision", http.StatusBadRequest)
+		return
+	}
+
+	ctx := client.WithActor(r.Context(), h.requestActor(r, client.ActorSourceEditor))
+	if _, err := h.client.PromoteFlag(ctx, flagName, from, to, revision); err != nil {
+		title, body := "Promotion failed", "Could not promote the flag. Check server logs."
+		if errors.Is(err, mongoopenfeature.ErrRevisionConflict) {
+			title, body = "Flag changed", "The flag was changed since this preview was loaded. Reload to review the new changes."
+		} else {
+			log.Printf("ERROR promoting flag '%s' from '%s' to '%s': %v", flagName, from, to, err)
+		}
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: title, Body: body})
+			return
+		}
+		http.Error(w, body, http.StatusConflict)
+		return
+	}
+
+	target := editURL(flagName, to)
+	if htmx {
+		w.Header().Set("HX-Redirect", target)
+		h.writeToast(w, http.StatusOK, toastData{
+			Title: "Flag promoted",
+			Body:  fmt.Sprintf("%q was promoted from %s to %s.", flagName, environmentLabel(from), environmentLabel(to)),
+		})
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
@@ -0,0 +1,149 @@
+package editor
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestParseEnvironments(t *testing.T) {
+	got := parseEnvironments(" dev, staging,,prod,dev ")
+	want := []string{"dev", "staging", "prod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseEnvironments = %v, want %v", got, want)
+	}
+	if got := parseEnvironments(""); len(got) != 0 {
+		t.Fatalf("parseEnvironments(\"\") = %v, want none", got)
+	}
+}
+
+func TestEnvironmentOptions(t *testing.T) {
+	h := NewWebHandler(nil)
+	h.environments = []string{"dev", "staging", "prod"}
+
+	def := &flag.Definition{
+		FlagName: "checkout",
+		Environments: map[string]flag.Environment{
+			"staging": {DefaultVariant: "on"},
+			"qa":      {DefaultVariant: "off"},
+		},
+	}
+
+	options := h.environmentOptions(def, "staging")
+	var names []string
+	for _, o := range options {
+		names = append(names, o.Name)
+	}
+	// Base first, then configured environments in order, then extras.
+	if want := []string{"", "dev", "staging", "prod", "qa"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("environment names = %q, want %q", names, want)
+	}
+	if options[0].Label != "default" || !options[0].Overridden {
+		t.Errorf("unexpected base option: %+v", options[0])
+	}
+	if !options[2].Active || !options[2].Overridden {
+		t.Errorf("expected staging to be active and overridden: %+v", options[2])
+	}
+	if options[1].Overridden || options[1].Active {
+		t.Errorf("expected dev to inherit and be inactive: %+v", options[1])
+	}
+
+	if got := h.nextEnvironment("dev"); got != "staging" {
+		t.Errorf("nextEnvironment(dev) = %q, want staging", got)
+	}
+	if got := h.nextEnvironment("prod"); got != "" {
+		t.Errorf("nextEnvironment(prod) = %q, want none", got)
+	}
+	if got := h.nextEnvironment("qa"); got != "" {
+		t.Errorf("nextEnvironment(qa) = %q, want none", got)
+	}
+}
+
+func TestPromotePage(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	render := func(data map[string]any) string {
+		var buf bytes.Buffer
+		if err := h.templates["promote"].ExecuteTemplate(&buf, "layout", data); err != nil {
+			t.Fatalf("rendering promote page: %v", err)
+		}
+		return buf.String()
+	}
+
+	got := render(map[string]any{
+		"FlagName":  "checkout",
+		"From":      "staging",
+		"FromLabel": "staging",
+		"To":        "prod",
+		"ToLabel":   "prod",
+		"Targets":   []environmentOption{{Name: "", Label: "default"}, {Name: "prod", Label: "prod", Active: true}},
+		"Revision":  int64(7),
+		"Changes":   []string{`+ Environments.prod: {"DefaultVariant":"on"}`},
+		"BackURL":   "/edit/checkout?env=staging",
+	})
+	for _, fragment := range []string{
+		"Promote checkout",
+		"Environments.prod",
+		`name="revision" value="7"`,
+		`hx-post="/promote"`,
+		`<option value="prod" selected>`,
+		"Promote to prod",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected promote page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+
+	unchanged := render(map[string]any{
+		"FlagName": "checkout", "From": "staging", "FromLabel": "staging", "To": "prod", "ToLabel": "prod",
+		"BackURL": "/edit/checkout?env=staging",
+	})
+	if strings.Contains(unchanged, `hx-post="/promote"`) {
+		t.Errorf("expected no promote button when nothing changes")
+	}
+	if !strings.Contains(unchanged, "nothing to promote") {
+		t.Errorf("expected nothing-to-promote message")
+	}
+}
+
+func TestEditPageEnvironmentSwitcher(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Flag":             &flag.Definition{FlagName: "checkout", Revision: 2},
+		"Environment":      "dev",
+		"EnvironmentLabel": "dev",
+		"Environments": []environmentOption{
+			{Name: "", Label: "default", Overridden: true},
+			{Name: "dev", Label: "dev", Active: true},
+			{Name: "prod", Label: "prod"},
+		},
+		"InheritsBase":         true,
+		"NextEnvironment":      "prod",
+		"RulesJSON":            "[]",
+		"DefaultValueJSON":     `""`,
+		"ContextKeyFieldsJSON": `[]`,
+		"TestResult":           testResultData{},
+	}
+	if err := h.templates["edit"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering edit page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		`class="env-switcher"`,
+		`href="/edit/checkout?env=prod"`,
+		`aria-current="page"`,
+		`name="environment" value="dev"`,
+		"Saving creates a dev override",
+		"Promote to prod",
+		`/promote/checkout?from=dev&amp;to=prod`,
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected edit page to contain %q", fragment)
+		}
+	}
+}
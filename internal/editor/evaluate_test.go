@@ -64,6 +64,58 @@ func TestHandleEvaluateFlagDraft(t *testing.T) {
 	}
 }
 
+// TestHandleEvaluateFlagDraftTimeContext checks time rules read RFC 3339
+// strings and Unix epoch numbers straight from the JSON context.
+func TestHandleEvaluateFlagDraftTimeContext(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	for _, context := range []string{`{"now":"2026-05-19T20:00:00Z"}`, `{"now":1779220800}`} {
+		form := url.Values{}
+		form.Set("source", "draft")
+		form.Set("context", context)
+		form.Set("rules", `[{"dateTimeRule":{"Key":"now","After":"2026-01-01T00:00:00Z","VariantID":"launched","ValueData":"true"}}]`)
+		form.Set("defaultVariant", "off")
+		form.Set("defaultValue", `"fallback"`)
+
+		req := httptest.NewRequest(http.MethodPost, "/test/my-flag", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("name", "my-flag")
+
+		rec := httptest.NewRecorder()
+		h.HandleEvaluateFlag(rec, req)
+
+		body := rec.Body.String()
+		if !strings.Contains(body, "test-out--matched") || !strings.Contains(body, "launched") {
+			t.Errorf("context %s: expected the open-ended date rule to match; got:\n%s", context, body)
+		}
+	}
+}
+
+// TestHandleEvaluateFlagDraftTargetingKey checks a fractional rule without a
+// key buckets on the targeting key sent by the tester.
+func TestHandleEvaluateFlagDraftTargetingKey(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	form := url.Values{}
+	form.Set("source", "draft")
+	form.Set("context", `{"targetingKey":"user-1"}`)
+	form.Set("rules", `[{"fractionalRule":{"Percentage":100,"VariantID":"rollout","ValueData":"true"}}]`)
+	form.Set("defaultVariant", "off")
+	form.Set("defaultValue", `"fallback"`)
+
+	req := httptest.NewRequest(http.MethodPost, "/test/my-flag", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("name", "my-flag")
+
+	rec := httptest.NewRecorder()
+	h.HandleEvaluateFlag(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "test-out--matched") || !strings.Contains(body, "rollout") {
+		t.Errorf("expected the fractional rule to bucket on the targeting key; got:\n%s", body)
+	}
+}
+
 func TestHandleEvaluateFlagDraftInvalidRules(t *testing.T) {
 	h := NewWebHandler(nil)
 
@@ -0,0 +1,133 @@
+package editor
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/experiment"
+)
+
+// experimentVariantView is a single row on the experiment page.
+type experimentVariantView struct {
+	Variant      string
+	Control      bool
+	Users        int64
+	Impressions  int64
+	Converters   int64
+	Rate         string
+	Interval     string
+	ValuePerUser string
+	Lift         string
+	PValue       string
+	Significant  bool
+	// Better is set when a significant lift is positive.
+	Better bool
+}
+
+func buildExperimentViews(results experiment.Results) []experimentVariantView {
+	views := make([]experimentVariantView, len(results.Variants))
+	for i, v := range results.Variants {
+		view := experimentVariantView{
+			Variant:      v.Variant,
+			Control:      v.Comparison == nil,
+			Users:        v.Users,
+			Impressions:  v.Impressions,
+			Converters:   v.Converters,
+			Rate:         percent(v.ConversionRate),
+			Interval:     percent(v.Low) + " – " + percent(v.High),
+			ValuePerUser: fmt.Sprintf("%.2f", v.ValuePerUser),
+		}
+		if c := v.Comparison; c != nil {
+			view.Lift = fmt.Sprintf("%+.1f%%", c.Lift*100)
+			view.PValue = fmt.Sprintf("%.3f", c.PValue)
+			view.Significant = c.Significant
+			view.Better = c.Significant && c.Lift > 0
+		}
+		views[i] = view
+	}
+	return views
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.1f%%", f*100)
+}
+
+// parseExperimentQuery reads the experiment filters from the query string.
+// Dates are whole days in the server's time zone, as on the audit log.
+func parseExperimentQuery(flagName string, values url.Values) (client.ExperimentQuery, error) {
+	query := client.ExperimentQuery{
+		FlagName:  flagName,
+		EventName: strings.TrimSpace(values.Get("event")),
+		Control:   strings.TrimSpace(values.Get("control")),
+	}
+	if since := values.Get("since"); since != "" {
+		t, err := time.ParseInLocation(auditDateLayout, since, time.Local)
+		if err != nil {
+			return query, err
+		}
+		query.Since = t
+	}
+	if until := values.Get("until"); until != "" {
+		t, err := time.ParseInLocation(auditDateLayout, until, time.Local)
+		if err != nil {
+			return query, err
+		}
+		query.Until = t.AddDate(0, 0, 1)
+	}
+	return query, nil
+}
+
+// HandleExperiment shows the results of the experiment run with a flag, for
+// the tracking event chosen in the query string (event, control, since,
+// until). The first event linked to the flag is shown when none is chosen.
+func (h *WebHandler) HandleExperiment(w http.ResponseWriter, r *http.Request) {
+	flagName := r.PathValue("name")
+	if flagName == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	values := r.URL.Query()
+	query, err := parseExperimentQuery(flagName, values)
+	if err != nil {
+		http.Error(w, "Invalid date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.client.TrackingEventNames(r.Context(), flagName)
+	if err != nil {
+		log.Printf("ERROR listing tracking events for '%s': %v", flagName, err)
+		http.Error(w, "Failed to load tracking events", http.StatusInternalServerError)
+		return
+	}
+	if query.EventName == "" && len(events) > 0 {
+		query.EventName = events[0]
+	}
+
+	data := map[string]any{
+		"FlagName": flagName,
+		"Events":   events,
+		"Filters": map[string]string{
+			"Event":   query.EventName,
+			"Control": values.Get("control"),
+			"Since":   values.Get("since"),
+			"Until":   values.Get("until"),
+		},
+	}
+	if query.EventName != "" {
+		results, err := h.client.ExperimentResults(r.Context(), query)
+		if err != nil {
+			log.Printf("ERROR analysing experiment '%s': %v", flagName, err)
+			http.Error(w, "Failed to analyse experiment", http.StatusInternalServerError)
+			return
+		}
+		data["Control"] = results.Control
+		data["Variants"] = buildExperimentViews(results.Results)
+		data["SRM"] = results.SRM
+	}
+	h.renderTemplate(w, "experiments", data)
+}
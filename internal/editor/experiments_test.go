@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/experiment"
+)
+
+func TestParseExperimentQuery(t *testing.T) {
+	query, err := parseExperimentQuery("checkout", url.Values{
+		"event":   {" purchase "},
+		"control": {"off"},
+		"since":   {"2025-03-01"},
+		"until":   {"2025-03-02"},
+	})
+	if err != nil {
+		t.Fatalf("parseExperimentQuery: %v", err)
+	}
+	if query.FlagName != "checkout" || query.EventName != "purchase" || query.Control != "off" {
+		t.Fatalf("unexpected filters: %+v", query)
+	}
+	if want := time.Date(2025, 3, 3, 0, 0, 0, 0, time.Local); !query.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", query.Until, want)
+	}
+
+	if _, err := parseExperimentQuery("checkout", url.Values{"until": {"tomorrow"}}); err == nil {
+		t.Errorf("expected an error for an invalid date")
+	}
+}
+
+func TestBuildExperimentViews(t *testing.T) {
+	results := experiment.Analyze([]experiment.VariantCounts{
+		{Variant: "control", Users: 1000, Impressions: 1200, Converters: 100, Value: 500},
+		{Variant: "treatment", Users: 1000, Impressions: 1100, Converters: 130, Value: 650},
+	}, "", nil)
+
+	views := buildExperimentViews(results)
+	if len(views) != 2 {
+		t.Fatalf("len(views) = %d, want 2", len(views))
+	}
+	control, treatment := views[0], views[1]
+	if !control.Control || control.Lift != "" || control.Rate != "10.0%" {
+		t.Errorf("unexpected control row: %+v", control)
+	}
+	if treatment.Control || treatment.Lift != "+30.0%" || !treatment.Significant || !treatment.Better {
+		t.Errorf("unexpected treatment row: %+v", treatment)
+	}
+	if treatment.ValuePerUser != "0.65" {
+		t.Errorf("ValuePerUser = %q, want 0.65", treatment.ValuePerUser)
+	}
+}
+
+// TestExperimentPage verifies the results table and the sample ratio
+// mismatch warning render.
+func TestExperimentPage(t *testing.T) {
+	h := NewWebHandler(nil)
+	results := experiment.Analyze([]experiment.VariantCounts{
+		{Variant: "off", Users: 5000, Converters: 400},
+		{Variant: "on", Users: 5400, Converters: 480},
+	}, "off", nil)
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"FlagName": "checkout",
+		"Events":   []string{"purchase", "signup"},
+		"Filters":  map[string]string{"Event": "purchase"},
+		"Control":  results.Control,
+		"Variants": buildExperimentViews(results),
+		"SRM":      results.SRM,
+	}
+	if err := h.templates["experiments"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering experiment page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		"Experiment on checkout",
+		`value="purchase"`,
+		`<option value="signup">`,
+		"Sample ratio mismatch",
+		"8.0%",
+		"8.9%",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected experiment page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package editor
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+// versionView is a single row on the history page.
+type versionView struct {
+	Version   int
+	Operation string
+	Author    string
+	Timestamp string
+	Deleted   bool
+	Current   bool
+	Changes   []string
+}
+
+// buildVersionViews converts stored versions (newest first) into rows for the
+// history template. The newest version is marked current.
+func buildVersionViews(versions []client.Version) []versionView {
+	views := make([]versionView, len(versions))
+	for i, v := range versions {
+		author := v.Author
+		if author == "" {
+			author = "unknown"
+		}
+		changes := make([]string, len(v.Diff))
+		for j, c := range v.Diff {
+			changes[j] = c.String()
+		}
+		views[i] = versionView{
+			Version:   v.Version,
+			Operation: string(v.Operation),
+			Author:    author,
+			Timestamp: v.Timestamp.Local().Format(time.DateTime),
+			Deleted:   v.Definition == nil,
+			Current:   i == 0,
+			Changes:   changes,
+		}
+	}
+	return views
+}
+
+// HandleFlagHistory shows every recorded version of a flag with its diff.
+func (h *WebHandler) HandleFlagHistory(w http.ResponseWriter, r *http.Request) {
+	flagName := r.PathValue("name")
+	if flagName == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	versions, err := h.client.ListVersions(r.Context(), flagName)
+	if err != nil {
+		log.Printf("ERROR listing versions for '%s': %v", flagName, err)
+		http.Error(w, "Failed to load flag history", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, "history", map[string]any{
+		"FlagName": flagName,
+		"Versions": buildVersionViews(versions),
+	})
+}
+
+// HandleRollbackFlag restores a flag to a previous version.
+// htmx requests get a toast partial back; classic form posts redirect to the edit page.
+func (h *WebHandler) HandleRollbackFlag(w http.ResponseWriter, r *http.Request) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	flagName := r.FormValue("flagName")
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if flagName == "" || err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "A flag name and version are required."})
+			return
+		}
+		http.Error(w, "Missing flag name or version", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.Rollback(client.WithActor(r.Context(), h.requestActor(r, client.ActorSourceEditor)), flagName, version); err != nil {
+		log.Printf("ERROR rolling back flag '%s' to version %d: %v", flagName, version, err)
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Rollback failed", Body: "Could not roll back the flag. Check server logs."})
+			return
+		}
+		http.Error(w, "Failed to roll back flag", http.StatusInternalServerError)
+		return
+	}
+
+	if htmx {
+		w.Header().Set("HX-Redirect", "/history/"+flagName)
+		h.writeToast(w, http.StatusOK, toastData{
+			Title: "Flag rolled back",
+			Body:  fmt.Sprintf("%q was restored to version %d.", flagName, version),
+		})
+		return
+	}
+
+	http.Redirect(w, r, "/history/"+flagName, http.StatusSeeOther)
+}
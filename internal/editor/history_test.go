@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestBuildVersionViews(t *testing.T) {
+	versions := []client.Version{
+		{
+			Version:   2,
+			Operation: client.OperationDelete,
+			Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Diff:      []flag.Change{{Kind: flag.ChangeRemoved, Path: "DefaultVariant", Old: "on"}},
+		},
+		{
+			Version:    1,
+			Operation:  client.OperationSet,
+			Author:     "alice",
+			Timestamp:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Definition: &flag.Definition{FlagName: "my-flag"},
+		},
+	}
+
+	views := buildVersionViews(versions)
+	if len(views) != 2 {
+		t.Fatalf("len(views) = %d, want 2", len(views))
+	}
+	if !views[0].Current || views[1].Current {
+		t.Fatalf("expected only the newest version to be current")
+	}
+	if !views[0].Deleted || views[1].Deleted {
+		t.Fatalf("expected only the delete version to be marked deleted")
+	}
+	if views[0].Author != "unknown" || views[1].Author != "alice" {
+		t.Fatalf("authors = %q, %q", views[0].Author, views[1].Author)
+	}
+	if len(views[0].Changes) != 1 || views[0].Changes[0] != `- DefaultVariant: "on"` {
+		t.Fatalf("changes = %v", views[0].Changes)
+	}
+}
+
+// TestHistoryPage verifies older versions get a rollback button and the
+// current one does not.
+func TestHistoryPage(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"FlagName": "my-flag",
+		"Versions": []versionView{
+			{Version: 2, Operation: "set", Author: "bob", Current: true, Changes: []string{`~ DefaultVariant: "off" -> "on"`}},
+			{Version: 1, Operation: "set", Author: "alice"},
+		},
+	}
+	if err := h.templates["history"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering history page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		"History of my-flag",
+		"Version 2",
+		"Version 1",
+		`hx-post="/rollback"`,
+		`"version": "1"`,
+		"DefaultVariant",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected history page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+	if strings.Count(got, `hx-post="/rollback"`) != 1 {
+		t.Errorf("expected exactly one rollback button")
+	}
+}
@@ -13,6 +13,8 @@ import (
 // run configures and starts the web application.
 func RunEditor(mongoClient *mongo.Client, ofClient *client.Client) error {
 	handler := NewWebHandler(ofClient)
+	handler.environments = parseEnvironments(os.Getenv("FLAG_ENVIRONMENTS"))
+	handler.userHeader = strings.TrimSpace(os.Getenv("EDITOR_USER_HEADER"))
 
 	mux := http.NewServeMux()
 
@@ -42,6 +44,20 @@ func RunEditor(mongoClient *mongo.Client, ofClient *client.Client) error {
 	mux.HandleFunc("POST /save", handler.HandleSaveFlag)
 	mux.HandleFunc("POST /delete", handler.HandleDeleteFlag)
 	mux.HandleFunc("POST /test/{name}", handler.HandleEvaluateFlag)
+	mux.HandleFunc("GET /history/{name}", handler.HandleFlagHistory)
+	mux.HandleFunc("POST /rollback", handler.HandleRollbackFlag)
+	mux.HandleFunc("GET /experiments/{name}", handler.HandleExperiment)
+	mux.HandleFunc("GET /audit", handler.HandleAuditLog)
+	mux.HandleFunc("GET /stale", handler.HandleStaleFlags)
+	mux.HandleFunc("GET /promote/{name}", handler.HandlePromotePreview)
+	mux.HandleFunc("POST /promote", handler.HandlePromoteFlag)
+	mux.HandleFunc("GET /webhooks", handler.HandleWebhooks)
+	mux.HandleFunc("POST /webhooks", handler.HandleSaveWebhook)
+	mux.HandleFunc("POST /webhooks/delete", handler.HandleDeleteWebhook)
+	mux.HandleFunc("POST /webhooks/dead-letters/delete", handler.HandleDeleteDeadLetter)
+	mux.HandleFunc("GET /context-schema", handler.HandleContextSchema)
+	mux.HandleFunc("POST /context-schema", handler.HandleSaveContextKey)
+	mux.HandleFunc("POST /context-schema/delete", handler.HandleDeleteContextKey)
 	mux.HandleFunc("GET /", handler.HandleListFlags)
 
 	port := ":3000"
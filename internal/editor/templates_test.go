@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
 	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
 )
@@ -22,11 +23,11 @@ func init() {
 // runtime when a request first arrives.
 func TestNewWebHandlerParsesTemplates(t *testing.T) {
 	h := NewWebHandler(nil) // client unused for template parsing.
-	if h.templates["index"] == nil || h.templates["edit"] == nil {
-		t.Fatalf("expected index and edit templates to be parsed")
+	if h.templates["index"] == nil || h.templates["edit"] == nil || h.templates["history"] == nil || h.templates["audit"] == nil || h.templates["promote"] == nil || h.templates["webhooks"] == nil || h.templates["experiments"] == nil || h.templates["stale"] == nil || h.templates["contextschema"] == nil {
+		t.Fatalf("expected index, edit, history, audit, promote, webhooks, experiments, stale and contextschema templates to be parsed")
 	}
-	if h.toast == nil || h.testResult == nil {
-		t.Fatalf("expected toast and testResult partials to be parsed")
+	if h.toast == nil || h.testResult == nil || h.conflict == nil {
+		t.Fatalf("expected toast, testResult and conflict partials to be parsed")
 	}
 }
 
@@ -120,7 +121,10 @@ func TestEditPageRendersTesterForSavedFlag(t *testing.T) {
 	render := func(name string, fields []rule.ContextKeyField) string {
 		var buf bytes.Buffer
 		data := map[string]any{
-			"Flag":                 struct{ FlagName, DefaultVariant, Category string }{name, "", ""},
+			"Flag": struct {
+				FlagName, DefaultVariant, Category, ManagedBy string
+				Revision                                      int64
+			}{name, "", "", "", 0},
 			"Categories":           []string{"Billing", "Growth"},
 			"RulesJSON":            "[]",
 			"DefaultValueJSON":     `""`,
@@ -202,11 +206,56 @@ func TestEditPageRendersTesterForSavedFlag(t *testing.T) {
 	}
 
 	noKeys := render("bare-flag", nil)
-	if !strings.Contains(noKeys, "No context keys in the saved rules") {
+	if !strings.Contains(noKeys, "No other context keys in the saved rules") {
 		t.Errorf("expected empty-state copy when flag has no context keys")
 	}
 }
 
+// TestEditPageRendersTargetingKeyField verifies the tester has a dedicated
+// targeting key field listing the rules that read it, instead of a row.
+func TestEditPageRendersTargetingKeyField(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	fields, targetingKey := splitTargetingKeyField([]rule.ContextKeyField{
+		{Key: "region", Rules: []rule.ContextKeyRef{{TopLevelIndex: 1, Label: "#2 prefixRule"}}},
+		{Key: rule.TargetingKey, Rules: []rule.ContextKeyRef{{TopLevelIndex: 0, Label: "#1 fractionalRule"}}},
+	})
+	if len(fields) != 1 || fields[0].Key != "region" {
+		t.Fatalf("expected only region to remain a row, got %+v", fields)
+	}
+	if targetingKey == nil || targetingKey.Key != rule.TargetingKey {
+		t.Fatalf("expected the targeting key field to be split out, got %+v", targetingKey)
+	}
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Flag": struct {
+			FlagName, DefaultVariant, Category, ManagedBy string
+			Revision                                      int64
+		}{"my-flag", "", "", "", 0},
+		"RulesJSON":            "[]",
+		"DefaultValueJSON":     `""`,
+		"ContextKeyFields":     fields,
+		"ContextKeyFieldsJSON": `[]`,
+		"TargetingKeyField":    targetingKey,
+		"TestResult":           testResultData{},
+	}
+	if err := h.templates["edit"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering edit page: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.Contains(got, "data-tester-targeting-key") {
+		t.Errorf("expected a dedicated targeting key field")
+	}
+	if strings.Contains(got, `data-context-key="targetingKey"`) {
+		t.Errorf("did not expect a context row for the targeting key")
+	}
+	if !strings.Contains(got, "#1 fractionalRule") {
+		t.Errorf("expected the targeting key field to list the rules that read it")
+	}
+}
+
 // TestIndexPageNewFlagDialog verifies the home page opens a name dialog instead
 // of linking directly to a blank edit form.
 func TestIndexPageNewFlagDialog(t *testing.T) {
@@ -247,6 +296,9 @@ func TestIndexPageGroupsFlagsByCategory(t *testing.T) {
 		"billing-flag": {Category: "Billing"},
 		"lonely-flag":  {},
 	})
+	applyUsage(sections, map[string]client.FlagUsage{
+		"billing-flag": {FlagName: "billing-flag", Evaluations: 70, LastEvaluated: time.Now()},
+	}, time.Now())
 
 	var buf bytes.Buffer
 	data := map[string]any{
@@ -267,6 +319,9 @@ func TestIndexPageGroupsFlagsByCategory(t *testing.T) {
 	if !strings.Contains(got, "lonely-flag") {
 		t.Errorf("expected uncategorized flag row")
 	}
+	if !strings.Contains(got, "10/day") || !strings.Contains(got, "never") {
+		t.Errorf("expected usage of each flag")
+	}
 	uncat := strings.Index(got, "Uncategorized")
 	billing := strings.Index(got, ">Billing<")
 	if uncat < 0 || billing < 0 || uncat > billing {
@@ -274,32 +329,28 @@ func TestIndexPageGroupsFlagsByCategory(t *testing.T) {
 	}
 }
 
-// TestConvertTimestamps verifies the helper that upgrades RFC3339 strings to
-// time.Time so date/cron rules behave like real Go callers.
-func TestConvertTimestamps(t *testing.T) {
-	ctx := map[string]any{
-		"now":    "2026-05-19T20:00:00Z",
-		"name":   "alice",
-		"nested": map[string]any{"then": "2025-01-01T00:00:00Z"},
-		"list":   []any{"2024-06-01T00:00:00Z", "not-a-date"},
-	}
-	convertTimestamps(ctx)
+// TestConflictPartial renders the save-conflict dialog for a changed and a
+// deleted flag.
+func TestConflictPartial(t *testing.T) {
+	h := NewWebHandler(nil)
 
-	if _, ok := ctx["now"].(time.Time); !ok {
-		t.Fatalf("expected ctx[now] to be time.Time, got %T", ctx["now"])
-	}
-	if ctx["name"] != "alice" {
-		t.Fatalf("expected ctx[name] to stay 'alice', got %v", ctx["name"])
-	}
-	nested := ctx["nested"].(map[string]any)
-	if _, ok := nested["then"].(time.Time); !ok {
-		t.Fatalf("expected ctx[nested.then] to be time.Time, got %T", nested["then"])
+	var buf bytes.Buffer
+	data := conflictData{FlagName: "checkout", Revision: 4, Changes: []string{`~ DefaultVariant: "off" -> "on"`}, ReloadURL: "/edit/checkout"}
+	if err := h.conflict.ExecuteTemplate(&buf, "conflict", data); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"data-conflict-dialog", "now at revision 4", `data-revision="4"`, `href="/edit/checkout"`, "DefaultVariant"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
 	}
-	list := ctx["list"].([]any)
-	if _, ok := list[0].(time.Time); !ok {
-		t.Fatalf("expected ctx[list[0]] to be time.Time, got %T", list[0])
+
+	buf.Reset()
+	if err := h.conflict.ExecuteTemplate(&buf, "conflict", conflictData{FlagName: "checkout"}); err != nil {
+		t.Fatalf("render: %v", err)
 	}
-	if list[1] != "not-a-date" {
-		t.Fatalf("expected non-date string to pass through, got %v", list[1])
+	if !strings.Contains(buf.String(), "has since been deleted") {
+		t.Errorf("expected deleted flag message, got %s", buf.String())
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
 	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
 )
@@ -103,6 +104,10 @@ func openRouterTools() []map[string]any {
 							"type":        "string",
 							"description": "Optional JSON array string of rules to append.",
 						},
+						"expected_revision": map[string]any{
+							"type":        "integer",
+							"description": "Optional revision the flag must still be at, as returned when it was read. The update is rejected if the flag changed since.",
+						},
 					},
 					"required": []string{"flag_name"},
 				},
@@ -223,6 +228,9 @@ func executeAssistantTool(ctx context.Context, h *WebHandler, name, argsJSON str
 		if len(updates) == 0 {
 			return fmt.Sprintf("No update fields provided for flag %q.", flagName), nil
 		}
+		if expectedRevision, ok := args["expected_revision"].(float64); ok && expectedRevision > 0 {
+			updates[client.ExpectedRevisionKey] = expectedRevision
+		}
 		if err := h.client.PartialUpdateFlag(ctx, flagName, updates); err != nil {
 			return "", fmt.Errorf("updating feature flag: %w", err)
 		}
@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/usage"
+)
+
+const (
+	// usageWindow is how far back evaluations per day are averaged on the
+	// home page.
+	usageWindow = 7 * 24 * time.Hour
+	// defaultStaleDays is the window of the stale flags report when none
+	// is chosen.
+	defaultStaleDays = 30
+)
+
+// flagUsageView is a flag's usage on the home page.
+type flagUsageView struct {
+	LastEvaluated string
+	// LastEvaluatedAt is the full time, shown on hover.
+	LastEvaluatedAt string
+	PerDay          string
+}
+
+// applyUsage sets the usage of every flag in the sections. Nothing is set
+// when no flag has usage, as usage is then most likely not counted at all.
+func applyUsage(sections []flagListSection, flagUsage map[string]client.FlagUsage, now time.Time) {
+	if len(flagUsage) == 0 {
+		return
+	}
+	for i := range sections {
+		for j := range sections[i].Flags {
+			row := &sections[i].Flags[j]
+			u := flagUsage[row.FlagName]
+			view := &flagUsageView{
+				LastEvaluated: "never",
+				PerDay:        formatPerDay(float64(u.Evaluations) / usageWindow.Hours() * 24),
+			}
+			if !u.LastEvaluated.IsZero() {
+				view.LastEvaluated = formatAge(now.Sub(u.LastEvaluated))
+				view.LastEvaluatedAt = u.LastEvaluated.Local().Format(time.DateTime)
+			}
+			row.Usage = view
+		}
+	}
+}
+
+// formatAge describes how long ago something happened, to the largest
+// whole unit.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// formatPerDay shortens a daily rate, e.g. 1.2k for 1234.
+func formatPerDay(perDay float64) string {
+	switch {
+	case perDay >= 1e6:
+		return fmt.Sprintf("%.1fM", perDay/1e6)
+	case perDay >= 1e3:
+		return fmt.Sprintf("%.1fk", perDay/1e3)
+	case perDay >= 10 || perDay == 0:
+		return fmt.Sprintf("%.0f", perDay)
+	default:
+		return fmt.Sprintf("%.1f", perDay)
+	}
+}
+
+// staleCandidateView is a single row on the stale flags page.
+type staleCandidateView struct {
+	FlagName      string
+	Reason        string
+	LastEvaluated string
+}
+
+func buildStaleCandidateViews(candidates []usage.Candidate, now time.Time) []staleCandidateView {
+	views := make([]staleCandidateView, len(candidates))
+	for i, c := range candidates {
+		view := staleCandidateView{FlagName: c.FlagName, LastEvaluated: "never"}
+		if !c.LastEvaluated.IsZero() {
+			view.LastEvaluated = formatAge(now.Sub(c.LastEvaluated))
+		}
+		switch c.Reason {
+		case usage.ReasonNeverEvaluated:
+			view.Reason = "Never evaluated"
+		case usage.ReasonNotEvaluated:
+			view.Reason = "Not evaluated recently"
+		case usage.ReasonSingleVariant:
+			view.Reason = fmt.Sprintf("Always resolved to %q (%d evaluations)", c.Variant, c.Evaluations)
+		}
+		views[i] = view
+	}
+	return views
+}
+
+// HandleStaleFlags lists the flags that were not evaluated in the last
+// "days" days, or that always resolved to the same variant in them.
+func (h *WebHandler) HandleStaleFlags(w http.ResponseWriter, r *http.Request) {
+	days := defaultStaleDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days <= 0 {
+			http.Error(w, "Invalid number of days", http.StatusBadRequest)
+			return
+		}
+	}
+
+	candidates, err := usage.StaleFlags(r.Context(), h.client, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		log.Printf("ERROR finding stale flags: %v", err)
+		http.Error(w, "Failed to find stale flags", http.StatusInternalServerError)
+		return
+	}
+	h.renderTemplate(w, "stale", map[string]any{
+		"Days":       days,
+		"Candidates": buildStaleCandidateViews(candidates, time.Now()),
+	})
+}
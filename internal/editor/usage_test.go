@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/usage"
+)
+
+func TestApplyUsage(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sections := BuildFlagListSections(map[string]flag.Definition{
+		"busy":   {},
+		"unused": {},
+	})
+
+	applyUsage(sections, nil, now)
+	if sections[0].Flags[0].Usage != nil {
+		t.Fatalf("expected no usage when none is counted")
+	}
+
+	applyUsage(sections, map[string]client.FlagUsage{
+		"busy": {FlagName: "busy", Evaluations: 8400, LastEvaluated: now.Add(-3 * time.Hour)},
+	}, now)
+	busy, unused := sections[0].Flags[0].Usage, sections[0].Flags[1].Usage
+	if busy == nil || busy.LastEvaluated != "3h ago" || busy.PerDay != "1.2k" {
+		t.Errorf("busy usage = %+v", busy)
+	}
+	if unused == nil || unused.LastEvaluated != "never" || unused.PerDay != "0" {
+		t.Errorf("unused usage = %+v", unused)
+	}
+}
+
+func TestFormatPerDay(t *testing.T) {
+	for perDay, want := range map[float64]string{
+		0:       "0",
+		0.43:    "0.4",
+		12.4:    "12",
+		1234:    "1.2k",
+		2500000: "2.5M",
+	} {
+		if got := formatPerDay(perDay); got != want {
+			t.Errorf("formatPerDay(%v) = %q, want %q", perDay, got, want)
+		}
+	}
+}
+
+// TestStalePage verifies each kind of candidate renders with its reason.
+func TestStalePage(t *testing.T) {
+	h := NewWebHandler(nil)
+	now := time.Now()
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Days": 30,
+		"Candidates": buildStaleCandidateViews([]usage.Candidate{
+			{FlagName: "forgotten", Reason: usage.ReasonNotEvaluated, LastEvaluated: now.AddDate(0, 0, -45)},
+			{FlagName: "new", Reason: usage.ReasonNeverEvaluated},
+			{FlagName: "rolled-out", Reason: usage.ReasonSingleVariant, LastEvaluated: now, Evaluations: 10, Variant: "on"},
+		}, now),
+	}
+	if err := h.templates["stale"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering stale page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		`href="/edit/forgotten"`,
+		"45d ago",
+		"Never evaluated",
+		"Always resolved to &#34;on&#34; (10 evaluations)",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected stale page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// webhookView is a single row on the webhooks page.
+type webhookView struct {
+	ID         string
+	Name       string
+	URL        string
+	FlagNames  []string
+	Categories []string
+	Disabled   bool
+	Created    string
+}
+
+// deadLetterView is a failed delivery on the webhooks page.
+type deadLetterView struct {
+	ID        string
+	Webhook   string
+	URL       string
+	FlagName  string
+	Attempts  int
+	LastError string
+	FailedAt  string
+	Payload   string
+}
+
+func buildWebhookViews(webhooks []client.Webhook) []webhookView {
+	views := make([]webhookView, len(webhooks))
+	for i, w := range webhooks {
+		created := w.CreatedAt.Local().Format(time.DateTime)
+		if w.CreatedBy.Name != "" {
+			created += " by " + w.CreatedBy.Name
+		}
+		views[i] = webhookView{
+			ID:         w.ID.Hex(),
+			Name:       w.Name,
+			URL:        w.URL,
+			FlagNames:  w.FlagNames,
+			Categories: w.Categories,
+			Disabled:   w.Disabled,
+			Created:    created,
+		}
+	}
+	return views
+}
+
+// buildDeadLetterViews names each dead letter's webhook, falling back to its
+// ID when the webhook has since been deleted.
+func buildDeadLetterViews(deadLetters []client.DeadLetter, webhooks []client.Webhook) []deadLetterView {
+	names := make(map[string]string, len(webhooks))
+	for _, w := range webhooks {
+		names[w.ID.Hex()] = w.Name
+	}
+	views := make([]deadLetterView, len(deadLetters))
+	for i, d := range deadLetters {
+		name, ok := names[d.WebhookID.Hex()]
+		if !ok {
+			name = d.WebhookID.Hex() + " (deleted)"
+		}
+		views[i] = deadLetterView{
+			ID:        d.ID.Hex(),
+			Webhook:   name,
+			URL:       d.URL,
+			FlagName:  d.FlagName,
+			Attempts:  d.Attempts,
+			LastError: d.LastError,
+			FailedAt:  d.FailedAt.Local().Format(time.DateTime),
+			Payload:   d.Payload,
+		}
+	}
+	return views
+}
+
+// parseWebhookForm reads a new webhook from the form on the webhooks page.
+// Flag names and categories are comma separated.
+func parseWebhookForm(values url.Values) (client.Webhook, error) {
+	webhook := client.Webhook{
+		Name:       strings.TrimSpace(values.Get("name")),
+		URL:        strings.TrimSpace(values.Get("url")),
+		Secret:     values.Get("secret"),
+		FlagNames:  splitList(values.Get("flagNames")),
+		Categories: splitList(values.Get("categories")),
+		Disabled:   values.Get("disabled") == "on",
+	}
+	return webhook, webhook.Validate()
+}
+
+// splitList splits a comma separated list, dropping blank entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// HandleWebhooks lists the webhooks and the most recent failed deliveries.
+func (h *WebHandler) HandleWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.client.ListWebhooks(r.Context())
+	if err != nil {
+		log.Printf("ERROR listing webhooks: %v", err)
+		http.Error(w, "Failed to load webhooks", http.StatusInternalServerError)
+		return
+	}
+	deadLetters, err := h.client.ListDeadLetters(r.Context(), "", 100)
+	if err != nil {
+		log.Printf("ERROR listing dead letters: %v", err)
+		http.Error(w, "Failed to load dead letters", http.StatusInternalServerError)
+		return
+	}
+	flags, err := h.client.GetAllFlags(r.Context())
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("ERROR fetching flags: %v", err)
+	}
+
+	h.renderTemplate(w, "webhooks", map[string]any{
+		"Webhooks":    buildWebhookViews(webhooks),
+		"DeadLetters": buildDeadLetterViews(deadLetters, webhooks),
+		"Categories":  CollectCategories(flags),
+	})
+}
+
+// HandleSaveWebhook creates a webhook from the form on the webhooks page.
+// htmx requests get a toast partial back; classic form posts redirect to the
+// webhooks page.
+func (h *WebHandler) HandleSaveWebhook(w http.ResponseWriter, r *http.Request) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := parseWebhookForm(r.PostForm)
+	if err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Invalid webhook", Body: err.Error()})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := client.WithActor(r.Context(), h.requestActor(r, client.ActorSourceEditor))
+	if _, err := h.client.SetWebhook(ctx, webhook); err != nil {
+		log.Printf("ERROR saving webhook '%s': %v", webhook.Name, err)
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Save failed", Body: "Could not save the webhook. Check server logs."})
+			return
+		}
+		http.Error(w, "Failed to save webhook", http.StatusInternalServerError)
+		return
+	}
+
+	if htmx {
+		w.Header().Set("HX-Redirect", "/webhooks")
+		h.writeToast(w, http.StatusOK, toastData{Title: "Webhook added", Body: fmt.Sprintf("%q will be sent flag changes.", webhook.Name)})
+		return
+	}
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+}
+
+// HandleDeleteWebhook removes a webhook. Its dead letters are kept.
+func (h *WebHandler) HandleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	h.handleWebhookDelete(w, r, "webhook", h.client.DeleteWebhook)
+}
+
+// HandleDeleteDeadLetter removes a dead letter once it has been dealt with.
+func (h *WebHandler) HandleDeleteDeadLetter(w http.ResponseWriter, r *http.Request) {
+	h.handleWebhookDelete(w, r, "dead letter", h.client.DeleteDeadLetter)
+}
+
+// handleWebhookDelete deletes the item named by the form's id with remove and
+// sends the browser back to the webhooks page.
+func (h *WebHandler) handleWebhookDelete(w http.ResponseWriter, r *http.Request, kind string, remove func(ctx context.Context, id string) error) {
+	htmx := isHTMX(r)
+
+	if err := r.ParseForm(); err != nil {
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Bad request", Body: "Could not parse form."})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	id := r.FormValue("id")
+	if err := remove(r.Context(), id); err != nil {
+		log.Printf("ERROR deleting %s '%s': %v", kind, id, err)
+		if htmx {
+			h.writeToast(w, http.StatusOK, toastData{Error: true, Title: "Delete failed", Body: fmt.Sprintf("Could not delete the %s.", kind)})
+			return
+		}
+		http.Error(w, "Failed to delete "+kind, http.StatusInternalServerError)
+		return
+	}
+
+	if htmx {
+		w.Header().Set("HX-Redirect", "/webhooks")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+}
@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"bytes"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseWebhookForm(t *testing.T) {
+	webhook, err := parseWebhookForm(url.Values{
+		"name":       {" Slack bot "},
+		"url":        {"https://example.com/hooks"},
+		"secret":     {"s3cret"},
+		"flagNames":  {"checkout, ,new-search"},
+		"categories": {"payments"},
+	})
+	if err != nil {
+		t.Fatalf("parseWebhookForm: %v", err)
+	}
+	if webhook.Name != "Slack bot" || webhook.Secret != "s3cret" || webhook.Disabled {
+		t.Fatalf("unexpected webhook: %+v", webhook)
+	}
+	if want := []string{"checkout", "new-search"}; !reflect.DeepEqual(webhook.FlagNames, want) {
+		t.Errorf("FlagNames = %v, want %v", webhook.FlagNames, want)
+	}
+	if want := []string{"payments"}; !reflect.DeepEqual(webhook.Categories, want) {
+		t.Errorf("Categories = %v, want %v", webhook.Categories, want)
+	}
+
+	for name, values := range map[string]url.Values{
+		"missing name":   {"url": {"https://example.com"}, "secret": {"s"}},
+		"invalid url":    {"name": {"a"}, "url": {"ftp://example.com"}, "secret": {"s"}},
+		"missing secret": {"name": {"a"}, "url": {"https://example.com"}},
+	} {
+		if _, err := parseWebhookForm(values); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWebhooksPage(t *testing.T) {
+	h := NewWebHandler(nil)
+
+	slack := client.Webhook{
+		ID:         bson.NewObjectID(),
+		Name:       "Slack bot",
+		URL:        "https://slack.example.com/hook",
+		Categories: []string{"payments"},
+		CreatedAt:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		CreatedBy:  client.Actor{Name: "alice"},
+	}
+	deploys := client.Webhook{
+		ID:       bson.NewObjectID(),
+		Name:     "Deploy tracker",
+		URL:      "https://deploys.example.com/hook",
+		Disabled: true,
+	}
+	webhooks := []client.Webhook{slack, deploys}
+	deadLetters := []client.DeadLetter{
+		{ID: bson.NewObjectID(), WebhookID: slack.ID, URL: slack.URL, FlagName: "checkout", Attempts: 5, LastError: "unexpected status 502 Bad Gateway", Payload: `{"event":"flag.updated"}`},
+		{ID: bson.NewObjectID(), WebhookID: bson.NewObjectID(), FlagName: "search", Attempts: 2},
+	}
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"Webhooks":    buildWebhookViews(webhooks),
+		"DeadLetters": buildDeadLetterViews(deadLetters, webhooks),
+		"Categories":  []string{"payments", "search"},
+	}
+	if err := h.templates["webhooks"].ExecuteTemplate(&buf, "layout", data); err != nil {
+		t.Fatalf("rendering webhooks page: %v", err)
+	}
+	got := buf.String()
+	for _, fragment := range []string{
+		"Slack bot",
+		"by alice",
+		`<span class="chip">payments</span>`,
+		`<span class="chip">disabled</span>`,
+		"Every flag",
+		`<option value="search">`,
+		"unexpected status 502 Bad Gateway",
+		"after 5 attempts",
+		`href="/history/checkout"`,
+		deadLetters[1].WebhookID.Hex() + " (deleted)",
+		`value="` + slack.ID.Hex() + `"`,
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("expected webhooks page to contain %q; got:\n%s", fragment, got)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 )
 
 var _ openfeature.EventHandler = (*EventHandler)(nil)
@@ -19,12 +20,14 @@ func New(opts *Options) (*EventHandler, error) {
 	return &EventHandler{
 		eventCh:             make(chan openfeature.Event, eventChannelSize),
 		droppedEventHandler: opts.DroppedEventHandler,
+		metrics:             opts.Metrics,
 	}, nil
 }
 
 type EventHandler struct {
 	eventCh             chan openfeature.Event
 	droppedEventHandler DroppedEventHandler
+	metrics             metrics.Metrics
 }
 
 func (h *EventHandler) EventChannel() <-chan openfeature.Event {
@@ -37,6 +40,7 @@ func (h *EventHandler) Publish(event openfeature.Event) {
 	select {
 	case h.eventCh <- event:
 	default:
+		h.metrics.EventDropped(event.EventType)
 		h.droppedEventHandler(event)
 	}
 }
@@ -3,6 +3,7 @@ package eventhandler
 import (
 	"github.com/open-feature/go-sdk/openfeature"
 	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 )
 
 type DroppedEventHandler func(event openfeature.Event)
@@ -12,6 +13,12 @@ type Options struct {
 
 	// DroppedEventHandler is the function to call when an event is dropped.
 	DroppedEventHandler DroppedEventHandler
+
+	// ===== Optional =====
+
+	// Metrics counts dropped events. If not provided, it defaults to
+	// metrics.Nop.
+	Metrics metrics.Metrics
 }
 
 func NewOptions(droppedEventHandler DroppedEventHandler) *Options {
@@ -20,6 +27,14 @@ func NewOptions(droppedEventHandler DroppedEventHandler) *Options {
 	}
 }
 
+func (opts *Options) WithMetrics(m metrics.Metrics) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Metrics = m
+	return opts
+}
+
 func (opts *Options) Validate() error {
 	if opts == nil {
 		return mongoopenfeature.ErrNilOptions
@@ -27,5 +42,10 @@ func (opts *Options) Validate() error {
 	if opts.DroppedEventHandler == nil {
 		return mongoopenfeature.ErrNilDroppedEventHandler
 	}
+
+	// Setting defaults
+	if opts.Metrics == nil {
+		opts.Metrics = metrics.Nop{}
+	}
 	return nil
 }
@@ -0,0 +1,180 @@
+// Package flagdsync serves the flags in a cache as a flagd flag
+// configuration, so flagd instances can use them as an HTTP sync source.
+// Besides plain polling, clients can long-poll for the next change or
+// subscribe to a server-sent event stream that pushes every change.
+package flagdsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagd"
+)
+
+const (
+	// FlagsPath serves the current configuration. It honours If-None-Match,
+	// and with a wait query parameter (such as ?wait=30s) a request whose
+	// ETag is current is held until the configuration changes.
+	FlagsPath = "/flagd/v1/flags.json"
+	// StreamPath is a server-sent event stream with one "configuration"
+	// event on connect and another after every change.
+	StreamPath = "/flagd/v1/stream"
+)
+
+func New(opts *Options) (*Server, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating flagd sync options: %w", err)
+	}
+	s := &Server{
+		cache:             opts.Cache,
+		maxWait:           opts.MaxWait,
+		heartbeatInterval: opts.HeartbeatInterval,
+		logger:            opts.Logger,
+		changed:           make(chan struct{}),
+	}
+	if err := s.Refresh(); err != nil {
+		return nil, fmt.Errorf("building initial configuration: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+FlagsPath, s.handleFlags)
+	mux.HandleFunc("GET "+StreamPath, s.handleStream)
+	s.handler = httputil.RequireBearer(opts.Tokens, mux)
+	return s, nil
+}
+
+// Server is an http.Handler serving flagd configurations.
+type Server struct {
+	cache             *cache.Cache
+	maxWait           time.Duration
+	heartbeatInterval time.Duration
+	logger            *slog.Logger
+	handler           http.Handler
+
+	mu   sync.Mutex
+	body []byte
+	etag string
+	// changed is closed and replaced whenever the configuration changes,
+	// waking every request waiting on it.
+	changed chan struct{}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// Refresh rebuilds the configuration from the cache and, if it differs from
+// the one being served, pushes it to waiting clients. It should be called
+// whenever the watch handler reports a change.
+func (s *Server) Refresh() error {
+	definitions := s.cache.All()
+	list := make([]flag.Definition, 0, len(definitions))
+	for _, definition := range definitions {
+		list = append(list, definition)
+	}
+	file, issues := flagd.Export(list)
+	body, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("encoding flagd configuration: %w", err)
+	}
+	etag := httputil.ETag(body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if etag == s.etag {
+		return nil
+	}
+	// Issues are only logged when the configuration changes, since the
+	// same flags would otherwise be reported on every refresh.
+	for _, issue := range issues {
+		s.logger.Warn("flag left out of flagd configuration", "flag", issue.FlagName, "reason", issue.Message)
+	}
+	s.body, s.etag = body, etag
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+func (s *Server) snapshot() ([]byte, string, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.body, s.etag, s.changed
+}
+
+func (s *Server) handleFlags(w http.ResponseWriter, r *http.Request) {
+	var wait time.Duration
+	if raw := r.URL.Query().Get("wait"); raw != "" {
+		var err error
+		if wait, err = time.ParseDuration(raw); err != nil || wait < 0 {
+			http.Error(w, "invalid wait duration", http.StatusBadRequest)
+			return
+		}
+		wait = min(wait, s.maxWait)
+	}
+
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	body, etag, changed := s.snapshot()
+	if wait > 0 && httputil.ETagMatches(ifNoneMatch, etag) {
+		timer := time.NewTimer(wait)
+		select {
+		case <-changed:
+		case <-timer.C:
+		case <-r.Context().Done():
+		}
+		timer.Stop()
+		body, etag, _ = s.snapshot()
+	}
+
+	w.Header().Set("ETag", etag)
+	if httputil.ETagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		s.logger.Error("writing flagd configuration", "error", err)
+	}
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		body, etag, changed := s.snapshot()
+		if _, err := fmt.Fprintf(w, "event: configuration\nid: %s\ndata: %s\n\n", strings.Trim(etag, `"`), body); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		for waiting := true; waiting; {
+			select {
+			case <-changed:
+				waiting = false
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package flagdsync
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagd"
+)
+
+func newTestServer(t *testing.T) (*Server, *cache.Cache) {
+	t.Helper()
+	c := cache.New()
+	if err := c.Set("checkout", flag.Definition{FlagName: "checkout", DefaultValue: true, DefaultVariant: "on"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(NewOptions(c).WithMaxWait(5 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+func get(s *Server, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFlags(t *testing.T) {
+	s, c := newTestServer(t)
+
+	rec := get(s, FlagsPath, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	file, err := flagd.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := file.Flags["checkout"]; !ok || f.Variants["on"] != true || f.DefaultVariant != "on" {
+		t.Fatalf("flags = %+v", file.Flags)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if rec := get(s, FlagsPath, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", rec.Code)
+	}
+
+	if err := c.Set("other", flag.Definition{FlagName: "other", DefaultValue: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(s, FlagsPath, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+		t.Fatal("the configuration should only change once Refresh is called")
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(s, FlagsPath, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d after refresh, want 200", rec.Code)
+	}
+}
+
+func TestLongPoll(t *testing.T) {
+	s, c := newTestServer(t)
+	etag := get(s, FlagsPath, nil).Header().Get("ETag")
+
+	t.Run("Timeout", func(t *testing.T) {
+		rec := get(s, FlagsPath+"?wait=10ms", http.Header{"If-None-Match": {etag}})
+		if rec.Code != http.StatusNotModified {
+			t.Fatalf("status = %d, want 304", rec.Code)
+		}
+	})
+
+	t.Run("Change", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- get(s, FlagsPath+"?wait=5s", http.Header{"If-None-Match": {etag}})
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		if err := c.Set("checkout", flag.Definition{FlagName: "checkout", DefaultValue: false, DefaultVariant: "off"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case rec := <-done:
+			if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"off"`) {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("long poll was not woken by the change")
+		}
+	})
+
+	t.Run("InvalidWait", func(t *testing.T) {
+		if rec := get(s, FlagsPath+"?wait=soon", nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", rec.Code)
+		}
+	})
+}
+
+func TestStream(t *testing.T) {
+	s, c := newTestServer(t)
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+StreamPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	nextData := func() string {
+		t.Helper()
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				return data
+			}
+		}
+		t.Fatalf("stream ended: %v", scanner.Err())
+		return ""
+	}
+
+	if data := nextData(); !strings.Contains(data, `"checkout"`) {
+		t.Fatalf("first event = %s", data)
+	}
+
+	if err := c.Set("banner", flag.Definition{FlagName: "banner", DefaultValue: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if data := nextData(); !strings.Contains(data, `"banner"`) {
+		t.Fatalf("second event = %s", data)
+	}
+}
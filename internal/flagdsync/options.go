@@ -0,0 +1,94 @@
+package flagdsync
+
+import (
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Cache holds the flags that are served. It is expected to be kept
+	// up to date by a watch handler, with Refresh called after every
+	// change.
+	Cache *cache.Cache
+
+	// ===== Optional =====
+
+	// Tokens are the bearer tokens accepted by the server. If none are
+	// provided, requests are not authenticated.
+	Tokens []string
+	// MaxWait is the longest a long-poll request is held open waiting
+	// for a change. If not provided, it defaults to 1 minute.
+	MaxWait time.Duration
+	// HeartbeatInterval is how often a comment is written to idle
+	// event streams so proxies don't close them. If not provided, it
+	// defaults to 30 seconds.
+	HeartbeatInterval time.Duration
+	// Logger is the logger to use for the server. Flags that cannot be
+	// translated to flagd's format are logged here.
+	Logger *slog.Logger
+}
+
+func NewOptions(cache *cache.Cache) *Options {
+	return &Options{
+		Cache: cache,
+	}
+}
+
+func (opts *Options) WithTokens(tokens ...string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Tokens = tokens
+	return opts
+}
+
+func (opts *Options) WithMaxWait(maxWait time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.MaxWait = maxWait
+	return opts
+}
+
+func (opts *Options) WithHeartbeatInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.HeartbeatInterval = interval
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Cache == nil {
+		return mongoopenfeature.ErrMissingCache
+	}
+
+	// Setting defaults
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = time.Minute
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = 30 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
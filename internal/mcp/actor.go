@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type remoteAddrKey struct{}
+
+// withRemoteAddr stores the address of the HTTP client in the context so
+// tool calls over the SSE and HTTP transports can record it.
+func withRemoteAddr(ctx context.Context, r *http.Request) context.Context {
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return context.WithValue(ctx, remoteAddrKey{}, addr)
+}
+
+// withActor attributes flag writes made by a tool call to the MCP client,
+// named by the client info it sent when initializing the session.
+func withActor(ctx context.Context) context.Context {
+	actor := client.Actor{Name: "mcp", Source: client.ActorSourceMCP}
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+		if info := session.GetClientInfo(); info.Name != "" {
+			actor.Name = info.Name
+		}
+	}
+	actor.Address, _ = ctx.Value(remoteAddrKey{}).(string)
+	return client.WithActor(ctx, actor)
+}
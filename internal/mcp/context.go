@@ -28,7 +28,7 @@ const contextGet = `
  "ExactMatchRule": "Matches when a context key exactly equals a specified value",
  "RegexRule": "Matches when a context key matches a regular expression pattern",
  "ExistsRule": "Matches when a specified key exists in the evaluation context",
- "FractionalRule": "Matches a percentage of users based on a hash of the key and its value",
+ "FractionalRule": "Matches a percentage of users based on a hash of the key and its value, bucketing on the targeting key by default",
  "RangeRule": "Matches when a numeric context key falls within a specified min/max range",
  "InListRule": "Matches when a context key's value is contained in a predefined list of values",
  "PrefixRule": "Matches when a context key's string value starts with a specified prefix",
@@ -36,7 +36,7 @@ const contextGet = `
  "ContainsRule": "Matches when a context key's string value contains a specified substring",
  "IPRangeRule": "Matches when an IP address context key falls within specified CIDR ranges",
  "GeoFenceRule": "Matches when latitude/longitude coordinates are within a specified radius from a center point",
- "DateTimeRule": "Matches when a time context key falls within a specified time range",
+ "DateTimeRule": "Matches when a time context key falls within a specified, optionally open-ended, time range",
  "SemVerRule": "Matches when a semantic version context key satisfies a version constraint expression",
  "CronRule": "Matches when a time context key falls within a cron schedule plus duration window",
  "AndRule": "Matches only when all nested rules match (logical AND operation)",
@@ -51,7 +51,7 @@ const contextPost = `
     "exactMatchRule": {
       "description": "Matches when a context key exactly equals a specified value.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "KeyValue": "string - value to match exactly",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority (higher = takes precedence)",
@@ -61,7 +61,7 @@ const contextPost = `
     "regexRule": {
       "description": "Matches when a context key matches a regular expression pattern.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "Pattern": "string - regex pattern to match",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -71,7 +71,7 @@ const contextPost = `
     "existsRule": {
       "description": "Matches when a specified key exists in the evaluation context.",
       "fields": {
-        "Key": "string - context key to check for existence",
+        "Key": "string - context key to check for existence (empty string uses the targeting key)",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
         "ValueData": "any - value to return when matched"
@@ -80,7 +80,7 @@ const contextPost = `
     "fractionalRule": {
       "description": "Matches a percentage of users based on a hash of the key and its value.",
       "fields": {
-        "Key": "string - context key to hash",
+        "Key": "string - context key to hash (empty string uses the targeting key)",
         "Percentage": "float64 - percentage (0.0-100.0) of users to match",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -103,7 +103,7 @@ const contextPost = `
     "inListRule": {
       "description": "Matches when a context key's value is contained in a predefined list of values.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "Items": "array - list of values to match against",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -113,7 +113,7 @@ const contextPost = `
     "prefixRule": {
       "description": "Matches when a context key's string value starts with a specified prefix.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "Prefix": "string - prefix to match",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -123,7 +123,7 @@ const contextPost = `
     "suffixRule": {
       "description": "Matches when a context key's string value ends with a specified suffix.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "Suffix": "string - suffix to match",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -133,7 +133,7 @@ const contextPost = `
     "containsRule": {
       "description": "Matches when a context key's string value contains a specified substring.",
       "fields": {
-        "Key": "string - context key to check",
+        "Key": "string - context key to check (empty string uses the targeting key)",
         "Substring": "string - substring to match",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
@@ -166,9 +166,9 @@ const contextPost = `
     "dateTimeRule": {
       "description": "Matches when a time context key falls within a specified time range.",
       "fields": {
-        "Key": "string - context key containing time.Time",
-        "After": "string (RFC3339) - start of time range",
-        "Before": "string (RFC3339) - end of time range",
+        "Key": "string - context key containing a time (RFC3339 string, Unix epoch seconds or milliseconds, or time.Time)",
+        "After": "string (RFC3339) - start of time range (omit for no start)",
+        "Before": "string (RFC3339) - end of time range (omit for no end)",
         "VariantID": "string - variant identifier",
         "Priority": "int - rule priority",
         "ValueData": "any - value to return when matched"
@@ -187,7 +187,7 @@ const contextPost = `
     "cronRule": {
       "description": "Matches when a time context key falls within a cron schedule plus duration window.",
       "fields": {
-        "Key": "string - context key containing time.Time (empty string uses time.Now())",
+        "Key": "string - context key containing a time (RFC3339 string, Unix epoch seconds or milliseconds, or time.Time; empty string uses time.Now())",
         "CronSpec": "string - cron expression (e.g., '0 9 * * MON-FRI')",
         "Duration": "int64 - window duration from cron trigger, in nanoseconds (Go time.Duration)",
         "VariantID": "string - variant identifier",
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+)
+
+func (se *mcpServer) getContextSchemaTool() (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	return mcp.NewTool("get_context_schema",
+			mcp.WithDescription("List the evaluation context keys callers send, with their types and descriptions. When any key is declared, rules may only read declared keys as their declared type, or the flag is rejected when it is saved; use these names in rule keys. 'targetingKey' is always available. Also returns the problems found in sampled contexts: keys callers send that are not declared, and keys sent with another type than declared."),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			schema, err := se.ofClient.GetContextSchema(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("getting context schema: %v", err)), nil
+			}
+			observations, err := se.ofClient.ListContextObservations(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("listing context observations: %v", err)), nil
+			}
+			issues := schema.CheckObservations(observations)
+			if issues == nil {
+				issues = []contextschema.Issue{}
+			}
+			return newToolResultResponseWithContext("context_schema", "context_schema://keys", map[string]any{
+				"keys":   schema,
+				"issues": issues,
+			}), nil
+		}
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+func (se *mcpServer) getExperimentResultsTool() (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	return mcp.NewTool("get_experiment_results",
+			mcp.WithDescription("Analyse the experiment run with a feature flag from its recorded impressions and tracking events. For each variant it returns the users exposed, the users who sent the conversion event, the conversion rate with a 95% confidence interval, the value per user, and the lift and p-value against the control. It also returns a sample ratio mismatch check; when 'srm.mismatch' is true the results can't be trusted."),
+			mcp.WithString("flag_name",
+				mcp.Required(),
+				mcp.Description("The name of the feature flag the experiment runs on."),
+			),
+			mcp.WithString("event_name",
+				mcp.Required(),
+				mcp.Description("The tracking event that counts as a conversion, e.g. 'purchase'."),
+			),
+			mcp.WithString("control",
+				mcp.Description("The variant to compare the others with. Defaults to the variant named 'control', or else the first variant by name."),
+			),
+			mcp.WithString("since",
+				mcp.Description("Only count impressions and events at or after this RFC 3339 time."),
+			),
+			mcp.WithString("until",
+				mcp.Description("Only count impressions and events before this RFC 3339 time."),
+			),
+			mcp.WithString("weights_json",
+				mcp.Description(`The expected share of users per variant as a JSON object, e.g. {"control": 50, "treatment": 50}. Users are expected to be split evenly when omitted.`),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			flagName, err := request.RequireString("flag_name")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("missing required argument 'flag_name': %v", err)), nil
+			}
+			eventName, err := request.RequireString("event_name")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("missing required argument 'event_name': %v", err)), nil
+			}
+			query := client.ExperimentQuery{
+				FlagName:  flagName,
+				EventName: eventName,
+				Control:   request.GetString("control", ""),
+			}
+			if since := request.GetString("since", ""); since != "" {
+				if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid 'since' time: %v", err)), nil
+				}
+			}
+			if until := request.GetString("until", ""); until != "" {
+				if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid 'until' time: %v", err)), nil
+				}
+			}
+			if weightsJSON := request.GetString("weights_json", ""); weightsJSON != "" {
+				if err := json.Unmarshal([]byte(weightsJSON), &query.Weights); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid 'weights_json': %v", err)), nil
+				}
+			}
+
+			results, err := se.ofClient.ExperimentResults(ctx, query)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("analysing the experiment on feature flag '%s': %v", flagName, err)), nil
+			}
+			resultsJSON, err := json.Marshal(results)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("marshaling results to JSON: %v", err)), nil
+			}
+			return mcp.NewToolResultResource("experiment_results", mcp.TextResourceContents{
+				URI:      fmt.Sprintf("feature_flags://%s/experiments/%s", flagName, eventName),
+				MIMEType: "application/json",
+				Text:     string(resultsJSON),
+			}), nil
+		}
+}
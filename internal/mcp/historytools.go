@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (se *mcpServer) listFeatureFlagVersionsTool() (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	return mcp.NewTool("list_feature_flag_versions",
+			mcp.WithDescription("List the recorded versions of a feature flag, newest first. Each version includes the author, timestamp, the full definition after the change and a diff from the previous version."),
+			mcp.WithString("flag_name",
+				mcp.Required(),
+				mcp.Description("The name of the feature flag."),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			flagName, err := request.RequireString("flag_name")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("missing required argument 'flag_name': %v", err)), nil
+			}
+
+			versions, err := se.ofClient.ListVersions(ctx, flagName)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("listing versions of feature flag '%s': %v", flagName, err)), nil
+			}
+			return newToolResultResponseWithContext("versions", fmt.Sprintf("feature_flags://%s/versions", flagName), versions), nil
+		}
+}
+
+func (se *mcpServer) rollbackFeatureFlagTool() (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	return mcp.NewTool("rollback_feature_flag",
+			mcp.WithDescription("Restore a feature flag to a previously recorded version. The rollback is recorded as a new version."),
+			mcp.WithString("flag_name",
+				mcp.Required(),
+				mcp.Description("The name of the feature flag to roll back."),
+			),
+			mcp.WithNumber("version",
+				mcp.Required(),
+				mcp.Description("The version number to restore, as returned by list_feature_flag_versions."),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			flagName, err := request.RequireString("flag_name")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("missing required argument 'flag_name': %v", err)), nil
+			}
+			version, err := request.RequireInt("version")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("missing required argument 'version': %v", err)), nil
+			}
+
+			if err := se.ofClient.Rollback(withActor(ctx), flagName, version); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to roll back feature flag '%s' to version %d: %v", flagName, version, err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Successfully rolled back feature flag '%s' to version %d.", flagName, version)), nil
+		}
+}
@@ -45,6 +45,10 @@ func (se *mcpServer) Serve() error {
 	s.AddTool(se.getFeatureFlagsTool())
 	s.AddTool(se.insertFeatureFlagTool())
 	s.AddTool(se.partialUpdateFeatureFlagTool())
+	s.AddTool(se.listFeatureFlagVersionsTool())
+	s.AddTool(se.rollbackFeatureFlagTool())
+	s.AddTool(se.getExperimentResultsTool())
+	s.AddTool(se.getContextSchemaTool())
 
 	serve := os.Getenv("MCP_SERVE")
 
@@ -55,7 +59,7 @@ func (se *mcpServer) Serve() error {
 			port = ":" + envPort
 		}
 		fmt.Println("Starting MCP Server Side Events server on http://localhost" + port)
-		if err := server.NewSSEServer(s).Start("0.0.0.0" + port); err != nil {
+		if err := server.NewSSEServer(s, server.WithSSEContextFunc(withRemoteAddr)).Start("0.0.0.0" + port); err != nil {
 			return fmt.Errorf("running SSE server: %w", err)
 		}
 	case "http":
@@ -64,7 +68,7 @@ func (se *mcpServer) Serve() error {
 			port = ":" + envPort
 		}
 		fmt.Println("Starting MCP server on http://localhost" + port)
-		if err := server.NewStreamableHTTPServer(s).Start("0.0.0.0" + port); err != nil {
+		if err := server.NewStreamableHTTPServer(s, server.WithHTTPContextFunc(withRemoteAddr)).Start("0.0.0.0" + port); err != nil {
 			return fmt.Errorf("running MCP server: %w", err)
 		}
 	default:
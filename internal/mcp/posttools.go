@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
 	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
 )
@@ -82,7 +83,7 @@ func (se *mcpServer) insertFeatureFlagTool() (mcp.Tool, func(ctx context.Context
 			}
 
 			// Insert the flag definition using the client
-			if err := se.ofClient.SetFlag(ctx, flagDef); err != nil {
+			if err := se.ofClient.SetFlag(withActor(ctx), flagDef); err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to insert feature flag '%s': %v", flagName, err)), nil
 			}
 
@@ -113,6 +114,9 @@ func (se *mcpServer) partialUpdateFeatureFlagTool() (mcp.Tool, func(ctx context.
 			mcp.WithString("append_rules_json",
 				mcp.Description("An optional JSON array string of rules to append to the existing rules. Existing rules will be preserved."),
 			),
+			mcp.WithNumber("expected_revision",
+				mcp.Description("An optional revision the flag must still be at, as returned when the flag was read. The update is rejected if the flag has been changed since."),
+			),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// Extract the required flag name
@@ -166,8 +170,12 @@ func (se *mcpServer) partialUpdateFeatureFlagTool() (mcp.Tool, func(ctx context.
 				return mcp.NewToolResultText(fmt.Sprintf("No update fields provided for flag '%s'. Nothing was changed.", flagName)), nil
 			}
 
+			if expectedRevision := request.GetInt("expected_revision", 0); expectedRevision > 0 {
+				updates[client.ExpectedRevisionKey] = expectedRevision
+			}
+
 			// Perform the partial update using the client
-			if err := se.ofClient.PartialUpdateFlag(ctx, flagName, updates); err != nil {
+			if err := se.ofClient.PartialUpdateFlag(withActor(ctx), flagName, updates); err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to update feature flag '%s': %v", flagName, err)), nil
 			}
 
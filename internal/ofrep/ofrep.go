@@ -0,0 +1,206 @@
+// Package ofrep serves the OpenFeature Remote Evaluation Protocol (OFREP)
+// single-flag and bulk evaluation endpoints from a flag cache.
+package ofrep
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+)
+
+const (
+	// EvaluatePath is the single-flag evaluation endpoint; {key} is the flag name.
+	EvaluatePath = "/ofrep/v1/evaluate/flags/{key}"
+	// BulkEvaluatePath is the bulk evaluation endpoint.
+	BulkEvaluatePath = "/ofrep/v1/evaluate/flags"
+
+	maxRequestSize = 1 << 20
+)
+
+// Error codes defined by OFREP.
+const (
+	ErrorCodeParse          = "PARSE_ERROR"
+	ErrorCodeInvalidContext = "INVALID_CONTEXT"
+	ErrorCodeFlagNotFound   = "FLAG_NOT_FOUND"
+	ErrorCodeGeneral        = "GENERAL"
+)
+
+// EvaluationRequest is the body of both evaluation endpoints.
+type EvaluationRequest struct {
+	Context map[string]any `json:"context"`
+}
+
+// EvaluationSuccess is the result of evaluating one flag.
+type EvaluationSuccess struct {
+	Key      string         `json:"key"`
+	Value    any            `json:"value"`
+	Reason   string         `json:"reason"`
+	Variant  string         `json:"variant,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// EvaluationFailure describes why a flag, or a whole request, could not be
+// evaluated.
+type EvaluationFailure struct {
+	Key          string `json:"key,omitempty"`
+	ErrorCode    string `json:"errorCode"`
+	ErrorDetails string `json:"errorDetails,omitempty"`
+}
+
+// BulkEvaluationResponse is the result of the bulk evaluation endpoint. Each
+// entry is an EvaluationSuccess or an EvaluationFailure.
+type BulkEvaluationResponse struct {
+	Flags []any `json:"flags"`
+}
+
+func New(opts *Options) (*Server, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating ofrep options: %w", err)
+	}
+	s := &Server{
+		cache:  opts.Cache,
+		logger: opts.Logger,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+EvaluatePath, s.handleEvaluate)
+	mux.HandleFunc("POST "+BulkEvaluatePath, s.handleBulkEvaluate)
+	s.handler = httputil.RequireBearer(opts.Tokens, mux)
+	return s, nil
+}
+
+// Server is an http.Handler for the OFREP evaluation endpoints.
+type Server struct {
+	cache   *cache.Cache
+	logger  *slog.Logger
+	handler http.Handler
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	evalCtx, failure := readContext(r)
+	if failure != nil {
+		failure.Key = key
+		s.writeJSON(w, http.StatusBadRequest, failure)
+		return
+	}
+
+	result := s.evaluate(key, evalCtx)
+	if failure, ok := result.(EvaluationFailure); ok {
+		status := http.StatusBadRequest
+		if failure.ErrorCode == ErrorCodeFlagNotFound {
+			status = http.StatusNotFound
+		}
+		s.writeJSON(w, status, failure)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// handleBulkEvaluate evaluates every flag. The response carries an ETag of
+// its body; a request whose If-None-Match matches it gets 304 Not Modified,
+// so clients polling with an unchanged context and unchanged flags don't
+// download the same result again.
+func (s *Server) handleBulkEvaluate(w http.ResponseWriter, r *http.Request) {
+	evalCtx, failure := readContext(r)
+	if failure != nil {
+		s.writeJSON(w, http.StatusBadRequest, failure)
+		return
+	}
+
+	definitions := s.cache.All()
+	keys := make([]string, 0, len(definitions))
+	for key := range definitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	response := BulkEvaluationResponse{Flags: make([]any, 0, len(keys))}
+	for _, key := range keys {
+		response.Flags = append(response.Flags, s.evaluate(key, evalCtx))
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Error("encoding bulk evaluation", "error", err)
+		s.writeJSON(w, http.StatusInternalServerError, EvaluationFailure{ErrorCode: ErrorCodeGeneral, ErrorDetails: "encoding response"})
+		return
+	}
+
+	etag := httputil.ETag(body)
+	w.Header().Set("ETag", etag)
+	if httputil.ETagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		s.logger.Error("writing bulk evaluation", "error", err)
+	}
+}
+
+// evaluate returns an EvaluationSuccess or EvaluationFailure for key.
+func (s *Server) evaluate(key string, evalCtx map[string]any) any {
+	definition, ok := s.cache.Get(key)
+	if !ok {
+		return EvaluationFailure{Key: key, ErrorCode: ErrorCodeFlagNotFound, ErrorDetails: fmt.Sprintf("flag '%s' was not found", key)}
+	}
+	value, detail := definition.Evaluate(evalCtx)
+	result := EvaluationSuccess{
+		Key:     key,
+		Value:   value,
+		Reason:  string(detail.Reason),
+		Variant: detail.Variant,
+	}
+	if definition.Category != "" {
+		result.Metadata = map[string]any{"category": definition.Category}
+	}
+	return result
+}
+
+// readContext decodes the evaluation context from the request body. An
+// empty body is an empty context.
+func readContext(r *http.Request) (map[string]any, *EvaluationFailure) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		return nil, &EvaluationFailure{ErrorCode: ErrorCodeParse, ErrorDetails: fmt.Sprintf("reading request: %v", err)}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return map[string]any{}, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, &EvaluationFailure{ErrorCode: ErrorCodeParse, ErrorDetails: fmt.Sprintf("decoding request: %v", err)}
+	}
+	evalCtx := map[string]any{}
+	if rawContext, ok := raw["context"]; ok && string(rawContext) != "null" {
+		if err := json.Unmarshal(rawContext, &evalCtx); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				return nil, &EvaluationFailure{ErrorCode: ErrorCodeInvalidContext, ErrorDetails: "context must be an object"}
+			}
+			return nil, &EvaluationFailure{ErrorCode: ErrorCodeParse, ErrorDetails: fmt.Sprintf("decoding context: %v", err)}
+		}
+	}
+	return evalCtx, nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error("writing response", "error", err)
+	}
+}
@@ -0,0 +1,167 @@
+package ofrep
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func newTestServer(t *testing.T, tokens ...string) (*Server, *cache.Cache) {
+	t.Helper()
+	c := cache.New()
+	err := c.SetAll(map[string]flag.Definition{
+		"checkout": {
+			FlagName:       "checkout",
+			DefaultValue:   false,
+			DefaultVariant: "off",
+			Category:       "payments",
+			Rules: []rule.ConcreteRule{
+				{ExactMatchRule: &rule.ExactMatchRule{Key: "targetingKey", KeyValue: "alice", VariantID: "on", ValueData: true}},
+			},
+		},
+		"max-items": {FlagName: "max-items", DefaultValue: int64(10)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(NewOptions(c).WithTokens(tokens...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+func post(s *Server, path, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEvaluate(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	t.Run("TargetingMatch", func(t *testing.T) {
+		rec := post(s, "/ofrep/v1/evaluate/flags/checkout", `{"context": {"targetingKey": "alice"}}`, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+		}
+		var got EvaluationSuccess
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Value != true || got.Variant != "on" || got.Reason != "TARGETING_MATCH" {
+			t.Fatalf("got %+v", got)
+		}
+		if got.Metadata["category"] != "payments" {
+			t.Fatalf("metadata = %v", got.Metadata)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		rec := post(s, "/ofrep/v1/evaluate/flags/checkout", `{"context": {"targetingKey": "bob"}}`, nil)
+		var got EvaluationSuccess
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Value != false || got.Variant != "off" || got.Reason != "DEFAULT" {
+			t.Fatalf("got %+v", got)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		rec := post(s, "/ofrep/v1/evaluate/flags/missing", `{}`, nil)
+		if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), ErrorCodeFlagNotFound) {
+			t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		rec := post(s, "/ofrep/v1/evaluate/flags/checkout", `{not json`, nil)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), ErrorCodeParse) {
+			t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("InvalidContext", func(t *testing.T) {
+		rec := post(s, "/ofrep/v1/evaluate/flags/checkout", `{"context": [1, 2]}`, nil)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), ErrorCodeInvalidContext) {
+			t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+		}
+	})
+}
+
+func TestBulkEvaluate(t *testing.T) {
+	s, c := newTestServer(t)
+
+	rec := post(s, "/ofrep/v1/evaluate/flags", `{"context": {"targetingKey": "alice"}}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var got struct {
+		Flags []EvaluationSuccess `json:"flags"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Flags) != 2 || got.Flags[0].Key != "checkout" || got.Flags[1].Key != "max-items" {
+		t.Fatalf("flags = %+v", got.Flags)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	rec = post(s, "/ofrep/v1/evaluate/flags", `{"context": {"targetingKey": "alice"}}`, http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", rec.Code)
+	}
+
+	rec = post(s, "/ofrep/v1/evaluate/flags", `{"context": {"targetingKey": "bob"}}`, http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("a different context should not match the ETag, status = %d", rec.Code)
+	}
+
+	if err := c.Set("max-items", flag.Definition{FlagName: "max-items", DefaultValue: int64(20)}); err != nil {
+		t.Fatal(err)
+	}
+	rec = post(s, "/ofrep/v1/evaluate/flags", `{"context": {"targetingKey": "alice"}}`, http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("a flag change should change the ETag, status = %d", rec.Code)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	s, _ := newTestServer(t, "secret", "other")
+
+	for name, tc := range map[string]struct {
+		header string
+		status int
+	}{
+		"Missing":   {"", http.StatusUnauthorized},
+		"Wrong":     {"Bearer nope", http.StatusUnauthorized},
+		"NotBearer": {"Basic secret", http.StatusUnauthorized},
+		"Valid":     {"Bearer secret", http.StatusOK},
+		"Second":    {"Bearer other", http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.header != "" {
+				header.Set("Authorization", tc.header)
+			}
+			rec := post(s, "/ofrep/v1/evaluate/flags/checkout", `{}`, header)
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ofrep
+
+import (
+	"log/slog"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Cache holds the flags that are evaluated. It is expected to be
+	// kept up to date by a watch handler.
+	Cache *cache.Cache
+
+	// ===== Optional =====
+
+	// Tokens are the bearer tokens accepted by the server. If none are
+	// provided, requests are not authenticated.
+	Tokens []string
+	// Logger is the logger to use for the server.
+	Logger *slog.Logger
+}
+
+func NewOptions(cache *cache.Cache) *Options {
+	return &Options{
+		Cache: cache,
+	}
+}
+
+func (opts *Options) WithTokens(tokens ...string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Tokens = tokens
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Cache == nil {
+		return mongoopenfeature.ErrMissingCache
+	}
+
+	// Setting defaults
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package reconciler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+)
+
+// isDefinitionFile reports whether path holds flag definitions. Hidden files
+// are skipped so editor swap files and the like are never applied.
+func isDefinitionFile(path string) bool {
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// definitionFiles lists the definition files under dir, sorted by path.
+func definitionFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isDefinitionFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// LoadDirectory reads every JSON and YAML file under dir. A file holds either
+// a single flag definition or a flag set. Flag names must be unique across
+// the directory.
+func LoadDirectory(dir string) ([]flag.Definition, error) {
+	files, err := definitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []flag.Definition
+	sources := map[string]string{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		format := flagset.FormatFromPath(path)
+
+		var loaded []flag.Definition
+		if flagset.IsSet(data, format) {
+			set, err := flagset.Decode(bytes.NewReader(data), format)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			loaded = set.Flags
+		} else {
+			def, err := flagset.DecodeDefinition(bytes.NewReader(data), format)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			loaded = []flag.Definition{*def}
+		}
+
+		for _, def := range loaded {
+			if other, ok := sources[def.FlagName]; ok {
+				return nil, fmt.Errorf("flag '%s' is defined in both %s and %s", def.FlagName, other, path)
+			}
+			sources[def.FlagName] = path
+			definitions = append(definitions, def)
+		}
+	}
+	return definitions, nil
+}
+
+// fingerprint summarises the names, sizes and modification times of the
+// definition files under dir, so changes can be detected without reading
+// them.
+func fingerprint(dir string) (string, error) {
+	files, err := definitionFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("stat %s: %w", path, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
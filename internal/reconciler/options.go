@@ -0,0 +1,138 @@
+package reconciler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client is the flag client the desired state is applied with.
+	Client *client.Client
+	// Directory holds the JSON and YAML flag definitions to apply.
+	Directory string
+
+	// ===== Optional ======
+
+	// Owner is the ownership marker written to every flag the reconciler
+	// manages. Only flags carrying it are updated or deleted. If not
+	// provided, it defaults to "reconciler".
+	Owner string
+	// Adopt lets the reconciler take over flags with the same name that
+	// were created by hand. Without it those flags are left alone and
+	// reported as skipped.
+	Adopt bool
+	// DryRun computes the changes without applying them.
+	DryRun bool
+	// PollInterval is how often the directory is checked for changes when
+	// running continuously. If not provided, it defaults to 2 seconds.
+	PollInterval time.Duration
+	// ResyncInterval is how often the flags are reconciled even if the
+	// directory did not change, to undo drift made outside of it. If not
+	// provided, it defaults to 1 minute.
+	ResyncInterval time.Duration
+	// Logger is the logger to use for the reconciler.
+	Logger *slog.Logger
+	// ParentContext is the parent context to use for the reconciler.
+	// If not provided, it defaults to context.Background().
+	ParentContext context.Context
+}
+
+func NewOptions(client *client.Client, directory string) *Options {
+	return &Options{
+		Client:    client,
+		Directory: directory,
+	}
+}
+
+func (opts *Options) WithOwner(owner string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Owner = owner
+	return opts
+}
+
+func (opts *Options) WithAdopt(adopt bool) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Adopt = adopt
+	return opts
+}
+
+func (opts *Options) WithDryRun(dryRun bool) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.DryRun = dryRun
+	return opts
+}
+
+func (opts *Options) WithPollInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.PollInterval = interval
+	return opts
+}
+
+func (opts *Options) WithResyncInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ResyncInterval = interval
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) WithParentContext(ctx context.Context) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ParentContext = ctx
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+	if opts.Directory == "" {
+		return mongoopenfeature.ErrMissingDirectory
+	}
+
+	// Setting defaults
+	if opts.Owner == "" {
+		opts.Owner = "reconciler"
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.ResyncInterval <= 0 {
+		opts.ResyncInterval = time.Minute
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.ParentContext == nil {
+		opts.ParentContext = context.Background()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package reconciler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// ActionKind is what the reconciler does to a flag.
+type ActionKind string
+
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionDelete ActionKind = "delete"
+	// ActionSkip marks a flag in the directory that the reconciler does
+	// not own, so it was left alone.
+	ActionSkip ActionKind = "skip"
+)
+
+// Action is the drift of a single flag and how it is corrected.
+type Action struct {
+	FlagName string        `json:"flagName"`
+	Kind     ActionKind    `json:"kind"`
+	Changes  []flag.Change `json:"changes,omitempty"`
+	// Reason explains skipped flags.
+	Reason string `json:"reason,omitempty"`
+
+	desired *flag.Definition
+	current *flag.Definition
+}
+
+func (a Action) String() string {
+	var b strings.Builder
+	switch a.Kind {
+	case ActionSkip:
+		fmt.Fprintf(&b, "! %s (skipped: %s)\n", a.FlagName, a.Reason)
+	case ActionDelete:
+		fmt.Fprintf(&b, "- %s (delete)\n", a.FlagName)
+	case ActionCreate:
+		fmt.Fprintf(&b, "+ %s (create)\n", a.FlagName)
+	default:
+		fmt.Fprintf(&b, "~ %s (update)\n", a.FlagName)
+	}
+	for _, change := range a.Changes {
+		fmt.Fprintf(&b, "    %s\n", change)
+	}
+	return b.String()
+}
+
+// Plan compares the desired definitions with the stored flags and returns
+// the actions that make the flags owned by owner match the directory,
+// sorted by flag name. Flags without owner's marker are never changed,
+// except that flags created by hand are taken over when adopt is set.
+func Plan(desired []flag.Definition, current map[string]flag.Definition, owner string, adopt bool) []Action {
+	var actions []Action
+	wanted := make(map[string]bool, len(desired))
+	for _, def := range desired {
+		def := def
+		def.ManagedBy = owner
+		def.Revision = 0
+		wanted[def.FlagName] = true
+
+		existing, ok := current[def.FlagName]
+		if !ok {
+			actions = append(actions, Action{FlagName: def.FlagName, Kind: ActionCreate, Changes: flag.Diff(nil, &def), desired: &def})
+			continue
+		}
+		if existing.ManagedBy != owner {
+			if existing.ManagedBy != "" {
+				actions = append(actions, Action{FlagName: def.FlagName, Kind: ActionSkip, Reason: fmt.Sprintf("managed by %s", existing.ManagedBy)})
+				continue
+			}
+			if !adopt {
+				actions = append(actions, Action{FlagName: def.FlagName, Kind: ActionSkip, Reason: "created by hand"})
+				continue
+			}
+		}
+		if changes := flag.Diff(&existing, &def); len(changes) > 0 {
+			actions = append(actions, Action{FlagName: def.FlagName, Kind: ActionUpdate, Changes: changes, desired: &def, current: &existing})
+		}
+	}
+
+	for name, existing := range current {
+		if existing.ManagedBy == owner && !wanted[name] {
+			existing := existing
+			actions = append(actions, Action{FlagName: name, Kind: ActionDelete, current: &existing})
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool { return actions[i].FlagName < actions[j].FlagName })
+	return actions
+}
+
+// batch turns the actions into batch operations. Updates are conditional on
+// the revision the plan was made from, so a concurrent edit fails the batch
+// instead of being overwritten unseen.
+func batch(actions []Action) []client.BatchOperation {
+	var ops []client.BatchOperation
+	for _, action := range actions {
+		switch action.Kind {
+		case ActionCreate:
+			ops = append(ops, client.SetOperation(*action.desired))
+		case ActionUpdate:
+			def := *action.desired
+			def.Revision = action.current.Revision
+			ops = append(ops, client.SetOperation(def))
+		case ActionDelete:
+			ops = append(ops, client.DeleteOperation(action.FlagName))
+		}
+	}
+	return ops
+}
@@ -0,0 +1,145 @@
+package reconciler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "checkout.json", `{"FlagName": "checkout", "DefaultValue": true, "DefaultVariant": "on"}`)
+	writeFile(t, dir, "team/limits.yaml", "version: 1\nflags:\n  - FlagName: max-items\n    DefaultValue: 10\n  - FlagName: max-users\n    DefaultValue: 3\n")
+	writeFile(t, dir, "README.md", "not a flag")
+	writeFile(t, dir, ".checkout.json.swp", "garbage")
+
+	definitions, err := LoadDirectory(dir)
+	if err != nil {
+		t.Fatalf("LoadDirectory: %v", err)
+	}
+	names := map[string]any{}
+	for _, def := range definitions {
+		names[def.FlagName] = def.DefaultValue
+	}
+	want := map[string]any{"checkout": true, "max-items": int64(10), "max-users": int64(3)}
+	if len(names) != len(want) {
+		t.Fatalf("loaded %v, want %v", names, want)
+	}
+	for name, value := range want {
+		if names[name] != value {
+			t.Fatalf("%s = %#v, want %#v", name, names[name], value)
+		}
+	}
+
+	writeFile(t, dir, "duplicate.yml", "FlagName: checkout\n")
+	if _, err := LoadDirectory(dir); err == nil {
+		t.Fatal("expected an error for a flag defined twice")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.json", `{"FlagName": "a"}`)
+
+	before, err := fingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "b.json", `{"FlagName": "b"}`)
+	after, err := fingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Fatal("expected the fingerprint to change when a file is added")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	const owner = "gitops"
+	desired := []flag.Definition{
+		{FlagName: "new-flag", DefaultValue: true},
+		{FlagName: "owned", DefaultValue: "b"},
+		{FlagName: "unchanged", DefaultValue: 1},
+		{FlagName: "by-hand", DefaultValue: false},
+		{FlagName: "other-owner", DefaultValue: false},
+	}
+	current := map[string]flag.Definition{
+		"owned":       {FlagName: "owned", DefaultValue: "a", ManagedBy: owner, Revision: 4},
+		"unchanged":   {FlagName: "unchanged", DefaultValue: 1, ManagedBy: owner, Revision: 2},
+		"by-hand":     {FlagName: "by-hand", DefaultValue: true, Revision: 1},
+		"other-owner": {FlagName: "other-owner", DefaultValue: true, ManagedBy: "terraform", Revision: 1},
+		"removed":     {FlagName: "removed", ManagedBy: owner, Revision: 7},
+		"hand-only":   {FlagName: "hand-only", Revision: 3},
+	}
+
+	kinds := func(actions []Action) map[string]ActionKind {
+		out := map[string]ActionKind{}
+		for _, action := range actions {
+			out[action.FlagName] = action.Kind
+		}
+		return out
+	}
+
+	t.Run("OwnedOnly", func(t *testing.T) {
+		actions := Plan(desired, current, owner, false)
+		want := map[string]ActionKind{
+			"by-hand":     ActionSkip,
+			"new-flag":    ActionCreate,
+			"other-owner": ActionSkip,
+			"owned":       ActionUpdate,
+			"removed":     ActionDelete,
+		}
+		got := kinds(actions)
+		if len(got) != len(want) {
+			t.Fatalf("actions = %v, want %v", got, want)
+		}
+		for name, kind := range want {
+			if got[name] != kind {
+				t.Fatalf("%s = %q, want %q", name, got[name], kind)
+			}
+		}
+
+		ops := batch(actions)
+		if len(ops) != 3 {
+			t.Fatalf("len(ops) = %d, want 3", len(ops))
+		}
+		for _, op := range ops {
+			if op.Set == nil {
+				continue
+			}
+			if op.Set.ManagedBy != owner {
+				t.Fatalf("%s is missing the ownership marker", op.Set.FlagName)
+			}
+			if op.Set.FlagName == "owned" && op.Set.Revision != 4 {
+				t.Fatalf("update of owned should be conditional on revision 4, got %d", op.Set.Revision)
+			}
+		}
+	})
+
+	t.Run("Adopt", func(t *testing.T) {
+		got := kinds(Plan(desired, current, owner, true))
+		if got["by-hand"] != ActionUpdate {
+			t.Fatalf("by-hand = %q, want %q", got["by-hand"], ActionUpdate)
+		}
+		if got["other-owner"] != ActionSkip {
+			t.Fatalf("other-owner = %q, want %q", got["other-owner"], ActionSkip)
+		}
+		if _, ok := got["hand-only"]; ok {
+			t.Fatal("flags created by hand and missing from the directory must not be deleted")
+		}
+	})
+}
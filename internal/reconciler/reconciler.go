@@ -0,0 +1,144 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+func New(opts *Options) (*Reconciler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating reconciler options: %w", err)
+	}
+	ctx, cancel := context.WithCancelCause(opts.ParentContext)
+	return &Reconciler{
+		ctx:    ctx,
+		cancel: cancel,
+
+		client:         opts.Client,
+		directory:      opts.Directory,
+		owner:          opts.Owner,
+		adopt:          opts.Adopt,
+		dryRun:         opts.DryRun,
+		pollInterval:   opts.PollInterval,
+		resyncInterval: opts.ResyncInterval,
+		logger:         opts.Logger,
+	}, nil
+}
+
+// Reconciler applies a directory of flag definitions to the flag store. It
+// only changes flags carrying its ownership marker, so flags created by hand
+// in the editor are left alone.
+type Reconciler struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	client         *client.Client
+	directory      string
+	owner          string
+	adopt          bool
+	dryRun         bool
+	pollInterval   time.Duration
+	resyncInterval time.Duration
+	logger         *slog.Logger
+}
+
+// Result is the outcome of a reconciliation.
+type Result struct {
+	Actions []Action `json:"actions"`
+	// Applied reports whether the actions were written, which is false for
+	// dry runs and when there was nothing to change.
+	Applied bool `json:"applied"`
+}
+
+// Changed returns the number of flags that were, or in a dry run would be,
+// created, updated or deleted.
+func (r *Result) Changed() int {
+	changed := 0
+	for _, action := range r.Actions {
+		if action.Kind != ActionSkip {
+			changed++
+		}
+	}
+	return changed
+}
+
+// Reconcile loads the directory, computes the drift against the stored flags
+// and applies it in a single batch.
+func (r *Reconciler) Reconcile(ctx context.Context) (*Result, error) {
+	desired, err := LoadDirectory(r.directory)
+	if err != nil {
+		return nil, fmt.Errorf("loading flag definitions: %w", err)
+	}
+	current, err := r.client.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading current flags: %w", err)
+	}
+
+	result := &Result{Actions: Plan(desired, current, r.owner, r.adopt)}
+	ops := batch(result.Actions)
+	if r.dryRun || len(ops) == 0 {
+		return result, nil
+	}
+
+	ctx = client.WithActor(ctx, client.Actor{Name: r.owner, Source: client.ActorSourceReconciler})
+	if err := r.client.ApplyBatch(ctx, ops); err != nil {
+		return nil, fmt.Errorf("applying flag changes: %w", err)
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// Run reconciles whenever the directory changes, and every resync interval
+// to undo drift, until Close is called.
+func (r *Reconciler) Run() {
+	poll := time.NewTicker(r.pollInterval)
+	defer poll.Stop()
+
+	var lastFingerprint string
+	var lastReconcile time.Time
+	for {
+		current, err := fingerprint(r.directory)
+		if err != nil {
+			r.logger.Error("error checking flag directory", "error", err, "directory", r.directory)
+		} else if current != lastFingerprint || time.Since(lastReconcile) >= r.resyncInterval {
+			if r.tick() {
+				lastFingerprint = current
+			}
+			lastReconcile = time.Now()
+		}
+
+		select {
+		case <-poll.C:
+		case <-r.ctx.Done():
+			r.logger.Info("reconciler stopped", "owner", r.owner)
+			return
+		}
+	}
+}
+
+// tick reconciles once and reports whether it succeeded.
+func (r *Reconciler) tick() bool {
+	result, err := r.Reconcile(r.ctx)
+	if err != nil {
+		r.logger.Error("error reconciling flags", "error", err, "directory", r.directory)
+		return false
+	}
+	for _, action := range result.Actions {
+		if action.Kind == ActionSkip {
+			r.logger.Warn("skipped flag not owned by reconciler", "flagName", action.FlagName, "reason", action.Reason)
+		} else if result.Applied {
+			r.logger.Info("reconciled flag", "flagName", action.FlagName, "action", action.Kind)
+		}
+	}
+	return true
+}
+
+func (r *Reconciler) Close() {
+	if r.cancel != nil {
+		r.cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,55 @@
+package relay
+
+import (
+	"fmt"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+)
+
+const (
+	// SnapshotPath returns every flag as a Snapshot.
+	SnapshotPath = "/relay/v1/snapshot"
+	// StreamPath is a server-sent event stream of changes. Clients pass
+	// the version they hold as the since query parameter (or the
+	// Last-Event-ID header when reconnecting) and receive the deltas after
+	// it, or a snapshot if those are no longer available.
+	StreamPath = "/relay/v1/stream"
+
+	// EventSnapshot carries a Snapshot that replaces everything the client
+	// holds.
+	EventSnapshot = "snapshot"
+	// EventDelta carries a Delta to apply on top of the previous version.
+	EventDelta = "delta"
+)
+
+// Snapshot is every flag at one version.
+type Snapshot struct {
+	Version uint64            `json:"version"`
+	Flags   []flag.Definition `json:"flags"`
+}
+
+// Delta is the change from Version-1 to Version.
+type Delta struct {
+	Version uint64            `json:"version"`
+	Set     []flag.Definition `json:"set,omitempty"`
+	Deleted []string          `json:"deleted,omitempty"`
+}
+
+// DecodeSnapshot decodes a snapshot, keeping integer flag values as int64.
+func DecodeSnapshot(data []byte) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := flagset.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// DecodeDelta decodes a delta, keeping integer flag values as int64.
+func DecodeDelta(data []byte) (*Delta, error) {
+	var delta Delta
+	if err := flagset.Unmarshal(data, &delta); err != nil {
+		return nil, fmt.Errorf("decoding delta: %w", err)
+	}
+	return &delta, nil
+}
@@ -0,0 +1,264 @@
+// Package relay serves the flags in a cache to many providers, so a single
+// process watches MongoDB instead of every service instance opening its own
+// change stream. Providers fetch a snapshot and then follow a server-sent
+// event stream of deltas; see relayprovider for the client.
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/httputil"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// maxHistory is how many deltas are kept for clients that reconnect. Clients
+// further behind are sent a snapshot instead.
+const maxHistory = 256
+
+func New(opts *Options) (*Server, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating relay options: %w", err)
+	}
+	s := &Server{
+		cache:             opts.Cache,
+		heartbeatInterval: opts.HeartbeatInterval,
+		logger:            opts.Logger,
+		flags:             map[string]flag.Definition{},
+		// Versions start at the current time so they keep increasing
+		// across restarts, and a client never mistakes a new relay's
+		// version for one it already holds.
+		version: uint64(time.Now().UnixNano()),
+		changed: make(chan struct{}),
+	}
+	if err := s.Refresh(); err != nil {
+		return nil, fmt.Errorf("building initial snapshot: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+SnapshotPath, s.handleSnapshot)
+	mux.HandleFunc("GET "+StreamPath, s.handleStream)
+	s.handler = httputil.RequireBearer(opts.Tokens, mux)
+	return s, nil
+}
+
+// Server is an http.Handler serving flag snapshots and deltas.
+type Server struct {
+	cache             *cache.Cache
+	heartbeatInterval time.Duration
+	logger            *slog.Logger
+	handler           http.Handler
+
+	mu      sync.Mutex
+	flags   map[string]flag.Definition
+	version uint64
+	// history holds the most recent deltas, oldest first.
+	history []encodedDelta
+	// snapshot is the encoded snapshot of version, built on first use.
+	snapshot []byte
+	// changed is closed and replaced whenever a new version is made,
+	// waking every stream waiting on it.
+	changed chan struct{}
+}
+
+type encodedDelta struct {
+	version uint64
+	data    []byte
+}
+
+// event is a server-sent event.
+type event struct {
+	name    string
+	version uint64
+	data    []byte
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// Refresh compares the cache with the last version served and, if any flag
+// changed, makes a new version and pushes its delta to connected clients. It
+// should be called whenever the watch handler reports a change.
+func (s *Server) Refresh() error {
+	current := s.cache.All()
+	// Providers key flags by FlagName, so make sure it matches the key
+	// the flag is cached under.
+	for name, definition := range current {
+		definition.FlagName = name
+		current[name] = definition
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delta := Delta{Version: s.version + 1}
+	for _, name := range sortedKeys(current) {
+		if previous, ok := s.flags[name]; !ok || !reflect.DeepEqual(previous, current[name]) {
+			delta.Set = append(delta.Set, current[name])
+		}
+	}
+	for _, name := range sortedKeys(s.flags) {
+		if _, ok := current[name]; !ok {
+			delta.Deleted = append(delta.Deleted, name)
+		}
+	}
+	if len(delta.Set) == 0 && len(delta.Deleted) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("encoding delta: %w", err)
+	}
+	s.history = append(s.history, encodedDelta{version: delta.Version, data: data})
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+	}
+	s.flags = current
+	s.version = delta.Version
+	s.snapshot = nil
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+// encodedSnapshot returns the snapshot of the current version. The caller
+// must hold s.mu.
+func (s *Server) encodedSnapshot() ([]byte, error) {
+	if s.snapshot != nil {
+		return s.snapshot, nil
+	}
+	snapshot := Snapshot{Version: s.version, Flags: make([]flag.Definition, 0, len(s.flags))}
+	for _, name := range sortedKeys(s.flags) {
+		snapshot.Flags = append(snapshot.Flags, s.flags[name])
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("encoding snapshot: %w", err)
+	}
+	s.snapshot = data
+	return data, nil
+}
+
+// eventsSince returns what a client holding since needs to catch up: nothing
+// if it is current, the deltas after since if they are still in the history,
+// and a snapshot otherwise. The returned channel is closed on the next change.
+func (s *Server) eventsSince(since uint64) ([]event, <-chan struct{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if since == s.version {
+		return nil, s.changed, nil
+	}
+	if since < s.version && len(s.history) > 0 && s.history[0].version <= since+1 {
+		var events []event
+		for _, delta := range s.history {
+			if delta.version > since {
+				events = append(events, event{name: EventDelta, version: delta.version, data: delta.data})
+			}
+		}
+		return events, s.changed, nil
+	}
+	data, err := s.encodedSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	return []event{{name: EventSnapshot, version: s.version, data: data}}, s.changed, nil
+}
+
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, err := s.encodedSnapshot()
+	s.mu.Unlock()
+	if err != nil {
+		s.logger.Error("building snapshot", "error", err)
+		http.Error(w, "building snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	etag := httputil.ETag(data)
+	w.Header().Set("ETag", etag)
+	if httputil.ETagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		s.logger.Error("writing snapshot", "error", err)
+	}
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	var version uint64
+	if since != "" {
+		var err error
+		if version, err = strconv.ParseUint(since, 10, 64); err != nil {
+			http.Error(w, "invalid since version", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		events, changed, err := s.eventsSince(version)
+		if err != nil {
+			s.logger.Error("building relay events", "error", err)
+			return
+		}
+		for _, e := range events {
+			if _, err := fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", e.name, e.version, e.data); err != nil {
+				return
+			}
+			version = e.version
+		}
+		flusher.Flush()
+
+		for waiting := true; waiting; {
+			select {
+			case <-changed:
+				waiting = false
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func sortedKeys(m map[string]flag.Definition) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+package relay
+
+import (
+	"testing"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestEventsSince(t *testing.T) {
+	c := cache.New()
+	if err := c.Set("a", flag.Definition{FlagName: "a", DefaultValue: true}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(NewOptions(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := s.version
+
+	if err := c.Set("b", flag.Definition{FlagName: "b", DefaultValue: int64(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Apply(nil, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if s.version != start+2 {
+		t.Fatalf("version = %d, want %d", s.version, start+2)
+	}
+
+	names := func(since uint64) []string {
+		events, _, err := s.eventsSince(since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, e := range events {
+			out = append(out, e.name)
+		}
+		return out
+	}
+
+	if got := names(s.version); len(got) != 0 {
+		t.Fatalf("current client got %v", got)
+	}
+	if got := names(start); len(got) != 2 || got[0] != EventDelta || got[1] != EventDelta {
+		t.Fatalf("client two versions behind got %v", got)
+	}
+	if got := names(0); len(got) != 1 || got[0] != EventSnapshot {
+		t.Fatalf("new client got %v", got)
+	}
+	if got := names(s.version + 5); len(got) != 1 || got[0] != EventSnapshot {
+		t.Fatalf("client ahead of the relay got %v", got)
+	}
+
+	events, _, _ := s.eventsSince(start + 1)
+	delta, err := DecodeDelta(events[0].data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delta.Deleted) != 1 || delta.Deleted[0] != "a" || len(delta.Set) != 0 {
+		t.Fatalf("delta = %+v", delta)
+	}
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client is the flag client used to claim and apply scheduled changes.
+	Client *client.Client
+
+	// ===== Optional ======
+
+	// Owner identifies this scheduler when leasing changes. If not
+	// provided, it defaults to the hostname and process ID.
+	Owner string
+	// Interval is how often the scheduler checks for due changes.
+	// If not provided, it defaults to 15 seconds.
+	Interval time.Duration
+	// LeaseDuration is how long a claimed change stays leased to this
+	// scheduler. If not provided, it defaults to 1 minute.
+	LeaseDuration time.Duration
+	// Logger is the logger to use for the scheduler.
+	Logger *slog.Logger
+	// ParentContext is the parent context to use for the scheduler.
+	// If not provided, it defaults to context.Background().
+	ParentContext context.Context
+}
+
+func NewOptions(client *client.Client) *Options {
+	return &Options{
+		Client: client,
+	}
+}
+
+func (opts *Options) WithOwner(owner string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Owner = owner
+	return opts
+}
+
+func (opts *Options) WithInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Interval = interval
+	return opts
+}
+
+func (opts *Options) WithLeaseDuration(lease time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.LeaseDuration = lease
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) WithParentContext(ctx context.Context) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ParentContext = ctx
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+
+	// Setting defaults
+	if opts.Owner == "" {
+		opts.Owner = defaultOwner()
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 15 * time.Second
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = time.Minute
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.ParentContext == nil {
+		opts.ParentContext = context.Background()
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+func New(opts *Options) (*Scheduler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating scheduler options: %w", err)
+	}
+	ctx, cancel := context.WithCancelCause(opts.ParentContext)
+	return &Scheduler{
+		ctx:    ctx,
+		cancel: cancel,
+
+		client:   opts.Client,
+		owner:    opts.Owner,
+		interval: opts.Interval,
+		lease:    opts.LeaseDuration,
+		logger:   opts.Logger,
+	}, nil
+}
+
+// Scheduler periodically applies scheduled flag changes that are due.
+// Several schedulers can run against the same collections; changes are
+// leased in Mongo, and the lease is renewed while a change is applied, so
+// each one is normally applied by a single scheduler. See
+// client.Client.ApplyDueChanges for when a change can be applied again.
+type Scheduler struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	client   *client.Client
+	owner    string
+	interval time.Duration
+	lease    time.Duration
+	logger   *slog.Logger
+}
+
+// Run applies due changes every interval until Close is called.
+func (s *Scheduler) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.tick()
+	for {
+		select {
+		case <-ticker.C:
+			s.tick()
+		case <-s.ctx.Done():
+			s.logger.Info("scheduler stopped", "owner", s.owner)
+			return
+		}
+	}
+}
+
+func (s *Scheduler) tick() {
+	applied, err := s.client.ApplyDueChanges(s.ctx, s.owner, s.lease)
+	if err != nil {
+		s.logger.Error("error applying scheduled changes", "error", err, "owner", s.owner)
+	}
+	if applied > 0 {
+		s.logger.Info("applied scheduled changes", "count", applied, "owner", s.owner)
+	}
+}
+
+func (s *Scheduler) Close() {
+	if s.cancel != nil {
+		s.cancel(context.Canceled)
+	}
+}
+
+func defaultOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/internal/testutil"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	var nilOpts *Options
+	if err := nilOpts.Validate(); err != mongoopenfeature.ErrNilOptions {
+		t.Fatalf("expected ErrNilOptions, got %v", err)
+	}
+	if err := NewOptions(nil).Validate(); err != mongoopenfeature.ErrMissingClient {
+		t.Fatalf("expected ErrMissingClient, got %v", err)
+	}
+
+	opts := NewOptions(&client.Client{})
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("validating options: %v", err)
+	}
+	if opts.Owner == "" || opts.Interval != 15*time.Second || opts.LeaseDuration != time.Minute {
+		t.Errorf("expected defaults, got owner %q, interval %s, lease %s", opts.Owner, opts.Interval, opts.LeaseDuration)
+	}
+}
+
+// TestSchedulersApplyOnce runs two schedulers against the same due changes
+// and checks each change is applied once.
+func TestSchedulersApplyOnce(t *testing.T) {
+	mongoClient := testutil.MongoClient(t)
+	c, err := client.New(client.NewOptions(mongoClient, testutil.DatabaseName(t, mongoClient), "flags"))
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"checkout", "search", "banner"} {
+		def := flag.Definition{FlagName: name, DefaultValue: true, DefaultVariant: "on"}
+		if _, err := c.ScheduleChange(ctx, client.ScheduledChange{
+			FlagName:   name,
+			Action:     client.ScheduledActionSet,
+			ApplyAt:    time.Now().Add(-time.Minute),
+			Definition: &def,
+		}); err != nil {
+			t.Fatalf("scheduling change: %v", err)
+		}
+	}
+
+	for _, owner := range []string{"a", "b"} {
+		s, err := New(NewOptions(c).WithOwner(owner).WithInterval(10 * time.Millisecond))
+		if err != nil {
+			t.Fatalf("creating scheduler: %v", err)
+		}
+		go s.Run()
+		defer s.Close()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		changes, err := c.ListScheduledChanges(ctx, "")
+		if err != nil {
+			t.Fatalf("listing scheduled changes: %v", err)
+		}
+		applied := 0
+		for _, change := range changes {
+			if change.Status == client.ScheduledStatusApplied {
+				applied++
+			}
+		}
+		if applied == len(changes) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected every change to be applied, got %d of %d", applied, len(changes))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, name := range []string{"checkout", "search", "banner"} {
+		versions, err := c.ListVersions(ctx, name)
+		if err != nil {
+			t.Fatalf("listing versions of %s: %v", name, err)
+		}
+		if len(versions) != 1 {
+			t.Errorf("expected %s to be written once, got %d versions", name, len(versions))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	containerOnce sync.Once
+	containerErr  error
+)
+
+// MongoClient connects to MONGODB_ENDPOINT, or to a MongoDB container started
+// once per test binary if it is not set. The test is skipped when neither is
+// available. The client is disconnected when the test finishes.
+func MongoClient(t *testing.T) *mongo.Client {
+	t.Helper()
+	if os.Getenv("MONGODB_ENDPOINT") == "" {
+		testcontainers.SkipIfProviderIsNotHealthy(t)
+		// The container is removed by testcontainers when the test binary
+		// exits.
+		containerOnce.Do(func() {
+			_, containerErr = CreateMongoContainer(context.Background())
+		})
+		if containerErr != nil {
+			t.Skipf("MongoDB is not available: %v", containerErr)
+		}
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(os.Getenv("MONGODB_ENDPOINT")))
+	if err != nil {
+		t.Fatalf("connecting to MongoDB: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+	})
+	return client
+}
+
+// DatabaseName returns a database name that is unique to the test, and drops
+// the database when the test finishes.
+func DatabaseName(t *testing.T, client *mongo.Client) string {
+	t.Helper()
+	name := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_ = client.Database(name).Drop(context.Background())
+	})
+	return name
+}
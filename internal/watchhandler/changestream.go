@@ -3,6 +3,7 @@ package watchhandler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -14,7 +15,7 @@ func (w *WatchHandler) changestream() error {
 	if w.documentID != "" {
 		pipeline = mongo.Pipeline{
 			{{Key: "$match", Value: bson.M{
-				"operationType":    bson.M{"$in": []string{"insert", "update ", "replace", "delete"}},
+				"operationType":    bson.M{"$in": []string{"insert", "update", "replace", "delete"}},
 				"fullDocument._id": w.documentID,
 			}}},
 		}
@@ -35,18 +36,48 @@ func (w *WatchHandler) changestream() error {
 		return fmt.Errorf("starting change stream: %w", err)
 	}
 	defer cs.Close(context.WithoutCancel(w.ctx))
+	// An open stream delivers every change from here on, so the cache is
+	// as current as MongoDB.
+	w.metrics.Synced(time.Now())
 
-	for cs.Next(ctx) {
-		if err := cs.Err(); err != nil {
-			return fmt.Errorf("change stream error: %w", err)
+	// Events written by one transaction arrive back to back. The ones the
+	// stream has already buffered are applied to the cache together, so
+	// evaluations usually see the whole transaction or none of it. Change
+	// streams do not mark the end of a transaction, so one that spans more
+	// than one batch from the server is still applied in parts.
+	var pending []ChangeStreamEvent
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		events := pending
+		pending = nil
+		if err := w.handleEvents(events); err != nil {
+			return fmt.Errorf("handling change stream events: %w", err)
 		}
-		var csEvent ChangeStreamEvent
-		if err := cs.Decode(&csEvent); err != nil {
-			return fmt.Errorf("decoding change stream document: %w", err)
+		return nil
+	}
+	for cs.Next(ctx) {
+		for {
+			if err := cs.Err(); err != nil {
+				return fmt.Errorf("change stream error: %w", err)
+			}
+			var csEvent ChangeStreamEvent
+			if err := cs.Decode(&csEvent); err != nil {
+				return fmt.Errorf("decoding change stream document: %w", err)
+			}
+			if len(pending) > 0 && !pending[0].sameTransaction(csEvent) {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			pending = append(pending, csEvent)
+			if csEvent.TxnNumber == nil || !cs.TryNext(ctx) {
+				break
+			}
 		}
-		fmt.Println(csEvent)
-		if err := w.handleEvent(csEvent); err != nil {
-			return fmt.Errorf("handling change stream event: %w", err)
+		if err := flush(); err != nil {
+			return err
 		}
 	}
 	if err := cs.Err(); err != nil {
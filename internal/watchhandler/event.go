@@ -2,11 +2,34 @@ package watchhandler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func (w *WatchHandler) handleEvent(event ChangeStreamEvent) error {
+	return w.handleEvents([]ChangeStreamEvent{event})
+}
+
+// handleEvents applies events that belong together, such as the writes of one
+// transaction, as a single change.
+func (w *WatchHandler) handleEvents(events []ChangeStreamEvent) error {
+	var err error
+	if w.documentID != "" {
+		// Every event carries the whole flag document, so the last one
+		// is the result of all of them.
+		err = w.handleEventSingleDocument(events[len(events)-1])
+	} else {
+		err = w.handleEventsAllDocuments(events)
+	}
+	if err != nil {
+		return err
+	}
+	w.metrics.Synced(time.Now())
+
+	// Published once the cache holds the change, so handlers that
+	// evaluate flags in response see the new values.
 	if w.eventHandler != nil {
 		w.eventHandler.Publish(openfeature.Event{
 			ProviderName: "WatchHandler",
@@ -16,10 +39,7 @@ func (w *WatchHandler) handleEvent(event ChangeStreamEvent) error {
 			},
 		})
 	}
-	if w.documentID != "" {
-		return w.handleEventSingleDocument(event)
-	}
-	return w.handleEventAllDocuments(event)
+	return nil
 }
 
 func (w *WatchHandler) handleEventSingleDocument(event ChangeStreamEvent) error {
@@ -27,35 +47,67 @@ func (w *WatchHandler) handleEventSingleDocument(event ChangeStreamEvent) error
 		return fmt.Errorf("change document ID does not match expected ID: %v != %v", id, w.documentID)
 	}
 	delete(event.FullDocument, "_id")
-	w.cache.Clear()
 
+	// The document holds every flag, so flags missing from it were deleted.
+	// The cache is replaced in one change, so evaluations never see it empty
+	// or half filled.
+	sets := make(map[string]any, len(event.FullDocument))
 	for key, value := range event.FullDocument {
-		if err := w.cache.Set(key, value); err != nil {
-			return fmt.Errorf("setting cache value for key %s: %w", key, err)
+		sets[key] = value
+	}
+	var deletes []string
+	for key := range w.cache.All() {
+		if _, ok := sets[key]; !ok {
+			deletes = append(deletes, key)
 		}
 	}
+	if err := w.cache.Apply(sets, deletes); err != nil {
+		return fmt.Errorf("applying document to cache: %w", err)
+	}
 
 	return nil
 }
 
-func (w *WatchHandler) handleEventAllDocuments(event ChangeStreamEvent) error {
-	if event.FullDocument == nil {
-		return fmt.Errorf("change event does not contain full document")
+func (w *WatchHandler) handleEventsAllDocuments(events []ChangeStreamEvent) error {
+	sets := make(map[string]any, len(events))
+	var deletes []string
+	for _, event := range events {
+		if event.OperationType == "delete" {
+			idString, err := documentIDString(event.DocumentKey)
+			if err != nil {
+				return fmt.Errorf("delete event: %w", err)
+			}
+			delete(sets, idString)
+			deletes = append(deletes, idString)
+			continue
+		}
+
+		if event.FullDocument == nil {
+			return fmt.Errorf("change event does not contain full document")
+		}
+		idString, err := documentIDString(event.FullDocument)
+		if err != nil {
+			return err
+		}
+		delete(event.FullDocument, "_id")
+		sets[idString] = event.FullDocument
+	}
+
+	if err := w.cache.Apply(sets, deletes); err != nil {
+		return fmt.Errorf("applying changes to cache: %w", err)
 	}
 
-	id, ok := event.FullDocument["_id"]
+	return nil
+}
+
+func documentIDString(doc bson.M) (string, error) {
+	id, ok := doc["_id"]
 	if !ok {
-		return fmt.Errorf("change event does not contain document ID")
+		return "", fmt.Errorf("change event does not contain document ID")
 	}
 	idString, ok := id.(string)
 	if !ok {
-		return fmt.Errorf("document ID is not a string: %v", id)
-	}
-
-	delete(event.FullDocument, "_id")
-	if err := w.cache.Set(idString, event.FullDocument); err != nil {
-		return fmt.Errorf("setting cache value for document ID %s: %w", idString, err)
+		return "", fmt.Errorf("document ID is not a string: %v", id)
 	}
-
-	return nil
+	return idString, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
 	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
 	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -40,6 +41,9 @@ type Options struct {
 	// ParentContext is the parent context to use for the watch handler.
 	// If not provided, it defaults to context.Background().
 	ParentContext context.Context
+	// Metrics records the watch state, reconnects and syncs. If not
+	// provided, it defaults to metrics.Nop.
+	Metrics metrics.Metrics
 }
 
 func NewOptions(client *mongo.Client, database, collection string, cache *cache.Cache) *Options {
@@ -91,6 +95,14 @@ func (opts *Options) WithParentContext(ctx context.Context) *Options {
 	return opts
 }
 
+func (opts *Options) WithMetrics(m metrics.Metrics) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Metrics = m
+	return opts
+}
+
 func (opts *Options) Validate() error {
 	if opts == nil {
 		return mongoopenfeature.ErrNilOptions
@@ -119,5 +131,8 @@ func (opts *Options) Validate() error {
 	if opts.ParentContext == nil {
 		opts.ParentContext = context.Background()
 	}
+	if opts.Metrics == nil {
+		opts.Metrics = metrics.Nop{}
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package watchhandler
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
@@ -8,6 +9,7 @@ import (
 	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
 	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -28,6 +30,7 @@ func New(opts *Options) (*WatchHandler, error) {
 		eventHandler: opts.EventHandler,
 		cache:        opts.Cache,
 		logger:       opts.Logger,
+		metrics:      opts.Metrics,
 	}, nil
 }
 
@@ -42,16 +45,33 @@ type WatchHandler struct {
 	eventHandler *eventhandler.EventHandler
 	cache        *cache.Cache
 	logger       *slog.Logger
+	metrics      metrics.Metrics
 }
 
 type ChangeStreamEvent struct {
 	FullDocument  bson.M `bson:"fullDocument"`
 	OperationType string `bson:"operationType"`
+	DocumentKey   bson.M `bson:"documentKey"`
+	// TxnNumber and LSID identify the transaction that made the change,
+	// if any, so the events of one transaction can be applied together.
+	TxnNumber *int64   `bson:"txnNumber,omitempty"`
+	LSID      bson.Raw `bson:"lsid,omitempty"`
+}
+
+// sameTransaction reports whether both events were written by the same
+// transaction.
+func (e ChangeStreamEvent) sameTransaction(other ChangeStreamEvent) bool {
+	return e.TxnNumber != nil && other.TxnNumber != nil &&
+		*e.TxnNumber == *other.TxnNumber && bytes.Equal(e.LSID, other.LSID)
 }
 
 func (w *WatchHandler) Watch() {
 	success := false
 	for attempt := 1; attempt <= w.maxTries; attempt++ {
+		if attempt > 1 {
+			w.metrics.WatchReconnect(metrics.WatchStateChangeStream)
+		}
+		w.metrics.WatchState(metrics.WatchStateChangeStream)
 		err := w.changestream()
 		if err == nil {
 			success = true
@@ -66,6 +86,10 @@ func (w *WatchHandler) Watch() {
 
 	// Fallback to polling
 	for attempt := 1; attempt <= w.maxTries; attempt++ {
+		if attempt > 1 {
+			w.metrics.WatchReconnect(metrics.WatchStatePolling)
+		}
+		w.metrics.WatchState(metrics.WatchStatePolling)
 		err := w.polling()
 		if err == nil {
 			success = true
@@ -78,6 +102,7 @@ func (w *WatchHandler) Watch() {
 	}
 
 	w.logger.Error("max retries reached, stopping watch", "tries", w.maxTries, "documentID", w.documentID)
+	w.metrics.WatchState(metrics.WatchStateStopped)
 	if w.eventHandler != nil {
 		w.eventHandler.BPublish(openfeature.Event{
 			ProviderName: "WatchHandler",
@@ -0,0 +1,175 @@
+package watchhandler
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/testutil"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSameTransaction(t *testing.T) {
+	lsid, err := bson.Marshal(bson.M{"id": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherLSID, err := bson.Marshal(bson.M{"id": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := func(n int64) *int64 { return &n }
+
+	for name, tc := range map[string]struct {
+		a, b ChangeStreamEvent
+		want bool
+	}{
+		"SameTransaction":    {a: ChangeStreamEvent{TxnNumber: txn(1), LSID: lsid}, b: ChangeStreamEvent{TxnNumber: txn(1), LSID: lsid}, want: true},
+		"OtherTxnNumber":     {a: ChangeStreamEvent{TxnNumber: txn(1), LSID: lsid}, b: ChangeStreamEvent{TxnNumber: txn(2), LSID: lsid}},
+		"OtherSession":       {a: ChangeStreamEvent{TxnNumber: txn(1), LSID: lsid}, b: ChangeStreamEvent{TxnNumber: txn(1), LSID: otherLSID}},
+		"NoTransaction":      {a: ChangeStreamEvent{}, b: ChangeStreamEvent{}},
+		"OneWithTransaction": {a: ChangeStreamEvent{TxnNumber: txn(1), LSID: lsid}, b: ChangeStreamEvent{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.a.sameTransaction(tc.b); got != tc.want {
+				t.Errorf("sameTransaction = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandleEventsAppliesTogether(t *testing.T) {
+	flagCache := cache.New()
+	if err := flagCache.Set("search", flag.Definition{FlagName: "search", DefaultVariant: "off"}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := eventhandler.New(eventhandler.NewOptions(func(openfeature.Event) {}).WithMetrics(metrics.Nop{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &WatchHandler{cache: flagCache, eventHandler: events, logger: slog.Default(), metrics: metrics.Nop{}}
+
+	err = w.handleEvents([]ChangeStreamEvent{
+		{OperationType: "insert", FullDocument: bson.M{"_id": "checkout", "defaultVariant": "off"}},
+		{OperationType: "update", FullDocument: bson.M{"_id": "checkout", "defaultVariant": "on"}},
+		{OperationType: "delete", DocumentKey: bson.M{"_id": "search"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if def, ok := flagCache.Get("checkout"); !ok || def.DefaultVariant != "on" {
+		t.Errorf("checkout = %+v, %t; want the last write", def, ok)
+	}
+	if _, ok := flagCache.Get("search"); ok {
+		t.Error("search should be deleted")
+	}
+	if n := len(events.EventChannel()); n != 1 {
+		t.Errorf("published %d events, want one for the whole group", n)
+	}
+}
+
+// syncMetrics reports every sync, so tests can wait for the change stream to
+// open.
+type syncMetrics struct {
+	metrics.Nop
+	synced chan struct{}
+}
+
+func (m syncMetrics) Synced(time.Time) {
+	select {
+	case m.synced <- struct{}{}:
+	default:
+	}
+}
+
+// startWatch watches the flag collection of opts and returns the cache
+// and event handler it updates, once the change stream is open.
+func startWatch(t *testing.T, opts *client.Options) (*cache.Cache, *eventhandler.EventHandler) {
+	t.Helper()
+	flagCache := cache.New()
+	events, err := eventhandler.New(eventhandler.NewOptions(func(openfeature.Event) {}).WithMetrics(metrics.Nop{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := syncMetrics{synced: make(chan struct{}, 1)}
+	w, err := New(NewOptions(opts.Client, opts.Database, opts.Collection, flagCache).
+		WithDocumentID(opts.DocumentID).
+		WithEventHandler(events).
+		WithMetrics(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go w.Watch()
+	t.Cleanup(w.Close)
+
+	select {
+	case <-m.synced:
+	case <-time.After(10 * time.Second):
+		t.Fatal("change stream did not open")
+	}
+	return flagCache, events
+}
+
+// waitForChange waits for the watch handler to publish a configuration
+// change.
+func waitForChange(t *testing.T, events *eventhandler.EventHandler) {
+	t.Helper()
+	select {
+	case event := <-events.EventChannel():
+		if event.EventType != openfeature.ProviderConfigChange {
+			t.Fatalf("got %s event, want a configuration change", event.EventType)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("no change was published")
+	}
+}
+
+func TestWatchBatch(t *testing.T) {
+	for name, documentID := range map[string]string{
+		"MultiDocument":  "",
+		"SingleDocument": "flags",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mongoClient := testutil.MongoClient(t)
+			opts := client.NewOptions(mongoClient, testutil.DatabaseName(t, mongoClient), "flags").WithDocumentID(documentID)
+			c, err := client.New(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := c.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "off"}); err != nil {
+				t.Fatal(err)
+			}
+
+			flagCache, events := startWatch(t, opts)
+			err = c.ApplyBatch(ctx, []client.BatchOperation{
+				client.SetOperation(flag.Definition{FlagName: "checkout", DefaultVariant: "on"}),
+				client.SetOperation(flag.Definition{FlagName: "banner", DefaultVariant: "on"}),
+				client.DeleteOperation("search"),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The batch is one transaction, or one update of the flag
+			// document, so its first change holds all of it.
+			waitForChange(t, events)
+			for _, name := range []string{"checkout", "banner"} {
+				if _, ok := flagCache.Get(name); !ok {
+					t.Errorf("%s is missing from the cache after the first change", name)
+				}
+			}
+			if _, ok := flagCache.Get("search"); ok {
+				t.Error("search is still cached after the first change")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client is the flag client used to read flags and webhooks and to
+	// record dead letters.
+	Client *client.Client
+
+	// ===== Optional ======
+
+	// HTTPClient sends the deliveries. If not provided, it defaults to a
+	// client with a 10 second timeout.
+	HTTPClient *http.Client
+	// MaxAttempts is how many times a delivery is tried before it is
+	// dead-lettered. If not provided, it defaults to 5.
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failed attempt. It
+	// doubles after every further failure, up to MaxBackoff. If not
+	// provided, it defaults to 1 second.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts. If not provided, it
+	// defaults to 1 minute.
+	MaxBackoff time.Duration
+	// Logger is the logger to use for the dispatcher.
+	Logger *slog.Logger
+	// ParentContext is the parent context to use for the dispatcher.
+	// If not provided, it defaults to context.Background().
+	ParentContext context.Context
+}
+
+func NewOptions(client *client.Client) *Options {
+	return &Options{
+		Client: client,
+	}
+}
+
+func (opts *Options) WithHTTPClient(httpClient *http.Client) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.HTTPClient = httpClient
+	return opts
+}
+
+func (opts *Options) WithMaxAttempts(maxAttempts int) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.MaxAttempts = maxAttempts
+	return opts
+}
+
+func (opts *Options) WithBackoff(initial, max time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.InitialBackoff = initial
+	opts.MaxBackoff = max
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) WithParentContext(ctx context.Context) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ParentContext = ctx
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+
+	// Setting defaults
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.ParentContext == nil {
+		opts.ParentContext = context.Background()
+	}
+	return nil
+}
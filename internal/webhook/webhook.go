@@ -0,0 +1,293 @@
+// Package webhook sends signed HTTP POSTs to the webhooks configured through
+// the client whenever a flag they cover changes. Deliveries are retried with
+// exponential backoff, and ones that fail every attempt are recorded as dead
+// letters.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Event is the kind of flag change a delivery reports.
+type Event string
+
+const (
+	EventFlagCreated Event = "flag.created"
+	EventFlagUpdated Event = "flag.updated"
+	EventFlagDeleted Event = "flag.deleted"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderID        = "X-Flag-Webhook-Id"
+	HeaderEvent     = "X-Flag-Webhook-Event"
+	HeaderTimestamp = "X-Flag-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" followed by the hex HMAC-SHA256 of
+	// the timestamp header, a ".", and the body, keyed with the
+	// webhook's secret. See Sign.
+	HeaderSignature = "X-Flag-Webhook-Signature"
+)
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	// ID identifies the delivery; it is the same for every attempt.
+	ID       string `json:"id"`
+	Event    Event  `json:"event"`
+	FlagName string `json:"flagName"`
+	Category string `json:"category,omitempty"`
+	// Revision is the flag's revision after the change, or 0 if it was
+	// deleted.
+	Revision int64 `json:"revision,omitempty"`
+	// Changes lists the field-level differences made by the change.
+	Changes   []flag.Change `json:"changes"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// Sign returns the signature header value for a delivery body sent at
+// timestamp (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func New(opts *Options) (*Dispatcher, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating webhook options: %w", err)
+	}
+	ctx, cancel := context.WithCancelCause(opts.ParentContext)
+	return &Dispatcher{
+		ctx:    ctx,
+		cancel: cancel,
+		client: opts.Client,
+		deliverer: deliverer{
+			httpClient:     opts.HTTPClient,
+			maxAttempts:    opts.MaxAttempts,
+			initialBackoff: opts.InitialBackoff,
+			maxBackoff:     opts.MaxBackoff,
+			logger:         opts.Logger,
+			deadLetter:     opts.Client.AddDeadLetter,
+		},
+		logger: opts.Logger,
+	}, nil
+}
+
+// Dispatcher compares the flags with the last ones it saw and delivers the
+// differences to the webhooks that cover them. Only one dispatcher should run
+// against a collection, or every change is delivered more than once.
+type Dispatcher struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	client    *client.Client
+	deliverer deliverer
+	logger    *slog.Logger
+
+	mu sync.Mutex
+	// flags is what the last Check saw; nil until the first one.
+	flags map[string]flag.Definition
+	wg    sync.WaitGroup
+}
+
+// Check loads every flag and delivers the changes since the previous check.
+// The first check only records the flags, so changes made while no
+// dispatcher was running are not delivered.
+func (d *Dispatcher) Check() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	current, err := d.client.GetAllFlags(d.ctx)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("getting all flags: %w", err)
+	}
+	if current == nil {
+		current = map[string]flag.Definition{}
+	}
+	if d.flags == nil {
+		d.flags = current
+		return nil
+	}
+
+	payloads := detectChanges(d.flags, current, time.Now().UTC())
+	if len(payloads) == 0 {
+		d.flags = current
+		return nil
+	}
+	webhooks, err := d.client.ListWebhooks(d.ctx)
+	if err != nil {
+		// The flags are not recorded, so the next check tries again.
+		return fmt.Errorf("listing webhooks: %w", err)
+	}
+	d.flags = current
+
+	for _, payload := range payloads {
+		for _, webhook := range webhooks {
+			if !webhook.Covers(payload.FlagName, payload.Category) {
+				continue
+			}
+			d.wg.Add(1)
+			go func(webhook client.Webhook, payload Payload) {
+				defer d.wg.Done()
+				d.deliverer.deliver(d.ctx, webhook, payload)
+			}(webhook, payload)
+		}
+	}
+	return nil
+}
+
+// Close stops the dispatcher. Deliveries still waiting to be retried are
+// dead-lettered rather than dropped.
+func (d *Dispatcher) Close() {
+	if d.cancel != nil {
+		d.cancel(context.Canceled)
+	}
+	d.wg.Wait()
+}
+
+// detectChanges returns a payload for every flag that was created, deleted or
+// changed between previous and current, ordered by flag name. Writes that
+// only bumped the revision are not changes.
+func detectChanges(previous, current map[string]flag.Definition, now time.Time) []Payload {
+	var payloads []Payload
+	add := func(event Event, name string, before, after *flag.Definition) {
+		var changes []flag.Change
+		for _, change := range flag.Diff(before, after) {
+			if change.Path != "Revision" {
+				changes = append(changes, change)
+			}
+		}
+		if event == EventFlagUpdated && len(changes) == 0 {
+			return
+		}
+		payload := Payload{
+			ID:        bson.NewObjectID().Hex(),
+			Event:     event,
+			FlagName:  name,
+			Changes:   changes,
+			Timestamp: now,
+		}
+		if after != nil {
+			payload.Category = after.Category
+			payload.Revision = after.Revision
+		} else {
+			payload.Category = before.Category
+		}
+		payloads = append(payloads, payload)
+	}
+
+	for name, after := range current {
+		before, ok := previous[name]
+		if !ok {
+			add(EventFlagCreated, name, nil, &after)
+			continue
+		}
+		add(EventFlagUpdated, name, &before, &after)
+	}
+	for name, before := range previous {
+		if _, ok := current[name]; !ok {
+			add(EventFlagDeleted, name, &before, nil)
+		}
+	}
+	sort.Slice(payloads, func(i, j int) bool { return payloads[i].FlagName < payloads[j].FlagName })
+	return payloads
+}
+
+// deliverer sends payloads to webhooks with retries.
+type deliverer struct {
+	httpClient     *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logger         *slog.Logger
+	deadLetter     func(context.Context, client.DeadLetter) error
+}
+
+// deliver sends payload to webhook until it is accepted, the attempts run
+// out, or ctx is done. Undelivered payloads are dead-lettered.
+func (d *deliverer) deliver(ctx context.Context, webhook client.Webhook, payload Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.logger.Error("encoding webhook payload", "error", err, "flagName", payload.FlagName)
+		return
+	}
+
+	backoff := d.initialBackoff
+	attempts := 0
+	for attempts < d.maxAttempts {
+		attempts++
+		err = d.send(ctx, webhook, payload, body)
+		if err == nil {
+			return
+		}
+		d.logger.Error("error delivering webhook, retrying", "attempt", attempts, "webhook", webhook.Name, "flagName", payload.FlagName, "error", err)
+		if attempts == d.maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			err = fmt.Errorf("dispatcher stopped before delivery: %w", err)
+			attempts = d.maxAttempts
+		}
+		backoff = min(backoff*2, d.maxBackoff)
+	}
+
+	// The dispatcher may be shutting down, so the dead letter is written
+	// with a context of its own.
+	deadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	deadLetter := client.DeadLetter{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		FlagName:  payload.FlagName,
+		Payload:   string(body),
+		Attempts:  attempts,
+		LastError: err.Error(),
+	}
+	if err := d.deadLetter(deadCtx, deadLetter); err != nil {
+		d.logger.Error("error recording dead letter", "webhook", webhook.Name, "flagName", payload.FlagName, "error", err)
+	}
+}
+
+func (d *deliverer) send(ctx context.Context, webhook client.Webhook, payload Payload, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, payload.ID)
+	req.Header.Set(HeaderEvent, string(payload.Event))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestDetectChanges(t *testing.T) {
+	previous := map[string]flag.Definition{
+		"kept":    {FlagName: "kept", DefaultValue: true, Revision: 1},
+		"changed": {FlagName: "changed", DefaultValue: "a", Category: "ui", Revision: 2},
+		"bumped":  {FlagName: "bumped", DefaultValue: 1, Revision: 3},
+		"removed": {FlagName: "removed", Category: "billing", Revision: 4},
+	}
+	current := map[string]flag.Definition{
+		"kept":    {FlagName: "kept", DefaultValue: true, Revision: 1},
+		"changed": {FlagName: "changed", DefaultValue: "b", Category: "ui", Revision: 3},
+		"bumped":  {FlagName: "bumped", DefaultValue: 1, Revision: 4},
+		"added":   {FlagName: "added", DefaultValue: false, Revision: 1},
+	}
+
+	payloads := detectChanges(previous, current, time.Now())
+	want := []struct {
+		name  string
+		event Event
+	}{
+		{"added", EventFlagCreated},
+		{"changed", EventFlagUpdated},
+		{"removed", EventFlagDeleted},
+	}
+	if len(payloads) != len(want) {
+		t.Fatalf("payloads = %+v", payloads)
+	}
+	for i, w := range want {
+		if payloads[i].FlagName != w.name || payloads[i].Event != w.event {
+			t.Fatalf("payload %d = %s %s, want %s %s", i, payloads[i].FlagName, payloads[i].Event, w.name, w.event)
+		}
+	}
+
+	changed := payloads[1]
+	if changed.Revision != 3 || changed.Category != "ui" {
+		t.Fatalf("changed = %+v", changed)
+	}
+	if len(changed.Changes) != 1 || changed.Changes[0].Path != "DefaultValue" {
+		t.Fatalf("changes = %+v", changed.Changes)
+	}
+	if payloads[2].Category != "billing" {
+		t.Fatal("deleted flags should keep their category so category webhooks see them")
+	}
+}
+
+func TestCovers(t *testing.T) {
+	all := client.Webhook{}
+	byFlag := client.Webhook{FlagNames: []string{"checkout"}}
+	byCategory := client.Webhook{Categories: []string{"billing"}}
+	disabled := client.Webhook{Disabled: true}
+
+	if !all.Covers("anything", "") {
+		t.Fatal("a webhook without filters should cover every flag")
+	}
+	if !byFlag.Covers("checkout", "") || byFlag.Covers("other", "billing") {
+		t.Fatal("flag name filter")
+	}
+	if !byCategory.Covers("other", "billing") || byCategory.Covers("other", "") {
+		t.Fatal("category filter")
+	}
+	if disabled.Covers("checkout", "") {
+		t.Fatal("disabled webhooks should not cover anything")
+	}
+}
+
+func newDeliverer(deadLetters chan<- client.DeadLetter) deliverer {
+	return deliverer{
+		httpClient:     &http.Client{Timeout: time.Second},
+		maxAttempts:    3,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     5 * time.Millisecond,
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		deadLetter: func(_ context.Context, deadLetter client.DeadLetter) error {
+			deadLetters <- deadLetter
+			return nil
+		},
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	const secret = "shh"
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if r.Header.Get(HeaderSignature) != Sign(secret, timestamp, body) {
+			t.Error("signature does not match")
+		}
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil || payload.FlagName != "checkout" {
+			t.Errorf("payload = %s", body)
+		}
+		// Fail the first attempt so the retry is exercised.
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	deadLetters := make(chan client.DeadLetter, 1)
+	d := newDeliverer(deadLetters)
+	d.deliver(context.Background(), client.Webhook{Name: "test", URL: server.URL, Secret: secret}, Payload{ID: "1", Event: EventFlagUpdated, FlagName: "checkout"})
+
+	if calls.Load() != 2 {
+		t.Fatalf("calls = %d, want 2", calls.Load())
+	}
+	if len(deadLetters) != 0 {
+		t.Fatal("a delivered payload should not be dead-lettered")
+	}
+}
+
+func TestDeliverDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	deadLetters := make(chan client.DeadLetter, 1)
+	d := newDeliverer(deadLetters)
+	d.deliver(context.Background(), client.Webhook{Name: "test", URL: server.URL, Secret: "s"}, Payload{ID: "1", FlagName: "checkout"})
+
+	select {
+	case deadLetter := <-deadLetters:
+		if deadLetter.Attempts != 3 || deadLetter.FlagName != "checkout" || deadLetter.LastError == "" {
+			t.Fatalf("dead letter = %+v", deadLetter)
+		}
+	default:
+		t.Fatal("expected a dead letter")
+	}
+}
@@ -6,62 +6,135 @@ import (
 
 	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func New() *Cache {
+	return NewForEnvironment("")
+}
+
+// NewForEnvironment creates a cache that stores every flag as it is evaluated
+// in the named environment. The empty name uses each flag's base definition.
+func NewForEnvironment(environment string) *Cache {
 	return &Cache{
-		cacheMutex: sync.RWMutex{},
-		cache:      make(map[string]flag.Definition),
+		cacheMutex:  sync.RWMutex{},
+		cache:       make(map[string]flag.Definition),
+		environment: environment,
+		metrics:     metrics.Nop{},
 	}
 }
 
 type Cache struct {
-	cacheMutex sync.RWMutex `bson:"-"`
-	cache      map[string]flag.Definition
+	cacheMutex  sync.RWMutex `bson:"-"`
+	cache       map[string]flag.Definition
+	environment string
+	metrics     metrics.Metrics
+}
+
+// WithMetrics makes the cache report its size to m whenever it changes.
+func (c *Cache) WithMetrics(m metrics.Metrics) *Cache {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	c.metrics = m
+	c.metrics.CacheSize(len(c.cache))
+	return c
+}
+
+// Environment returns the environment the cache resolves flags for.
+func (c *Cache) Environment() string {
+	return c.environment
+}
+
+// Get returns the cached definition of flagKey, already resolved for the
+// cache's environment.
+func (c *Cache) Get(flagKey string) (flag.Definition, bool) {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+	definition, ok := c.cache[flagKey]
+	return definition, ok
+}
+
+// All returns a copy of every cached definition, keyed by flag name.
+func (c *Cache) All() map[string]flag.Definition {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+	definitions := make(map[string]flag.Definition, len(c.cache))
+	for flagKey, definition := range c.cache {
+		definitions[flagKey] = definition
+	}
+	return definitions
 }
 
 func (c *Cache) Clear() {
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
 	c.cache = make(map[string]flag.Definition)
+	c.metrics.CacheSize(0)
 }
 
 func (c *Cache) Set(flagKey string, definition any) error {
+	parsedDefinition, err := parseDefinition(definition)
+	if err != nil {
+		return err
+	}
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	c.cache[flagKey] = parsedDefinition.ForEnvironment(c.environment)
+	c.metrics.CacheSize(len(c.cache))
+
+	return nil
+}
+
+// parseDefinition converts a flag.Definition, or any value with the same BSON
+// shape such as a raw change stream document, into a flag.Definition.
+func parseDefinition(definition any) (flag.Definition, error) {
 	parsedDefinition, ok := definition.(flag.Definition)
 	if ok {
-		c.cacheMutex.Lock()
-		defer c.cacheMutex.Unlock()
-		c.cache[flagKey] = parsedDefinition
-		return nil
+		return parsedDefinition, nil
 	}
 	// If the definition is not of type FlagDefinition, we attempt to parse it.
 	bsonDefinition, err := bson.Marshal(definition)
 	if err != nil {
-		return fmt.Errorf("marshalling definition to bson: %w", err)
+		return parsedDefinition, fmt.Errorf("marshalling definition to bson: %w", err)
 	}
 	if err := bson.Unmarshal(bsonDefinition, &parsedDefinition); err != nil {
-		return fmt.Errorf("unmarshalling bson to flag definition: %w", err)
+		return parsedDefinition, fmt.Errorf("unmarshalling bson to flag definition: %w", err)
+	}
+	return parsedDefinition, nil
+}
+
+// Apply stores sets and removes deletes as one change, so evaluations never
+// see only part of it.
+func (c *Cache) Apply(sets map[string]any, deletes []string) error {
+	parsed := make(map[string]flag.Definition, len(sets))
+	for flagKey, definition := range sets {
+		parsedDefinition, err := parseDefinition(definition)
+		if err != nil {
+			return fmt.Errorf("parsing flag %s: %w", flagKey, err)
+		}
+		parsed[flagKey] = parsedDefinition.ForEnvironment(c.environment)
 	}
 
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
-	c.cache[flagKey] = parsedDefinition
-
+	for _, flagKey := range deletes {
+		delete(c.cache, flagKey)
+	}
+	for flagKey, definition := range parsed {
+		c.cache[flagKey] = definition
+	}
+	c.metrics.CacheSize(len(c.cache))
 	return nil
 }
 
 func (c *Cache) SetAll(definitions map[string]flag.Definition) error {
-	c.cacheMutex.Lock()
-	defer c.cacheMutex.Unlock()
-
+	sets := make(map[string]any, len(definitions))
 	for flagKey, definition := range definitions {
-		if err := c.Set(flagKey, definition); err != nil {
-			return fmt.Errorf("setting flag %s: %w", flagKey, err)
-		}
+		sets[flagKey] = definition
 	}
-
-	return nil
+	return c.Apply(sets, nil)
 }
 
 func Evaluate[T any](cache *Cache, flatCtx openfeature.FlattenedContext, flag string, defaultValue T) (T, openfeature.ProviderResolutionDetail) {
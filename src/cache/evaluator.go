@@ -2,10 +2,17 @@ package cache
 
 import (
 	"context"
+	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
 )
 
+// EvaluationObserver is told about every evaluation a CacheEvaluator makes,
+// for example to record how long evaluations take.
+type EvaluationObserver interface {
+	ObserveEvaluation(ctx context.Context, flagKey string, detail openfeature.ProviderResolutionDetail, duration time.Duration)
+}
+
 func NewEvaluator(cache *Cache) *CacheEvaluator {
 	return &CacheEvaluator{
 		cache: cache,
@@ -13,35 +20,60 @@ func NewEvaluator(cache *Cache) *CacheEvaluator {
 }
 
 type CacheEvaluator struct {
-	cache *Cache
+	cache    *Cache
+	observer EvaluationObserver
+}
+
+// WithObserver makes the evaluator report every evaluation to observer.
+func (c *CacheEvaluator) WithObserver(observer EvaluationObserver) *CacheEvaluator {
+	c.observer = observer
+	return c
+}
+
+// observe reports an evaluation that started at start to the observer, if
+// there is one.
+func (c *CacheEvaluator) observe(ctx context.Context, flag string, detail openfeature.ProviderResolutionDetail, start time.Time) {
+	if c.observer != nil {
+		c.observer.ObserveEvaluation(ctx, flag, detail, time.Since(start))
+	}
 }
 
 // BooleanEvaluation implements openfeature.FeatureProvider.
 func (c *CacheEvaluator) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	start := time.Now()
 	val, detail := Evaluate(c.cache, flatCtx, flag, defaultValue)
+	c.observe(ctx, flag, detail, start)
 	return openfeature.BoolResolutionDetail{Value: val, ProviderResolutionDetail: detail}
 }
 
 // FloatEvaluation implements openfeature.FeatureProvider.
 func (c *CacheEvaluator) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	start := time.Now()
 	val, detail := Evaluate(c.cache, flatCtx, flag, defaultValue)
+	c.observe(ctx, flag, detail, start)
 	return openfeature.FloatResolutionDetail{Value: val, ProviderResolutionDetail: detail}
 }
 
 // IntEvaluation implements openfeature.FeatureProvider.
 func (c *CacheEvaluator) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	start := time.Now()
 	val, detail := Evaluate(c.cache, flatCtx, flag, defaultValue)
+	c.observe(ctx, flag, detail, start)
 	return openfeature.IntResolutionDetail{Value: val, ProviderResolutionDetail: detail}
 }
 
 // ObjectEvaluation implements openfeature.FeatureProvider.
 func (c *CacheEvaluator) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	start := time.Now()
 	val, detail := Evaluate(c.cache, flatCtx, flag, defaultValue)
+	c.observe(ctx, flag, detail, start)
 	return openfeature.InterfaceResolutionDetail{Value: val, ProviderResolutionDetail: detail}
 }
 
 // StringEvaluation implements openfeature.FeatureProvider.
 func (c *CacheEvaluator) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	start := time.Now()
 	val, detail := Evaluate(c.cache, flatCtx, flag, defaultValue)
+	c.observe(ctx, flag, detail, start)
 	return openfeature.StringResolutionDetail{Value: val, ProviderResolutionDetail: detail}
 }
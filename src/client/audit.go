@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ActorSource is the path a mutation came through.
+type ActorSource string
+
+const (
+	ActorSourceClient     ActorSource = "client"
+	ActorSourceEditor     ActorSource = "editor"
+	ActorSourceMCP        ActorSource = "mcp"
+	ActorSourceAssistant  ActorSource = "assistant"
+	ActorSourceScheduler  ActorSource = "scheduler"
+	ActorSourceCLI        ActorSource = "cli"
+	ActorSourceReconciler ActorSource = "reconciler"
+)
+
+const (
+	// OperationSchedule and OperationCancelSchedule are audited when a
+	// scheduled change is queued or cancelled. They never produce a version.
+	OperationSchedule       Operation = "schedule"
+	OperationCancelSchedule Operation = "cancel_schedule"
+)
+
+// Actor identifies who made a flag mutation and from where.
+type Actor struct {
+	// Name is the user or service, e.g. an email address.
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	// Source is the path the mutation came through.
+	Source ActorSource `bson:"source" json:"source"`
+	// Address is the network address of the caller, when known.
+	Address string `bson:"address,omitempty" json:"address,omitempty"`
+}
+
+func (a Actor) String() string {
+	switch {
+	case a.Name == "":
+		return string(a.Source)
+	case a.Source == "":
+		return a.Name
+	default:
+		return fmt.Sprintf("%s (%s)", a.Name, a.Source)
+	}
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes flag mutations made with it to
+// actor in the audit log and version history.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor. Mutations made without
+// one are attributed to an anonymous ActorSourceClient actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok {
+		return Actor{Source: ActorSourceClient}
+	}
+	if actor.Source == "" {
+		actor.Source = ActorSourceClient
+	}
+	return actor
+}
+
+// AuditEntry records a single flag mutation.
+type AuditEntry struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Timestamp time.Time     `bson:"timestamp" json:"timestamp"`
+	Actor     Actor         `bson:"actor" json:"actor"`
+	Operation Operation     `bson:"operation" json:"operation"`
+	FlagName  string        `bson:"flagName" json:"flagName"`
+	// Revision is the flag's revision after the mutation, or 0 if it was
+	// deleted or the mutation did not write the flag.
+	Revision int64 `bson:"revision,omitempty" json:"revision,omitempty"`
+	// Diff lists the changes the mutation made to the flag.
+	Diff []flag.Change `bson:"diff,omitempty" json:"diff,omitempty"`
+	// Detail adds context that is not captured by the diff, such as the
+	// scheduled change that was applied.
+	Detail string `bson:"detail,omitempty" json:"detail,omitempty"`
+}
+
+// AuditQuery filters ListAuditEntries. Zero fields are not filtered on.
+type AuditQuery struct {
+	FlagName string
+	// Actor matches the actor's name exactly.
+	Actor  string
+	Source ActorSource
+	// Since and Until bound the entry timestamps, inclusive and exclusive
+	// respectively.
+	Since time.Time
+	Until time.Time
+	// Limit caps the number of entries returned. It defaults to 100.
+	Limit int
+}
+
+func (q AuditQuery) filter() bson.M {
+	filter := bson.M{}
+	if q.FlagName != "" {
+		filter["flagName"] = q.FlagName
+	}
+	if q.Actor != "" {
+		filter["actor.name"] = q.Actor
+	}
+	if q.Source != "" {
+		filter["actor.source"] = q.Source
+	}
+	timestamp := bson.M{}
+	if !q.Since.IsZero() {
+		timestamp["$gte"] = q.Since.UTC()
+	}
+	if !q.Until.IsZero() {
+		timestamp["$lt"] = q.Until.UTC()
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+	return filter
+}
+
+// ListAuditEntries returns the audit entries matching query, newest first.
+func (c *Client) ListAuditEntries(ctx context.Context, query AuditQuery) ([]AuditEntry, error) {
+	var err error
+	var result []AuditEntry
+	for i := 0; i < c.maxTries; i++ {
+		result, err = c.listAuditEntries(ctx, query)
+		if err == nil {
+			return result, nil
+		}
+		c.logger.Error("error listing audit entries, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("list_audit_entries")
+	}
+	return nil, fmt.Errorf("listing audit entries after %d attempts: %w", c.maxTries, err)
+}
+
+func (c *Client) listAuditEntries(ctx context.Context, query AuditQuery) ([]AuditEntry, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := c.auditCollection.Find(ctx, query.filter(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("finding audit entries: %w", err)
+	}
+	entries := []AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("decoding audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// recordMutation records a completed write to a flag in both the version
+// history and the audit log, with after being the flag as the write stored
+// it. Inside a transaction a failure is returned, so the write is rolled back
+// rather than left unrecorded. Without one the write has already landed, so
+// failures are logged instead.
+func (c *Client) recordMutation(ctx context.Context, operation Operation, flagName string, before, after *flag.Definition) error {
+	if err := c.recordVersion(ctx, operation, flagName, before, after); err != nil {
+		if transactional(ctx) {
+			return err
+		}
+		c.logger.Error("error recording flag version", slog.String("flagName", flagName), slog.String("operation", string(operation)), slog.Any("error", err))
+	}
+
+	entry := AuditEntry{
+		Operation: operation,
+		FlagName:  flagName,
+		Diff:      flag.Diff(before, after),
+	}
+	if after != nil {
+		entry.Revision = after.Revision
+	}
+	if err := c.insertAudit(ctx, entry); err != nil {
+		if transactional(ctx) {
+			return err
+		}
+		c.logger.Error("error recording audit entry", slog.String("flagName", entry.FlagName), slog.String("operation", string(entry.Operation)), slog.Any("error", err))
+	}
+	return nil
+}
+
+// recordAudit stamps entry with the actor from ctx and stores it. It runs
+// after the action it records succeeded, so failures are logged rather than
+// returned.
+func (c *Client) recordAudit(ctx context.Context, entry AuditEntry) {
+	if err := c.insertAudit(ctx, entry); err != nil {
+		c.logger.Error("error recording audit entry", slog.String("flagName", entry.FlagName), slog.String("operation", string(entry.Operation)), slog.Any("error", err))
+	}
+}
+
+// insertAudit stamps entry with the actor from ctx and stores it.
+func (c *Client) insertAudit(ctx context.Context, entry AuditEntry) error {
+	err := c.auditIndexes.ensure(c.auditCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "flagName", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "actor.name", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	if err != nil {
+		// The indexes only speed up queries, so the entry is stored
+		// regardless.
+		c.logger.Error("error creating audit indexes", slog.Any("error", err))
+	}
+
+	entry.ID = bson.NewObjectID()
+	entry.Timestamp = time.Now().UTC()
+	entry.Actor = ActorFromContext(ctx)
+
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.auditCollection.InsertOne(ctx, entry)
+		if err == nil || mongo.IsDuplicateKeyError(err) {
+			// A duplicate key means a previous attempt landed.
+			return nil
+		}
+		if transactional(ctx) {
+			break
+		}
+	}
+	return err
+}
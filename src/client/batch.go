@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// BatchOperation is a single write in a batch. Exactly one of Set and Delete
+// must be provided.
+type BatchOperation struct {
+	// Set creates or replaces a flag, like SetFlag. A non-zero Revision
+	// makes the whole batch conditional on that flag's revision.
+	Set *flag.Definition
+	// Delete is the name of a flag to remove. Deleting a flag that does
+	// not exist is not an error.
+	Delete string
+	// ExpectedRevision, when not nil, makes the whole batch conditional on
+	// the flag being stored at that revision, for a Set or a Delete. Zero
+	// matches a flag written before revisions were tracked. It takes
+	// precedence over Set.Revision.
+	ExpectedRevision *int64
+}
+
+// SetOperation returns a batch operation that writes def.
+func SetOperation(def flag.Definition) BatchOperation {
+	return BatchOperation{Set: &def}
+}
+
+// DeleteOperation returns a batch operation that removes the named flag.
+func DeleteOperation(flagName string) BatchOperation {
+	return BatchOperation{Delete: flagName}
+}
+
+// AtRevision returns op made conditional on the flag being stored at
+// revision; see ExpectedRevision.
+func (op BatchOperation) AtRevision(revision int64) BatchOperation {
+	op.ExpectedRevision = &revision
+	return op
+}
+
+// expectedRevision returns the revision op is conditional on, if any.
+func (op BatchOperation) expectedRevision() (int64, bool) {
+	if op.ExpectedRevision != nil {
+		return *op.ExpectedRevision, true
+	}
+	if op.Set != nil && op.Set.Revision != 0 {
+		return op.Set.Revision, true
+	}
+	return 0, false
+}
+
+func (op BatchOperation) flagName() string {
+	if op.Set != nil {
+		return op.Set.FlagName
+	}
+	return op.Delete
+}
+
+func validateBatch(ops []BatchOperation) error {
+	seen := make(map[string]bool, len(ops))
+	for i, op := range ops {
+		if (op.Set == nil) == (op.Delete == "") {
+			return fmt.Errorf("operation %d must set or delete exactly one flag", i)
+		}
+		name := op.flagName()
+		if name == "" {
+			return fmt.Errorf("operation %d is missing a flag name", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("flag '%s' appears more than once in the batch", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ApplyBatch applies every operation or none of them. In single-document mode
+// the batch is one atomic update of the flag document. In multi-document mode
+// it runs in a transaction; standalone servers do not support transactions,
+// so there the operations are applied one at a time after checking any
+// expected revisions, and a failure can leave the batch partly applied.
+//
+// If any operation carries a revision that no longer matches, nothing is
+// written and a *RevisionConflictError is returned. Likewise, if any flag set
+// does not match the context schema, nothing is written and a
+// *ContextSchemaError is returned.
+func (c *Client) ApplyBatch(ctx context.Context, ops []BatchOperation) error {
+	if err := validateBatch(ops); err != nil {
+		return fmt.Errorf("validating batch: %w", err)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	var sets []flag.Definition
+	for _, op := range ops {
+		if op.Set != nil {
+			sets = append(sets, *op.Set)
+		}
+	}
+	if err := c.checkContextSchema(ctx, sets...); err != nil {
+		return err
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		err = c.inTransaction(ctx, func(ctx context.Context) error {
+			return c.applyBatch(ctx, ops)
+		})
+		if err == nil {
+			return nil
+		}
+		if isRevisionConflict(err) {
+			return err
+		}
+		c.logger.Error("error applying batch, retrying", slog.Int("attempt", i+1), slog.Int("operations", len(ops)), slog.Any("error", err))
+		c.metrics.Retry("apply_batch")
+	}
+	return fmt.Errorf("applying batch of %d operations after %d attempts: %w", len(ops), c.maxTries, err)
+}
+
+// applyBatch writes the batch and records every operation in it.
+func (c *Client) applyBatch(ctx context.Context, ops []BatchOperation) error {
+	before := make(map[string]*flag.Definition, len(ops))
+	for _, op := range ops {
+		before[op.flagName()] = c.currentFlag(ctx, op.flagName())
+	}
+
+	var after map[string]*flag.Definition
+	var err error
+	if c.documentID != "" {
+		after, err = c.applyBatchSingleDocument(ctx, ops)
+	} else {
+		after, err = c.applyBatchMultiDocument(ctx, ops)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		name := op.flagName()
+		operation := OperationSet
+		if op.Delete != "" {
+			operation = OperationDelete
+		}
+		if err := c.recordMutation(ctx, operation, name, before[name], after[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyBatchMultiDocument applies the operations one at a time and returns the
+// flags they set. Outside a transaction, expected revisions are checked up
+// front so a stale batch is rejected before anything is written.
+func (c *Client) applyBatchMultiDocument(ctx context.Context, ops []BatchOperation) (map[string]*flag.Definition, error) {
+	if !transactional(ctx) {
+		if err := c.checkBatchRevisions(ctx, ops); err != nil {
+			return nil, err
+		}
+	}
+
+	after := make(map[string]*flag.Definition, len(ops))
+	for _, op := range ops {
+		def, err := c.applyBatchOperation(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		after[op.flagName()] = def
+	}
+	return after, nil
+}
+
+// checkBatchRevisions returns a *RevisionConflictError for the first
+// operation whose flag is not at the expected revision.
+func (c *Client) checkBatchRevisions(ctx context.Context, ops []BatchOperation) error {
+	for _, op := range ops {
+		expected, ok := op.expectedRevision()
+		if !ok {
+			continue
+		}
+		current := c.currentFlag(ctx, op.flagName())
+		if current == nil || current.Revision != expected {
+			return c.revisionConflict(ctx, op.flagName(), expected)
+		}
+	}
+	return nil
+}
+
+func (c *Client) applyBatchOperation(ctx context.Context, op BatchOperation) (*flag.Definition, error) {
+	expected, conditional := op.expectedRevision()
+	if op.Set != nil {
+		write := c.setFlag
+		def := *op.Set
+		if conditional {
+			def.Revision = expected
+			write = c.setFlagAtRevision
+		}
+		stored, err := write(ctx, def)
+		if err != nil {
+			return nil, fmt.Errorf("setting flag %s: %w", op.Set.FlagName, err)
+		}
+		return stored, nil
+	}
+	if conditional {
+		res, err := c.collection.DeleteOne(ctx, bson.M{"_id": op.Delete, "revision": revisionFilter(expected)})
+		if err != nil {
+			return nil, fmt.Errorf("deleting flag %s: %w", op.Delete, err)
+		}
+		if res.DeletedCount == 0 {
+			return nil, c.revisionConflict(ctx, op.Delete, expected)
+		}
+		return nil, nil
+	}
+	// Unlike DeleteFlag, a missing flag is not an error, so a retried batch
+	// does not fail on the deletes that already landed.
+	if _, err := c.collection.DeleteOne(ctx, bson.M{"_id": op.Delete}); err != nil {
+		return nil, fmt.Errorf("deleting flag %s: %w", op.Delete, err)
+	}
+	return nil, nil
+}
+
+// applyBatchSingleDocument writes the whole batch as one update pipeline on
+// the flag document, bumping the revision of every flag it sets, and returns
+// the flags it set.
+func (c *Client) applyBatchSingleDocument(ctx context.Context, ops []BatchOperation) (map[string]*flag.Definition, error) {
+	filter := bson.M{"_id": c.documentID}
+	conditional := false
+	set := bson.D{}
+	var unset bson.A
+	for _, op := range ops {
+		if expected, ok := op.expectedRevision(); ok {
+			filter[op.flagName()+".revision"] = revisionFilter(expected)
+			if expected == 0 {
+				// A missing revision also matches a missing flag.
+				filter[op.flagName()] = bson.M{"$exists": true}
+			}
+			conditional = true
+		}
+		if op.Delete != "" {
+			unset = append(unset, op.Delete)
+			continue
+		}
+		doc, err := marshalDefinition(*op.Set)
+		if err != nil {
+			return nil, err
+		}
+		nextRevision := bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + op.Set.FlagName + ".revision", 0}}, 1}}
+		set = append(set, bson.E{Key: op.Set.FlagName, Value: bson.M{"$mergeObjects": bson.A{
+			bson.M{"$literal": doc},
+			bson.M{"revision": nextRevision},
+		}}})
+	}
+
+	pipeline := mongo.Pipeline{}
+	if len(set) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$set", Value: set}})
+	}
+	if len(unset) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$unset", Value: unset}})
+	}
+
+	// A conditional batch can only apply to an existing document, so it is
+	// not an upsert.
+	opts := options.FindOneAndUpdate().SetUpsert(!conditional).SetReturnDocument(options.After)
+	var result singleDocument
+	err := c.collection.FindOneAndUpdate(ctx, filter, pipeline, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if err := c.checkBatchRevisions(ctx, ops); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("document '%s' not found", c.documentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating doc %s: %w", c.documentID, err)
+	}
+
+	after := make(map[string]*flag.Definition, len(ops))
+	for _, op := range ops {
+		if op.Set == nil {
+			continue
+		}
+		def := result.Flags[op.Set.FlagName]
+		after[op.Set.FlagName] = &def
+	}
+	return after, nil
+}
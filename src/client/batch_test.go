@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestValidateBatch(t *testing.T) {
+	def := flag.Definition{FlagName: "checkout"}
+	for name, tc := range map[string]struct {
+		ops   []BatchOperation
+		valid bool
+	}{
+		"Empty":         {valid: true},
+		"SetAndDelete":  {ops: []BatchOperation{SetOperation(def), DeleteOperation("search")}, valid: true},
+		"Neither":       {ops: []BatchOperation{{}}},
+		"Both":          {ops: []BatchOperation{{Set: &def, Delete: "checkout"}}},
+		"MissingName":   {ops: []BatchOperation{SetOperation(flag.Definition{})}},
+		"DuplicateFlag": {ops: []BatchOperation{SetOperation(def), DeleteOperation("checkout")}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateBatch(tc.ops)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	for name, newClient := range map[string]func(*testing.T) *Client{
+		"Transaction": newTestClient,
+		"Standalone": func(t *testing.T) *Client {
+			// Writes as on a standalone server, without transactions.
+			c := newTestClient(t)
+			c.transactionsUnsupported.Store(true)
+			return c
+		},
+		"SingleDocument": newSingleDocumentTestClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newClient(t)
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "off"}))
+
+			require.NoError(t, c.ApplyBatch(ctx, []BatchOperation{
+				SetOperation(flag.Definition{FlagName: "checkout", DefaultVariant: "on", Revision: 1}),
+				SetOperation(flag.Definition{FlagName: "banner", DefaultVariant: "on"}),
+				DeleteOperation("search"),
+			}))
+			flags, err := c.GetAllFlags(ctx)
+			require.NoError(t, err)
+			require.Len(t, flags, 2)
+			assert.Equal(t, "on", flags["checkout"].DefaultVariant)
+			assert.Equal(t, int64(2), flags["checkout"].Revision)
+			assert.Equal(t, int64(1), flags["banner"].Revision)
+
+			versions, err := c.ListVersions(ctx, "search")
+			require.NoError(t, err)
+			require.Len(t, versions, 2, "every operation is recorded")
+			assert.Equal(t, OperationDelete, versions[0].Operation)
+
+			// A stale revision rejects the whole batch.
+			err = c.ApplyBatch(ctx, []BatchOperation{
+				SetOperation(flag.Definition{FlagName: "banner", DefaultVariant: "off"}),
+				SetOperation(flag.Definition{FlagName: "checkout", DefaultVariant: "off", Revision: 1}),
+			})
+			assert.ErrorIs(t, err, mongoopenfeature.ErrRevisionConflict)
+			banner, err := c.GetFlag(ctx, "banner")
+			require.NoError(t, err)
+			assert.Equal(t, "on", banner.DefaultVariant, "nothing in a rejected batch is written")
+		})
+	}
+}
@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -17,55 +23,229 @@ func New(opts *Options) (*Client, error) {
 		return nil, fmt.Errorf("validating client options: %w", err)
 	}
 
+	database := opts.Client.Database(opts.Database)
 	client := &Client{
-		collection: opts.Client.Database(opts.Database).Collection(opts.Collection),
-		maxTries:   opts.MaxTries,
-		documentID: opts.DocumentID,
-		logger:     opts.Logger,
+		collection:                   database.Collection(opts.Collection),
+		scheduleCollection:           database.Collection(opts.ScheduleCollection),
+		historyCollection:            database.Collection(opts.HistoryCollection),
+		auditCollection:              database.Collection(opts.AuditCollection),
+		webhookCollection:            database.Collection(opts.WebhookCollection),
+		deadLetterCollection:         database.Collection(opts.DeadLetterCollection),
+		impressionCollection:         database.Collection(opts.ImpressionCollection),
+		trackingCollection:           database.Collection(opts.TrackingCollection),
+		usageCollection:              database.Collection(opts.UsageCollection),
+		codeReferenceCollection:      database.Collection(opts.CodeReferenceCollection),
+		contextSchemaCollection:      database.Collection(opts.ContextSchemaCollection),
+		contextObservationCollection: database.Collection(opts.ContextObservationCollection),
+		maxTries:                     opts.MaxTries,
+		documentID:                   opts.DocumentID,
+		logger:                       opts.Logger,
+		metrics:                      opts.Metrics,
 	}
 
 	return client, nil
 }
 
 type Client struct {
-	collection *mongo.Collection
-	maxTries   int
-	documentID string
-
-	logger *slog.Logger
+	collection                   *mongo.Collection
+	scheduleCollection           *mongo.Collection
+	historyCollection            *mongo.Collection
+	auditCollection              *mongo.Collection
+	webhookCollection            *mongo.Collection
+	deadLetterCollection         *mongo.Collection
+	impressionCollection         *mongo.Collection
+	trackingCollection           *mongo.Collection
+	usageCollection              *mongo.Collection
+	codeReferenceCollection      *mongo.Collection
+	contextSchemaCollection      *mongo.Collection
+	contextObservationCollection *mongo.Collection
+	maxTries                     int
+	documentID                   string
+
+	historyIndexes         lazyIndexes
+	auditIndexes           lazyIndexes
+	impressionIndexOnce    sync.Once
+	trackingIndexOnce      sync.Once
+	codeReferenceIndexOnce sync.Once
+
+	// transactionsUnsupported is set once a write finds the deployment is
+	// a standalone server.
+	transactionsUnsupported atomic.Bool
+
+	logger  *slog.Logger
+	metrics metrics.Metrics
 }
 
+// SetFlag creates or replaces a flag definition. When the definition has a
+// non-zero Revision, the write only succeeds if the stored flag is still at
+// that revision; otherwise a *RevisionConflictError is returned. A zero
+// Revision writes unconditionally.
+//
+// When the context schema declares any keys, the flag's rules must only read
+// declared keys, as their declared types; otherwise a *ContextSchemaError is
+// returned and nothing is written. Every other write of flag definitions is
+// checked the same way.
 func (c *Client) SetFlag(ctx context.Context, flagDefinition flag.Definition) error {
+	return c.setFlagAs(ctx, flagDefinition, OperationSet)
+}
+
+func (c *Client) setFlagAs(ctx context.Context, flagDefinition flag.Definition, operation Operation) error {
+	return c.writeFlagAs(ctx, flagDefinition, operation, c.setFlag)
+}
+
+// writeFlagAs checks a flag against the context schema, writes it with write,
+// retrying failures other than revision conflicts, and records the mutation
+// as operation. The write and its record share a transaction where the
+// deployment supports them.
+func (c *Client) writeFlagAs(ctx context.Context, flagDefinition flag.Definition, operation Operation, write func(context.Context, flag.Definition) (*flag.Definition, error)) error {
+	if err := c.checkContextSchema(ctx, flagDefinition); err != nil {
+		return err
+	}
+
 	var err error
 	for i := 0; i < c.maxTries; i++ {
-		err = c.setFlag(ctx, flagDefinition)
+		err = c.inTransaction(ctx, func(ctx context.Context) error {
+			before := c.currentFlag(ctx, flagDefinition.FlagName)
+			after, err := write(ctx, flagDefinition)
+			if err != nil {
+				return err
+			}
+			return c.recordMutation(ctx, operation, flagDefinition.FlagName, before, after)
+		})
 		if err == nil {
 			return nil
 		}
+		if isRevisionConflict(err) {
+			return err
+		}
 		c.logger.Error("error setting flag, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagDefinition.FlagName), slog.Any("error", err))
+		c.metrics.Retry("set_flag")
 	}
 
 	return fmt.Errorf("setting flag %s after %d attempts: %w", flagDefinition.FlagName, c.maxTries, err)
 }
 
-func (c *Client) setFlag(ctx context.Context, flagDefinition flag.Definition) error {
+// setFlag writes a flag and returns it as stored.
+func (c *Client) setFlag(ctx context.Context, flagDefinition flag.Definition) (*flag.Definition, error) {
+	if flagDefinition.Revision != 0 {
+		return c.setFlagAtRevision(ctx, flagDefinition)
+	}
+
+	// Unconditional writes bump whatever revision is stored, so they use an
+	// update pipeline to read and increment it atomically.
 	documentID := flagDefinition.FlagName
-	var update any = flagDefinition
+	revisionPath := "$revision"
 	if c.documentID != "" {
 		documentID = c.documentID
+		revisionPath = fmt.Sprintf("$%s.revision", flagDefinition.FlagName)
+	}
+	nextRevision := bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{revisionPath, 0}}, 1}}
+
+	doc, err := marshalDefinition(flagDefinition)
+	if err != nil {
+		return nil, err
+	}
+	var set bson.D
+	if c.documentID != "" {
+		set = bson.D{{Key: flagDefinition.FlagName, Value: bson.M{"$mergeObjects": bson.A{
+			bson.M{"$literal": doc},
+			bson.M{"revision": nextRevision},
+		}}}}
+	} else {
+		// The stored revision is replaced by the bumped one; a second
+		// revision field would conflict within the $set stage.
+		doc = slices.DeleteFunc(doc, func(e bson.E) bool { return e.Key == "revision" })
+		set = append(literalDocument(doc), bson.E{Key: "revision", Value: nextRevision})
+	}
+
+	res := c.collection.FindOneAndUpdate(ctx, bson.M{"_id": documentID}, mongo.Pipeline{
+		{{Key: "$set", Value: set}},
+	}, c.returnFlag(flagDefinition.FlagName).SetUpsert(true))
+	return c.decodeFlag(res, flagDefinition.FlagName)
+}
+
+// setFlagAtRevision writes a flag only if the stored flag is still at the
+// definition's Revision. A zero Revision matches an existing flag written
+// before revisions were tracked, which has no revision field. It returns the
+// flag as stored.
+func (c *Client) setFlagAtRevision(ctx context.Context, flagDefinition flag.Definition) (*flag.Definition, error) {
+	expected := flagDefinition.Revision
+	flagDefinition.Revision = expected + 1
+
+	revision := revisionFilter(expected)
+	filter := bson.M{"_id": flagDefinition.FlagName, "revision": revision}
+	var update any = flagDefinition
+	if c.documentID != "" {
+		filter = bson.M{"_id": c.documentID, flagDefinition.FlagName + ".revision": revision}
+		if expected == 0 {
+			// A missing revision also matches a missing flag, which must
+			// not be recreated.
+			filter[flagDefinition.FlagName] = bson.M{"$exists": true}
+		}
 		update = map[string]flag.Definition{
 			flagDefinition.FlagName: flagDefinition,
 		}
 	}
 
-	_, err := c.collection.UpdateByID(ctx, documentID, bson.M{
+	res := c.collection.FindOneAndUpdate(ctx, filter, bson.M{
 		"$set": update,
-	}, options.UpdateOne().SetUpsert(true))
-	if err != nil {
-		return err
+	}, c.returnFlag(flagDefinition.FlagName))
+	stored, err := c.decodeFlag(res, flagDefinition.FlagName)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, c.revisionConflict(ctx, flagDefinition.FlagName, expected)
 	}
+	return stored, err
+}
 
-	return nil
+// returnFlag returns the options of a FindOneAndUpdate whose result is read
+// with decodeFlag.
+func (c *Client) returnFlag(flagName string) *options.FindOneAndUpdateOptionsBuilder {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if c.documentID != "" {
+		opts.SetProjection(bson.M{flagName: 1})
+	}
+	return opts
+}
+
+// singleDocument is the flag document of the single-document mode.
+type singleDocument struct {
+	ID    any                        `bson:"_id"`
+	Flags map[string]flag.Definition `bson:",inline"`
+}
+
+// decodeFlag reads a flag from the document a write returned. It returns
+// mongo.ErrNoDocuments when the write matched nothing.
+func (c *Client) decodeFlag(res *mongo.SingleResult, flagName string) (*flag.Definition, error) {
+	if c.documentID == "" {
+		var def flag.Definition
+		if err := res.Decode(&def); err != nil {
+			return nil, err
+		}
+		return &def, nil
+	}
+
+	var doc singleDocument
+	if err := res.Decode(&doc); err != nil {
+		return nil, err
+	}
+	def, ok := doc.Flags[flagName]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &def, nil
+}
+
+// marshalDefinition converts a definition to the document stored in Mongo.
+func marshalDefinition(flagDefinition flag.Definition) (bson.D, error) {
+	raw, err := bson.Marshal(flagDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling flag %s: %w", flagDefinition.FlagName, err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshalling flag %s: %w", flagDefinition.FlagName, err)
+	}
+	return doc, nil
 }
 
 func (c *Client) GetFlag(ctx context.Context, flagName string) (*flag.Definition, error) {
@@ -77,6 +257,7 @@ func (c *Client) GetFlag(ctx context.Context, flagName string) (*flag.Definition
 			return result, nil
 		}
 		c.logger.Error("error getting flag, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("get_flag")
 	}
 
 	return nil, fmt.Errorf("getting flag %s after %d attempts: %w", flagName, c.maxTries, err)
@@ -103,10 +284,7 @@ func (c *Client) getFlagMultiDocument(ctx context.Context, flagName string) (*fl
 }
 
 func (c *Client) getFlagSingleDocument(ctx context.Context, flagName string) (*flag.Definition, error) {
-	var result struct {
-		ID    any                        `bson:"_id"`
-		Flags map[string]flag.Definition `bson:",inline"`
-	}
+	var result singleDocument
 	opts := options.FindOne().SetProjection(bson.M{flagName: 1})
 	err := c.collection.FindOne(ctx, bson.M{"_id": c.documentID}, opts).Decode(&result)
 	if err != nil {
@@ -125,59 +303,153 @@ func (c *Client) getFlagSingleDocument(ctx context.Context, flagName string) (*f
 
 // PartialUpdateFlag performs an atomic partial update on a flag definition.
 // The updates map should contain keys matching the BSON field names to be changed.
+// If updates contains ExpectedRevisionKey, the update only applies when the
+// stored flag is at that revision; otherwise a *RevisionConflictError is returned.
+// The updated flag is checked against the context schema like SetFlag.
 func (c *Client) PartialUpdateFlag(ctx context.Context, flagName string, updates map[string]any) error {
+	fields := make(map[string]any, len(updates))
+	var expectedRevision *int64
+	for k, v := range updates {
+		switch k {
+		case ExpectedRevisionKey:
+			revision, err := parseRevision(v)
+			if err != nil {
+				return fmt.Errorf("parsing %s: %w", ExpectedRevisionKey, err)
+			}
+			expectedRevision = &revision
+		case "revision":
+			// The revision is managed by the client.
+		default:
+			fields[k] = v
+		}
+	}
+
+	if err := c.checkPartialUpdateContextSchema(ctx, flagName, fields); err != nil {
+		return err
+	}
+
 	var err error
 	for i := 0; i < c.maxTries; i++ {
-		err = c.partialUpdateFlag(ctx, flagName, updates)
+		err = c.inTransaction(ctx, func(ctx context.Context) error {
+			before := c.currentFlag(ctx, flagName)
+			after, err := c.partialUpdateFlag(ctx, flagName, fields, expectedRevision)
+			if err != nil {
+				return err
+			}
+			return c.recordMutation(ctx, OperationUpdate, flagName, before, after)
+		})
 		if err == nil {
 			return nil
 		}
+		if isRevisionConflict(err) {
+			return err
+		}
 		c.logger.Error("error partially updating flag, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("update_flag")
 	}
 	return fmt.Errorf("partially updating flag %s after %d attempts: %w", flagName, c.maxTries, err)
 }
 
-func (c *Client) partialUpdateFlag(ctx context.Context, flagName string, updates map[string]any) error {
+// partialUpdateFlag updates a flag and returns it as stored.
+func (c *Client) partialUpdateFlag(ctx context.Context, flagName string, updates map[string]any, expectedRevision *int64) (*flag.Definition, error) {
 	if c.documentID != "" {
-		return c.partialUpdateFlagSingleDocument(ctx, flagName, updates)
+		return c.partialUpdateFlagSingleDocument(ctx, flagName, updates, expectedRevision)
 	}
-	return c.partialUpdateFlagMultiDocument(ctx, flagName, updates)
+	return c.partialUpdateFlagMultiDocument(ctx, flagName, updates, expectedRevision)
 }
 
-func (c *Client) partialUpdateFlagMultiDocument(ctx context.Context, flagName string, updates map[string]any) error {
+// applyPartialUpdate returns def with updates applied the way
+// partialUpdateFlag applies them to the stored flag. Keys may be dotted paths,
+// and append_rules appends to the rules. def is not modified.
+func applyPartialUpdate(def flag.Definition, updates map[string]any) (flag.Definition, error) {
+	def.Environments = maps.Clone(def.Environments)
+
+	set := bson.M{}
+	var appendRules any
+	for k, v := range updates {
+		if k == "append_rules" {
+			appendRules = v
+			continue
+		}
+		doc := set
+		path := strings.Split(k, ".")
+		for _, field := range path[:len(path)-1] {
+			next, ok := doc[field].(bson.M)
+			if !ok {
+				next = bson.M{}
+				doc[field] = next
+			}
+			doc = next
+		}
+		doc[path[len(path)-1]] = v
+	}
+
+	raw, err := bson.Marshal(set)
+	if err != nil {
+		return def, fmt.Errorf("marshalling updates: %w", err)
+	}
+	if err := bson.Unmarshal(raw, &def); err != nil {
+		return def, fmt.Errorf("applying updates: %w", err)
+	}
+
+	if appendRules != nil {
+		raw, err := bson.Marshal(bson.M{"rules": appendRules})
+		if err != nil {
+			return def, fmt.Errorf("marshalling append_rules: %w", err)
+		}
+		var appended flag.Definition
+		if err := bson.Unmarshal(raw, &appended); err != nil {
+			return def, fmt.Errorf("decoding append_rules: %w", err)
+		}
+		def.Rules = append(slices.Clip(def.Rules), appended.Rules...)
+	}
+	return def, nil
+}
+
+func (c *Client) partialUpdateFlagMultiDocument(ctx context.Context, flagName string, updates map[string]any, expectedRevision *int64) (*flag.Definition, error) {
 	// pull out append_rules if present
+	setDoc := bson.M{}
 	var toPush bson.M
-	if raw, ok := updates["append_rules"]; ok {
-		slice, ok := raw.([]any)
-		if !ok {
-			return errors.New("append_rules must be a slice")
+	for k, v := range updates {
+		if k == "append_rules" {
+			slice, ok := v.([]any)
+			if !ok {
+				return nil, errors.New("append_rules must be a slice")
+			}
+			toPush = bson.M{"rules": bson.M{"$each": slice}}
+			continue
 		}
-		toPush = bson.M{"rules": bson.M{"$each": slice}}
-		delete(updates, "append_rules")
+		setDoc[k] = v
 	}
 
-	updateDoc := bson.M{}
-	if len(updates) > 0 {
-		updateDoc["$set"] = updates
+	updateDoc := bson.M{
+		"$inc": bson.M{"revision": 1},
+	}
+	if len(setDoc) > 0 {
+		updateDoc["$set"] = setDoc
 	}
 	if toPush != nil {
 		updateDoc["$push"] = toPush
 	}
 
-	res, err := c.collection.UpdateOne(ctx,
-		bson.M{"_id": flagName},
-		updateDoc,
-	)
-	if err != nil {
-		return fmt.Errorf("updating flag %s: %w", flagName, err)
+	filter := bson.M{"_id": flagName}
+	if expectedRevision != nil {
+		filter["revision"] = revisionFilter(*expectedRevision)
 	}
-	if res.MatchedCount == 0 {
-		return fmt.Errorf("flag '%s' not found", flagName)
+	stored, err := c.decodeFlag(c.collection.FindOneAndUpdate(ctx, filter, updateDoc, c.returnFlag(flagName)), flagName)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if expectedRevision != nil && c.currentFlag(ctx, flagName) != nil {
+			return nil, c.revisionConflict(ctx, flagName, *expectedRevision)
+		}
+		return nil, fmt.Errorf("flag '%s' not found", flagName)
 	}
-	return nil
+	if err != nil {
+		return nil, fmt.Errorf("updating flag %s: %w", flagName, err)
+	}
+	return stored, nil
 }
 
-func (c *Client) partialUpdateFlagSingleDocument(ctx context.Context, flagName string, updates map[string]any) error {
+func (c *Client) partialUpdateFlagSingleDocument(ctx context.Context, flagName string, updates map[string]any, expectedRevision *int64) (*flag.Definition, error) {
 	setDoc := bson.M{}
 	var pushDoc bson.M
 
@@ -191,7 +463,9 @@ func (c *Client) partialUpdateFlagSingleDocument(ctx context.Context, flagName s
 		setDoc[fmt.Sprintf("%s.%s", flagName, k)] = v
 	}
 
-	updateDoc := bson.M{}
+	updateDoc := bson.M{
+		"$inc": bson.M{fmt.Sprintf("%s.revision", flagName): 1},
+	}
 	if len(setDoc) > 0 {
 		updateDoc["$set"] = setDoc
 	}
@@ -199,28 +473,45 @@ func (c *Client) partialUpdateFlagSingleDocument(ctx context.Context, flagName s
 		updateDoc["$push"] = pushDoc
 	}
 
-	res, err := c.collection.UpdateOne(ctx,
-		bson.M{"_id": c.documentID},
-		updateDoc,
-	)
+	// Without the $exists check a missing flag would be created as a stub
+	// holding only the incremented revision.
+	filter := bson.M{"_id": c.documentID, flagName: bson.M{"$exists": true}}
+	if expectedRevision != nil {
+		filter[fmt.Sprintf("%s.revision", flagName)] = revisionFilter(*expectedRevision)
+	}
+	stored, err := c.decodeFlag(c.collection.FindOneAndUpdate(ctx, filter, updateDoc, c.returnFlag(flagName)), flagName)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if expectedRevision != nil && c.currentFlag(ctx, flagName) != nil {
+			return nil, c.revisionConflict(ctx, flagName, *expectedRevision)
+		}
+		return nil, fmt.Errorf("flag '%s' not found in document %s", flagName, c.documentID)
+	}
 	if err != nil {
-		return fmt.Errorf("updating flag %s in doc %s: %w",
+		return nil, fmt.Errorf("updating flag %s in doc %s: %w",
 			flagName, c.documentID, err)
 	}
-	if res.MatchedCount == 0 {
-		return fmt.Errorf("document '%s' not found", c.documentID)
-	}
-	return nil
+	return stored, nil
 }
 
 func (c *Client) DeleteFlag(ctx context.Context, flagName string) error {
+	return c.deleteFlagAs(ctx, flagName, OperationDelete)
+}
+
+func (c *Client) deleteFlagAs(ctx context.Context, flagName string, operation Operation) error {
 	var err error
 	for i := 0; i < c.maxTries; i++ {
-		err = c.deleteFlag(ctx, flagName)
+		err = c.inTransaction(ctx, func(ctx context.Context) error {
+			before := c.currentFlag(ctx, flagName)
+			if err := c.deleteFlag(ctx, flagName); err != nil {
+				return err
+			}
+			return c.recordMutation(ctx, operation, flagName, before, nil)
+		})
 		if err == nil {
 			return nil
 		}
 		c.logger.Error("error deleting flag, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("delete_flag")
 	}
 
 	return fmt.Errorf("deleting flag %s after %d attempts: %w", flagName, c.maxTries, err)
@@ -267,6 +558,7 @@ func (c *Client) FlagExists(ctx context.Context, flagName string) (bool, error)
 			return exists, nil
 		}
 		c.logger.Error("error checking if flag exists, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("flag_exists")
 	}
 	return false, fmt.Errorf("checking if flag %s exists after %d attempts: %w", flagName, c.maxTries, err)
 }
@@ -310,6 +602,7 @@ func (c *Client) GetAllFlags(ctx context.Context) (map[string]flag.Definition, e
 			return result, nil
 		}
 		c.logger.Error("error getting all flags, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("get_all_flags")
 	}
 
 	return nil, fmt.Errorf("getting all flags after %d attempts: %w", c.maxTries, err)
@@ -0,0 +1,18 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/testutil"
+)
+
+// newTestClient returns a client on a fresh database. The test is skipped
+// when MongoDB is not available.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	mongoClient := testutil.MongoClient(t)
+	c, err := New(NewOptions(mongoClient, testutil.DatabaseName(t, mongoClient), "flags"))
+	require.NoError(t, err)
+	return c
+}
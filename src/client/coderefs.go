@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CodeReference is a place in a repository's source where a flag is
+// evaluated, as found by a code references scan.
+type CodeReference struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	FlagName   string        `bson:"flagName" json:"flagName"`
+	Repository string        `bson:"repository" json:"repository"`
+	// Path is relative to the repository's root.
+	Path    string `bson:"path" json:"path"`
+	Line    int    `bson:"line" json:"line"`
+	Snippet string `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	// URL links to the line in a code browser, when the scan was given a
+	// link template.
+	URL string `bson:"url,omitempty" json:"url,omitempty"`
+	// ScanID identifies the scan that found the reference; a repository's
+	// references are all from its latest scan.
+	ScanID    bson.ObjectID `bson:"scanID" json:"-"`
+	ScannedAt time.Time     `bson:"scannedAt" json:"scannedAt"`
+}
+
+// ReplaceCodeReferences replaces the stored references of a repository with
+// the references from a new scan. The new references are written before the
+// old ones are deleted, so readers never see the repository without any.
+func (c *Client) ReplaceCodeReferences(ctx context.Context, repository string, references []CodeReference) error {
+	c.codeReferenceIndexOnce.Do(func() {
+		_, err := c.codeReferenceCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "flagName", Value: 1}, {Key: "repository", Value: 1}}},
+			{Keys: bson.D{{Key: "repository", Value: 1}, {Key: "scanID", Value: 1}}},
+		})
+		if err != nil {
+			c.logger.Error("error creating code reference indexes", slog.Any("error", err))
+		}
+	})
+
+	scanID := bson.NewObjectID()
+	scannedAt := time.Now().UTC()
+	documents := make([]any, len(references))
+	for i := range references {
+		references[i].ID = bson.NewObjectID()
+		references[i].Repository = repository
+		references[i].ScanID = scanID
+		references[i].ScannedAt = scannedAt
+		documents[i] = references[i]
+	}
+
+	var err error
+	if len(documents) > 0 {
+		for i := 0; i < c.maxTries; i++ {
+			_, err = c.codeReferenceCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+			if err == nil || onlyDuplicateKeys(err) {
+				// Duplicate keys are references a previous attempt wrote.
+				err = nil
+				break
+			}
+			c.logger.Error("error adding code references, retrying", slog.Int("attempt", i+1), slog.String("repository", repository), slog.Any("error", err))
+			c.metrics.Retry("add_code_references")
+		}
+		if err != nil {
+			return fmt.Errorf("adding %d code references of '%s' after %d attempts: %w", len(references), repository, c.maxTries, err)
+		}
+	}
+
+	filter := bson.M{"repository": repository, "scanID": bson.M{"$ne": scanID}}
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.codeReferenceCollection.DeleteMany(ctx, filter)
+		if err == nil {
+			return nil
+		}
+		c.logger.Error("error deleting old code references, retrying", slog.Int("attempt", i+1), slog.String("repository", repository), slog.Any("error", err))
+		c.metrics.Retry("delete_code_references")
+	}
+	return fmt.Errorf("deleting old code references of '%s' after %d attempts: %w", repository, c.maxTries, err)
+}
+
+// ListCodeReferences returns the references to a flag in every scanned
+// repository, ordered by repository, path and line.
+func (c *Client) ListCodeReferences(ctx context.Context, flagName string) ([]CodeReference, error) {
+	findOpts := options.Find().SetSort(bson.D{{Key: "repository", Value: 1}, {Key: "path", Value: 1}, {Key: "line", Value: 1}})
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.codeReferenceCollection.Find(ctx, bson.M{"flagName": flagName}, findOpts)
+		if err == nil {
+			references := []CodeReference{}
+			if err = cursor.All(ctx, &references); err == nil {
+				return references, nil
+			}
+		}
+		c.logger.Error("error listing code references, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("list_code_references")
+	}
+	return nil, fmt.Errorf("listing code references of '%s' after %d attempts: %w", flagName, c.maxTries, err)
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ContextSchemaError is returned when a flag's rules read context keys that
+// are not in the context schema, or read them as another type than
+// declared. It matches mongoopenfeature.ErrContextSchema with errors.Is.
+type ContextSchemaError struct {
+	FlagName string
+	Issues   []contextschema.Issue
+}
+
+func (e *ContextSchemaError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return fmt.Sprintf("flag '%s' does not match the context schema: %s", e.FlagName, strings.Join(messages, "; "))
+}
+
+func (e *ContextSchemaError) Is(target error) bool {
+	return target == mongoopenfeature.ErrContextSchema
+}
+
+// checkContextSchema checks flags' rules against the context schema. It
+// returns a *ContextSchemaError for the first flag that does not match.
+func (c *Client) checkContextSchema(ctx context.Context, flagDefinitions ...flag.Definition) error {
+	if len(flagDefinitions) == 0 {
+		return nil
+	}
+	schema, err := c.GetContextSchema(ctx)
+	if err != nil {
+		return err
+	}
+	for _, def := range flagDefinitions {
+		if issues := schema.CheckDefinition(def); len(issues) > 0 {
+			return &ContextSchemaError{FlagName: def.FlagName, Issues: issues}
+		}
+	}
+	return nil
+}
+
+// checkPartialUpdateContextSchema checks the flag that applying updates to
+// the stored flag would produce against the context schema. A missing flag
+// is not checked, since the update itself fails.
+func (c *Client) checkPartialUpdateContextSchema(ctx context.Context, flagName string, updates map[string]any) error {
+	schema, err := c.GetContextSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if len(schema) == 0 {
+		return nil
+	}
+	current := c.currentFlag(ctx, flagName)
+	if current == nil {
+		return nil
+	}
+	updated, err := applyPartialUpdate(*current, updates)
+	if err != nil {
+		return err
+	}
+	if issues := schema.CheckDefinition(updated); len(issues) > 0 {
+		return &ContextSchemaError{FlagName: flagName, Issues: issues}
+	}
+	return nil
+}
+
+// GetContextSchema returns the declared context keys, sorted by name.
+func (c *Client) GetContextSchema(ctx context.Context) (contextschema.Schema, error) {
+	findOpts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.contextSchemaCollection.Find(ctx, bson.M{}, findOpts)
+		if err == nil {
+			schema := contextschema.Schema{}
+			if err = cursor.All(ctx, &schema); err == nil {
+				return schema, nil
+			}
+		}
+		c.logger.Error("error getting context schema, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("get_context_schema")
+	}
+	return nil, fmt.Errorf("getting context schema after %d attempts: %w", c.maxTries, err)
+}
+
+// SetContextKey adds a key to the context schema, or replaces the key with
+// the same name.
+func (c *Client) SetContextKey(ctx context.Context, key contextschema.Key) error {
+	key.Name = strings.TrimSpace(key.Name)
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.contextSchemaCollection.ReplaceOne(ctx, bson.M{"_id": key.Name}, key, options.Replace().SetUpsert(true))
+		if err == nil {
+			return nil
+		}
+		c.logger.Error("error setting context key, retrying", slog.Int("attempt", i+1), slog.String("key", key.Name), slog.Any("error", err))
+		c.metrics.Retry("set_context_key")
+	}
+	return fmt.Errorf("setting context key '%s' after %d attempts: %w", key.Name, c.maxTries, err)
+}
+
+// DeleteContextKey removes a key from the context schema.
+func (c *Client) DeleteContextKey(ctx context.Context, name string) error {
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var result *mongo.DeleteResult
+		result, err = c.contextSchemaCollection.DeleteOne(ctx, bson.M{"_id": name})
+		if err == nil {
+			if result.DeletedCount == 0 {
+				return fmt.Errorf("context key '%s' not found", name)
+			}
+			return nil
+		}
+		c.logger.Error("error deleting context key, retrying", slog.Int("attempt", i+1), slog.String("key", name), slog.Any("error", err))
+		c.metrics.Retry("delete_context_key")
+	}
+	return fmt.Errorf("deleting context key '%s' after %d attempts: %w", name, c.maxTries, err)
+}
+
+// observationKey identifies an observation document: a key sent with values
+// of one type.
+type observationKey struct {
+	Key  string             `bson:"key"`
+	Type contextschema.Type `bson:"type"`
+}
+
+// observationDocument is an observation as it is stored.
+type observationDocument struct {
+	ID       observationKey `bson:"_id"`
+	Count    int64          `bson:"count"`
+	LastSeen time.Time      `bson:"lastSeen"`
+}
+
+// AddContextObservations adds sampled counts of the keys and types callers
+// send in evaluation contexts. Counts from many providers add up.
+func (c *Client) AddContextObservations(ctx context.Context, observations []contextschema.Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(observations))
+	for i, o := range observations {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": observationKey{Key: o.Key, Type: o.Type}}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"count": o.Count},
+				"$max": bson.M{"lastSeen": o.LastSeen.UTC()},
+			}).
+			SetUpsert(true)
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.contextObservationCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err == nil {
+			return nil
+		}
+		c.logger.Error("error adding context observations, retrying", slog.Int("attempt", i+1), slog.Int("observations", len(observations)), slog.Any("error", err))
+		c.metrics.Retry("add_context_observations")
+	}
+	return fmt.Errorf("adding %d context observations after %d attempts: %w", len(observations), c.maxTries, err)
+}
+
+// ListContextObservations returns every key and type seen in sampled
+// contexts, sorted by key.
+func (c *Client) ListContextObservations(ctx context.Context) ([]contextschema.Observation, error) {
+	findOpts := options.Find().SetSort(bson.D{{Key: "_id.key", Value: 1}, {Key: "_id.type", Value: 1}})
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.contextObservationCollection.Find(ctx, bson.M{}, findOpts)
+		if err == nil {
+			var documents []observationDocument
+			if err = cursor.All(ctx, &documents); err == nil {
+				observations := make([]contextschema.Observation, len(documents))
+				for i, d := range documents {
+					observations[i] = contextschema.Observation{Key: d.ID.Key, Type: d.ID.Type, Count: d.Count, LastSeen: d.LastSeen}
+				}
+				return observations, nil
+			}
+		}
+		c.logger.Error("error listing context observations, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("list_context_observations")
+	}
+	return nil, fmt.Errorf("listing context observations after %d attempts: %w", c.maxTries, err)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func TestApplyPartialUpdate(t *testing.T) {
+	def := flag.Definition{
+		FlagName:       "checkout",
+		DefaultVariant: "off",
+		Rules:          []rule.ConcreteRule{{PrefixRule: &rule.PrefixRule{Key: "country"}}},
+		Environments: map[string]flag.Environment{
+			"dev":  {DefaultVariant: "on"},
+			"prod": {DefaultVariant: "off"},
+		},
+	}
+
+	updated, err := applyPartialUpdate(def, map[string]any{
+		"defaultVariant":          "on",
+		"environments.prod.rules": []rule.ConcreteRule{{SuffixRule: &rule.SuffixRule{Key: "email"}}},
+		"append_rules":            []any{map[string]any{"exactMatchRule": map[string]any{"key": "plan"}}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "on", updated.DefaultVariant)
+	require.Len(t, updated.Rules, 2)
+	assert.Equal(t, "country", updated.Rules[0].PrefixRule.Key)
+	assert.Equal(t, "plan", updated.Rules[1].ExactMatchRule.Key)
+	assert.Equal(t, "on", updated.Environments["dev"].DefaultVariant)
+	require.Len(t, updated.Environments["prod"].Rules, 1)
+	assert.Equal(t, "email", updated.Environments["prod"].Rules[0].SuffixRule.Key)
+
+	assert.Equal(t, "off", def.DefaultVariant, "the input is not modified")
+	assert.Len(t, def.Rules, 1)
+	assert.Empty(t, def.Environments["prod"].Rules)
+}
+
+func TestContextSchemaChecksEveryWrite(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	require.NoError(t, c.SetContextKey(ctx, contextschema.Key{Name: "country", Type: contextschema.TypeString}))
+
+	valid := flag.Definition{
+		FlagName:       "checkout",
+		DefaultVariant: "off",
+		Rules:          []rule.ConcreteRule{{PrefixRule: &rule.PrefixRule{Key: "country"}}},
+	}
+	require.NoError(t, c.SetFlag(ctx, valid))
+
+	err := c.PartialUpdateFlag(ctx, "checkout", map[string]any{
+		"append_rules": []rule.ConcreteRule{{PrefixRule: &rule.PrefixRule{Key: "contry"}}},
+	})
+	assert.ErrorIs(t, err, mongoopenfeature.ErrContextSchema)
+
+	invalid := flag.Definition{
+		FlagName: "search",
+		Rules:    []rule.ConcreteRule{{RangeRule: &rule.RangeRule{Key: "country"}}},
+	}
+	err = c.ApplyBatch(ctx, []BatchOperation{DeleteOperation("checkout"), SetOperation(invalid)})
+	assert.ErrorIs(t, err, mongoopenfeature.ErrContextSchema)
+
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Len(t, stored.Rules, 1, "rejected writes change nothing")
+	exists, err := c.FlagExists(ctx, "search")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// PromoteFlag copies a flag's configuration in the from environment to the to
+// environment and returns the changes it made. Either name may be empty to
+// refer to the base definition. When expectedRevision is non-zero the flag
+// must still be at that revision, e.g. the one a reviewed diff was built
+// from; otherwise a *RevisionConflictError is returned. The write is always
+// conditional on the revision that was read, including flags written before
+// revisions were tracked, so a concurrent edit is never overwritten.
+func (c *Client) PromoteFlag(ctx context.Context, flagName, from, to string, expectedRevision int64) ([]flag.Change, error) {
+	if from == to {
+		return nil, fmt.Errorf("cannot promote flag %s from environment '%s' to itself", flagName, from)
+	}
+	current, err := c.GetFlag(ctx, flagName)
+	if err != nil {
+		return nil, err
+	}
+	if expectedRevision != 0 && current.Revision != expectedRevision {
+		return nil, &RevisionConflictError{FlagName: flagName, Expected: expectedRevision, Actual: current.Revision}
+	}
+	promoted := current.Promote(from, to)
+	changes := flag.Diff(current, &promoted)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if err := c.writeFlagAs(ctx, promoted, OperationPromote, c.setFlagAtRevision); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
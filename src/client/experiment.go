@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/experiment"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ExperimentQuery selects the impressions and tracking events an experiment
+// is analysed from.
+type ExperimentQuery struct {
+	FlagName string `json:"flagName"`
+	// EventName is the tracking event that counts as a conversion.
+	EventName string `json:"eventName"`
+	// Since and Until bound the impressions and events; zero times leave
+	// the range open.
+	Since time.Time `json:"since,omitzero"`
+	Until time.Time `json:"until,omitzero"`
+	// Control is the variant the others are compared with. See
+	// experiment.Analyze for how it is chosen when empty.
+	Control string `json:"control,omitempty"`
+	// Weights are the expected shares of users per variant, used to check
+	// for a sample ratio mismatch. Users are expected to be split evenly
+	// when empty.
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// ExperimentResults is the analysis of an experiment.
+type ExperimentResults struct {
+	Query ExperimentQuery `json:"query"`
+	experiment.Results
+}
+
+// ExperimentResults counts the users exposed to each variant of the flag and
+// the conversions linked to them, and analyses the counts.
+func (c *Client) ExperimentResults(ctx context.Context, query ExperimentQuery) (*ExperimentResults, error) {
+	counts, err := c.ExperimentCounts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentResults{
+		Query:   query,
+		Results: experiment.Analyze(counts, query.Control, query.Weights),
+	}, nil
+}
+
+// ExperimentCounts counts, per variant of the flag, the users and
+// impressions exposed to it, and the users who then sent the event. Users
+// served more than one variant are counted in each.
+func (c *Client) ExperimentCounts(ctx context.Context, query ExperimentQuery) ([]experiment.VariantCounts, error) {
+	if query.FlagName == "" {
+		return nil, errors.New("experiment query is missing a flag name")
+	}
+	if query.EventName == "" {
+		return nil, errors.New("experiment query is missing an event name")
+	}
+
+	timestamp := bson.M{}
+	if !query.Since.IsZero() {
+		timestamp["$gte"] = query.Since
+	}
+	if !query.Until.IsZero() {
+		timestamp["$lt"] = query.Until
+	}
+
+	exposureMatch := bson.M{"flagName": query.FlagName}
+	if len(timestamp) > 0 {
+		exposureMatch["timestamp"] = timestamp
+	}
+	exposures := []experimentRow{}
+	err := c.aggregate(ctx, c.impressionCollection, "experiment_exposures", mongo.Pipeline{
+		{{Key: "$match", Value: exposureMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"variant": "$variant", "user": "$targetingKey"},
+			"impressions": bson.M{"$sum": 1},
+		}}},
+		// Impressions without a targeting key are not a user.
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$_id.variant",
+			"users":       bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$_id.user", nil}}, 1, 0}}},
+			"impressions": bson.M{"$sum": "$impressions"},
+		}}},
+	}, &exposures)
+	if err != nil {
+		return nil, fmt.Errorf("counting exposures: %w", err)
+	}
+
+	conversionMatch := bson.M{
+		"name":                 query.EventName,
+		"impressions.flagName": query.FlagName,
+		"targetingKey":         bson.M{"$exists": true},
+	}
+	if len(timestamp) > 0 {
+		conversionMatch["timestamp"] = timestamp
+	}
+	conversions := []experimentRow{}
+	err = c.aggregate(ctx, c.trackingCollection, "experiment_conversions", mongo.Pipeline{
+		{{Key: "$match", Value: conversionMatch}},
+		{{Key: "$unwind", Value: "$impressions"}},
+		{{Key: "$match", Value: bson.M{"impressions.flagName": query.FlagName}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"variant": "$impressions.variant", "user": "$targetingKey"},
+			"events": bson.M{"$sum": 1},
+			"value":  bson.M{"$sum": "$value"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$_id.variant",
+			"converters": bson.M{"$sum": 1},
+			"events":     bson.M{"$sum": "$events"},
+			"value":      bson.M{"$sum": "$value"},
+		}}},
+	}, &conversions)
+	if err != nil {
+		return nil, fmt.Errorf("counting conversions: %w", err)
+	}
+
+	byVariant := map[string]*experiment.VariantCounts{}
+	var counts []*experiment.VariantCounts
+	get := func(variant string) *experiment.VariantCounts {
+		if count, ok := byVariant[variant]; ok {
+			return count
+		}
+		count := &experiment.VariantCounts{Variant: variant}
+		byVariant[variant] = count
+		counts = append(counts, count)
+		return count
+	}
+	for _, row := range exposures {
+		count := get(row.Variant)
+		count.Users = row.Users
+		count.Impressions = row.Impressions
+	}
+	for _, row := range conversions {
+		count := get(row.Variant)
+		count.Converters = row.Converters
+		count.Events = row.Events
+		count.Value = row.Value
+	}
+
+	result := make([]experiment.VariantCounts, len(counts))
+	for i, count := range counts {
+		result[i] = *count
+	}
+	return result, nil
+}
+
+// experimentRow is a row of the experiment aggregations.
+type experimentRow struct {
+	Variant     string  `bson:"_id"`
+	Users       int64   `bson:"users"`
+	Impressions int64   `bson:"impressions"`
+	Converters  int64   `bson:"converters"`
+	Events      int64   `bson:"events"`
+	Value       float64 `bson:"value"`
+}
+
+// aggregate runs pipeline on collection and decodes every result into out,
+// retrying on failure. operation names the aggregation in logs and metrics.
+func (c *Client) aggregate(ctx context.Context, collection *mongo.Collection, operation string, pipeline mongo.Pipeline, out any) error {
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = collection.Aggregate(ctx, pipeline)
+		if err == nil {
+			if err = cursor.All(ctx, out); err == nil {
+				return nil
+			}
+		}
+		c.logger.Error("error running aggregation, retrying", slog.Int("attempt", i+1), slog.String("operation", operation), slog.Any("error", err))
+		c.metrics.Retry(operation)
+	}
+	return fmt.Errorf("running %s after %d attempts: %w", operation, c.maxTries, err)
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+)
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Mode decides what happens to stored flags missing from the set. It
+	// defaults to flagset.ModeMerge.
+	Mode flagset.Mode
+	// DryRun computes the plan without writing anything.
+	DryRun bool
+}
+
+// Export returns every stored flag as a portable flag set.
+func (c *Client) Export(ctx context.Context) (*flagset.Set, error) {
+	flags, err := c.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("exporting flags: %w", err)
+	}
+	definitions := make([]flag.Definition, 0, len(flags))
+	for _, def := range flags {
+		definitions = append(definitions, def)
+	}
+	return flagset.New(definitions), nil
+}
+
+// Import writes the flags in set and returns the per-flag plan it applied.
+// With DryRun set the plan is returned without writing anything.
+//
+// The import is applied as a single batch. Flags it changes or removes are
+// written at the revision they had when the plan was made, including flags
+// written before revisions were tracked, so if one of them is changed
+// concurrently nothing is imported and a *RevisionConflictError is returned.
+func (c *Client) Import(ctx context.Context, set *flagset.Set, opts ImportOptions) (*flagset.Plan, error) {
+	if err := set.Validate(); err != nil {
+		return nil, fmt.Errorf("validating flag set: %w", err)
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = flagset.ModeMerge
+	}
+	if mode != flagset.ModeMerge && mode != flagset.ModeReplace {
+		return nil, fmt.Errorf("unknown import mode '%s'", mode)
+	}
+
+	current, err := c.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading current flags: %w", err)
+	}
+	plan := flagset.NewPlan(current, set, mode)
+	if opts.DryRun || !plan.HasChanges() {
+		return plan, nil
+	}
+
+	var ops []BatchOperation
+	for _, diff := range plan.Flags {
+		switch diff.Kind {
+		case flagset.DiffAdded:
+			def := *diff.Incoming
+			def.Revision = 0
+			ops = append(ops, SetOperation(def))
+		case flagset.DiffChanged:
+			def := *diff.Incoming
+			def.Revision = 0
+			ops = append(ops, SetOperation(def).AtRevision(diff.Current.Revision))
+		case flagset.DiffRemoved:
+			ops = append(ops, DeleteOperation(diff.FlagName).AtRevision(diff.Current.Revision))
+		}
+	}
+	if err := c.ApplyBatch(ctx, ops); err != nil {
+		return nil, fmt.Errorf("importing flags: %w", err)
+	}
+	return plan, nil
+}
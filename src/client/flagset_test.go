@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flagset"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestImportFlagsWithoutStoredRevision(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	// Flags written before revisions were tracked.
+	_, err := c.collection.InsertMany(ctx, []any{
+		bson.M{"_id": "checkout", "defaultVariant": "off"},
+		bson.M{"_id": "search", "defaultVariant": "off"},
+	})
+	require.NoError(t, err)
+
+	set := flagset.New([]flag.Definition{{FlagName: "checkout", DefaultVariant: "on"}})
+	_, err = c.Import(ctx, set, ImportOptions{Mode: flagset.ModeReplace})
+	require.NoError(t, err)
+
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "on", stored.DefaultVariant)
+	exists, err := c.FlagExists(ctx, "search")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestBatchConditionalDelete(t *testing.T) {
+	for name, newClient := range map[string]func(*testing.T) *Client{
+		"MultiDocument":  newTestClient,
+		"SingleDocument": newSingleDocumentTestClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newClient(t)
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "off"}))
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "on"}))
+
+			err := c.ApplyBatch(ctx, []BatchOperation{
+				DeleteOperation("checkout").AtRevision(1),
+				DeleteOperation("search").AtRevision(1),
+			})
+			assert.ErrorIs(t, err, mongoopenfeature.ErrRevisionConflict)
+			exists, err := c.FlagExists(ctx, "checkout")
+			require.NoError(t, err)
+			assert.True(t, exists, "nothing is deleted when a revision does not match")
+
+			require.NoError(t, c.ApplyBatch(ctx, []BatchOperation{
+				DeleteOperation("checkout").AtRevision(1),
+				DeleteOperation("search").AtRevision(2),
+			}))
+			flags, err := c.GetAllFlags(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, flags)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Operation is the kind of write that produced a flag version.
+type Operation string
+
+const (
+	OperationSet      Operation = "set"
+	OperationUpdate   Operation = "update"
+	OperationDelete   Operation = "delete"
+	OperationRollback Operation = "rollback"
+	OperationPromote  Operation = "promote"
+)
+
+// Version is an immutable snapshot of a flag, recorded after every write made
+// through the client.
+type Version struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	FlagName  string        `bson:"flagName" json:"flagName"`
+	Version   int           `bson:"version" json:"version"`
+	Operation Operation     `bson:"operation" json:"operation"`
+	// Author describes the Actor that made the write.
+	Author    string    `bson:"author,omitempty" json:"author,omitempty"`
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+	// Definition is the flag after the write, or nil when the write deleted it.
+	Definition *flag.Definition `bson:"definition,omitempty" json:"definition,omitempty"`
+	// Diff lists the changes from the previous state of the flag.
+	Diff []flag.Change `bson:"diff,omitempty" json:"diff,omitempty"`
+}
+
+// ListVersions returns the recorded versions of a flag, newest first.
+func (c *Client) ListVersions(ctx context.Context, flagName string) ([]Version, error) {
+	var err error
+	var result []Version
+	for i := 0; i < c.maxTries; i++ {
+		result, err = c.listVersions(ctx, flagName)
+		if err == nil {
+			return result, nil
+		}
+		c.logger.Error("error listing flag versions, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("list_flag_versions")
+	}
+	return nil, fmt.Errorf("listing versions of flag %s after %d attempts: %w", flagName, c.maxTries, err)
+}
+
+func (c *Client) listVersions(ctx context.Context, flagName string) ([]Version, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := c.historyCollection.Find(ctx, bson.M{"flagName": flagName}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("finding versions: %w", err)
+	}
+	versions := []Version{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("decoding versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersion returns a single recorded version of a flag.
+func (c *Client) GetVersion(ctx context.Context, flagName string, version int) (*Version, error) {
+	var err error
+	var result *Version
+	for i := 0; i < c.maxTries; i++ {
+		result, err = c.getVersion(ctx, flagName, version)
+		if err == nil || errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		c.logger.Error("error getting flag version, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Int("version", version), slog.Any("error", err))
+		c.metrics.Retry("get_flag_version")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting version %d of flag %s: %w", version, flagName, err)
+	}
+	return result, nil
+}
+
+func (c *Client) getVersion(ctx context.Context, flagName string, version int) (*Version, error) {
+	var result Version
+	err := c.historyCollection.FindOne(ctx, bson.M{"flagName": flagName, "version": version}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Rollback restores a flag to the state recorded in version. Rolling back to
+// a version that deleted the flag deletes it again. The rollback itself is
+// recorded as a new version.
+func (c *Client) Rollback(ctx context.Context, flagName string, version int) error {
+	target, err := c.GetVersion(ctx, flagName, version)
+	if err != nil {
+		return err
+	}
+	if target.Definition == nil {
+		return c.deleteFlagAs(ctx, flagName, OperationRollback)
+	}
+	// Rolling back restores the old contents regardless of what has been
+	// written since, so it is not conditional on a revision.
+	restored := *target.Definition
+	restored.Revision = 0
+	return c.setFlagAs(ctx, restored, OperationRollback)
+}
+
+// currentFlag returns the stored flag, or nil if it does not exist or cannot
+// be read. It is used to capture the before and after states of a write.
+func (c *Client) currentFlag(ctx context.Context, flagName string) *flag.Definition {
+	def, err := c.getFlag(ctx, flagName)
+	if err != nil {
+		return nil
+	}
+	return def
+}
+
+// recordVersion appends a version for a completed write. Inside a transaction
+// it shares the write's outcome; see recordMutation.
+func (c *Client) recordVersion(ctx context.Context, operation Operation, flagName string, before, after *flag.Definition) error {
+	// Without the unique index nextVersion could hand out the same number
+	// twice, so no version is recorded until it exists.
+	err := c.historyIndexes.ensure(c.historyCollection, []mongo.IndexModel{{
+		Keys:    bson.D{{Key: "flagName", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}})
+	if err != nil {
+		return err
+	}
+
+	version := Version{
+		FlagName:   flagName,
+		Operation:  operation,
+		Author:     ActorFromContext(ctx).String(),
+		Timestamp:  time.Now().UTC(),
+		Definition: after,
+		Diff:       flag.Diff(before, after),
+	}
+
+	for i := 0; i < c.maxTries; i++ {
+		version.Version, err = c.nextVersion(ctx, flagName)
+		if err != nil {
+			continue
+		}
+		version.ID = bson.NewObjectID()
+		_, err = c.historyCollection.InsertOne(ctx, version)
+		if err == nil || transactional(ctx) {
+			// A failed write aborts the transaction, so only a retry of
+			// the whole transaction can succeed.
+			break
+		}
+		// A duplicate key means another writer took this version number;
+		// the next attempt picks the one after it.
+	}
+	if err != nil {
+		return fmt.Errorf("recording version of flag %s: %w", flagName, err)
+	}
+	return nil
+}
+
+func (c *Client) nextVersion(ctx context.Context, flagName string) (int, error) {
+	var latest Version
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}).SetProjection(bson.M{"version": 1})
+	err := c.historyCollection.FindOne(ctx, bson.M{"flagName": flagName}, opts).Decode(&latest)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 1, nil
+		}
+		return 0, err
+	}
+	return latest.Version + 1, nil
+}
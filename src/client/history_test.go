@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+func TestHistoryRecordsStoredFlags(t *testing.T) {
+	for name, newClient := range map[string]func(*testing.T) *Client{
+		"MultiDocument":  newTestClient,
+		"SingleDocument": newSingleDocumentTestClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newClient(t)
+
+			require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+			require.NoError(t, c.PartialUpdateFlag(ctx, "checkout", map[string]any{"defaultVariant": "on"}))
+			require.NoError(t, c.DeleteFlag(ctx, "checkout"))
+
+			versions, err := c.ListVersions(ctx, "checkout")
+			require.NoError(t, err)
+			require.Len(t, versions, 3)
+
+			byNumber := map[int]Version{}
+			for _, v := range versions {
+				byNumber[v.Version] = v
+			}
+			require.NotNil(t, byNumber[1].Definition)
+			assert.Equal(t, int64(1), byNumber[1].Definition.Revision, "versions hold the flag as the write stored it")
+			require.NotNil(t, byNumber[2].Definition)
+			assert.Equal(t, int64(2), byNumber[2].Definition.Revision)
+			assert.Equal(t, "on", byNumber[2].Definition.DefaultVariant)
+			assert.Nil(t, byNumber[3].Definition)
+		})
+	}
+}
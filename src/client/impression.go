@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Impression records that a variant of a flag was served to a user.
+type Impression struct {
+	ID           bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	FlagName     string        `bson:"flagName" json:"flagName"`
+	Variant      string        `bson:"variant" json:"variant"`
+	TargetingKey string        `bson:"targetingKey,omitempty" json:"targetingKey,omitempty"`
+	// RuleIndex is the index of the rule that matched, or nil when the
+	// flag's default was served.
+	RuleIndex *int      `bson:"ruleIndex,omitempty" json:"ruleIndex,omitempty"`
+	Reason    string    `bson:"reason" json:"reason"`
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// AddImpressions writes impressions in bulk. Impressions without an ID are
+// given one, so retrying a partly written batch does not duplicate them.
+func (c *Client) AddImpressions(ctx context.Context, impressions []Impression) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+	c.impressionIndexOnce.Do(func() {
+		_, err := c.impressionCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "flagName", Value: 1}, {Key: "timestamp", Value: 1}}},
+			{Keys: bson.D{{Key: "targetingKey", Value: 1}, {Key: "timestamp", Value: 1}}},
+		})
+		if err != nil {
+			c.logger.Error("error creating impression indexes", slog.Any("error", err))
+		}
+	})
+
+	documents := make([]any, len(impressions))
+	for i := range impressions {
+		if impressions[i].ID.IsZero() {
+			impressions[i].ID = bson.NewObjectID()
+		}
+		documents[i] = impressions[i]
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.impressionCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+		if err == nil || onlyDuplicateKeys(err) {
+			// Duplicate keys are impressions a previous attempt wrote.
+			return nil
+		}
+		c.logger.Error("error adding impressions, retrying", slog.Int("attempt", i+1), slog.Int("impressions", len(impressions)), slog.Any("error", err))
+		c.metrics.Retry("add_impressions")
+	}
+	return fmt.Errorf("adding %d impressions after %d attempts: %w", len(impressions), c.maxTries, err)
+}
+
+// onlyDuplicateKeys reports whether every write error in a bulk insert was a
+// duplicate key.
+func onlyDuplicateKeys(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr.WriteError) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Mismatch describes a flag that differs between the source and target of a
+// migration.
+type Mismatch struct {
+	FlagName string `json:"flagName"`
+	// MissingIn is "source" or "target" when the flag only exists on one
+	// side, and empty when it exists on both.
+	MissingIn string `json:"missingIn,omitempty"`
+	// Changes lists the differences from the source to the target.
+	Changes []flag.Change `json:"changes,omitempty"`
+	// SourceRevision and TargetRevision are the stored revisions.
+	SourceRevision int64 `json:"sourceRevision,omitempty"`
+	TargetRevision int64 `json:"targetRevision,omitempty"`
+}
+
+// MigrationReport summarises a migration run.
+type MigrationReport struct {
+	// Flags is the number of flags in the source.
+	Flags int `json:"flags"`
+	// Written is the number of flags that were copied because they were
+	// missing or different in the target.
+	Written int `json:"written"`
+	// Deleted is the number of flags removed from the target because they
+	// are no longer in the source.
+	Deleted int `json:"deleted"`
+	// Mismatches lists the flags that still differ after the copy. An
+	// empty list means the target is equivalent to the source.
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+}
+
+// Verified reports whether the target matched the source after the copy.
+func (r *MigrationReport) Verified() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Migrate copies every flag from c to target, removes flags from target that
+// are not in c, and then verifies that both hold equivalent flags. The two
+// clients may use different storage layouts, which is how flags are moved
+// between the single-document and multi-document modes.
+//
+// Flags are copied verbatim, revisions included, so conditional writes keep
+// working after a cutover. Only flags that differ are written, which makes
+// repeated runs cheap; see MirrorTo. Edits made directly to target are
+// overwritten, and flags only in target are deleted. History, audit and
+// scheduled changes are not copied; create target with MigrationTarget to keep
+// them.
+func (c *Client) Migrate(ctx context.Context, target *Client) (*MigrationReport, error) {
+	if err := c.checkMigrationTarget(target); err != nil {
+		return nil, err
+	}
+
+	source, err := c.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading source flags: %w", err)
+	}
+	existing, err := target.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading target flags: %w", err)
+	}
+
+	report := &MigrationReport{Flags: len(source)}
+	var writes []flag.Definition
+	var deletes []string
+	for name, def := range source {
+		if current, ok := existing[name]; !ok || !sameStoredFlag(def, current) {
+			writes = append(writes, def)
+		}
+	}
+	for name := range existing {
+		if _, ok := source[name]; !ok {
+			deletes = append(deletes, name)
+		}
+	}
+
+	if len(writes) > 0 || len(deletes) > 0 {
+		for i := 0; i < target.maxTries; i++ {
+			err = target.writeStoredFlags(ctx, source, writes, deletes)
+			if err == nil {
+				break
+			}
+			target.logger.Error("error writing migrated flags, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+			target.metrics.Retry("migrate_flags")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing migrated flags after %d attempts: %w", target.maxTries, err)
+		}
+	}
+	report.Written = len(writes)
+	report.Deleted = len(deletes)
+
+	report.Mismatches, err = c.VerifyMigration(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// VerifyMigration compares the flags stored by c and target and returns the
+// flags that differ, sorted by name. Revisions are compared too, since a
+// revision mismatch would break conditional writes after a cutover.
+func (c *Client) VerifyMigration(ctx context.Context, target *Client) ([]Mismatch, error) {
+	source, err := c.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading source flags: %w", err)
+	}
+	existing, err := target.GetAllFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading target flags: %w", err)
+	}
+
+	var mismatches []Mismatch
+	for name, def := range source {
+		current, ok := existing[name]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{FlagName: name, MissingIn: "target", SourceRevision: def.Revision})
+			continue
+		}
+		if !sameStoredFlag(def, current) {
+			mismatches = append(mismatches, Mismatch{
+				FlagName:       name,
+				Changes:        flag.Diff(&def, &current),
+				SourceRevision: def.Revision,
+				TargetRevision: current.Revision,
+			})
+		}
+	}
+	for name, def := range existing {
+		if _, ok := source[name]; !ok {
+			mismatches = append(mismatches, Mismatch{FlagName: name, MissingIn: "source", TargetRevision: def.Revision})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].FlagName < mismatches[j].FlagName })
+	return mismatches, nil
+}
+
+// MirrorTo runs Migrate every interval until ctx is cancelled, keeping target
+// in step with writes that still go to c. Every run makes target an exact
+// copy of c, so target must not be written to while the mirror runs: target
+// edits are reverted and flags only in target are deleted. A cutover while
+// providers are running goes:
+//
+//  1. Start MirrorTo from the old layout to the new one.
+//  2. Restart providers on the new layout. They read the mirrored flags,
+//     which stay current because writes still land in the old layout.
+//  3. Freeze writes to the old layout (editor, MCP server, scripts).
+//  4. Stop MirrorTo by cancelling ctx. It runs Migrate one final time and
+//     returns that run's report, which must be verified.
+//  5. Move writers to the new layout.
+//
+// onRun, if not nil, is called with the report of every run before the final
+// one. Errors from those runs are logged and retried on the next tick; an
+// error from the final run is returned.
+func (c *Client) MirrorTo(ctx context.Context, target *Client, interval time.Duration, onRun func(*MigrationReport)) (*MigrationReport, error) {
+	if err := c.checkMigrationTarget(target); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, errors.New("mirror interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := c.Migrate(ctx, target)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			c.logger.Error("error mirroring flags", slog.Any("error", err))
+		} else if onRun != nil {
+			onRun(report)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	// The final run copies whatever was written before writes were frozen,
+	// so it must finish even though ctx is done.
+	report, err := c.Migrate(context.WithoutCancel(ctx), target)
+	if err != nil {
+		return nil, fmt.Errorf("final mirror run: %w", err)
+	}
+	return report, nil
+}
+
+// MigrationTarget returns a client for another storage layout that shares
+// every collection of c except the flag collection. History, audit, scheduled
+// changes, webhooks, impressions, tracking events, usage, code references and
+// the context schema therefore carry over when flags are migrated to it, even
+// when the target is in a different database.
+func (c *Client) MigrationTarget(database, collection, documentID string) (*Client, error) {
+	target, err := New(NewOptions(c.collection.Database().Client(), database, collection).
+		WithDocumentID(documentID).
+		WithMaxTries(c.maxTries).
+		WithLogger(c.logger).
+		WithMetrics(c.metrics))
+	if err != nil {
+		return nil, err
+	}
+	target.scheduleCollection = c.scheduleCollection
+	target.historyCollection = c.historyCollection
+	target.auditCollection = c.auditCollection
+	target.webhookCollection = c.webhookCollection
+	target.deadLetterCollection = c.deadLetterCollection
+	target.impressionCollection = c.impressionCollection
+	target.trackingCollection = c.trackingCollection
+	target.usageCollection = c.usageCollection
+	target.codeReferenceCollection = c.codeReferenceCollection
+	target.contextSchemaCollection = c.contextSchemaCollection
+	target.contextObservationCollection = c.contextObservationCollection
+	return target, nil
+}
+
+// checkMigrationTarget rejects targets that share storage with c. A
+// multi-document collection cannot hold a single flag document either, since
+// every document in it is read as a flag.
+func (c *Client) checkMigrationTarget(target *Client) error {
+	if target == nil {
+		return errors.New("migration target is required")
+	}
+	sameCollection := c.collection.Database().Name() == target.collection.Database().Name() &&
+		c.collection.Name() == target.collection.Name()
+	if !sameCollection {
+		return nil
+	}
+	if c.documentID == target.documentID {
+		return errors.New("migration source and target are the same")
+	}
+	if c.documentID == "" || target.documentID == "" {
+		return fmt.Errorf("collection %s cannot hold both storage layouts, migrate to a different collection", c.collection.Name())
+	}
+	return nil
+}
+
+// sameStoredFlag reports whether two stored definitions are identical,
+// revision included.
+func sameStoredFlag(a, b flag.Definition) bool {
+	return a.Revision == b.Revision && len(flag.Diff(&a, &b)) == 0
+}
+
+// writeStoredFlags stores definitions verbatim, without bumping revisions or
+// recording history. In single-document mode the whole document is replaced
+// with all flags, which is one atomic write.
+func (c *Client) writeStoredFlags(ctx context.Context, all map[string]flag.Definition, writes []flag.Definition, deletes []string) error {
+	if c.documentID != "" {
+		doc := bson.D{{Key: "_id", Value: c.documentID}}
+		names := make([]string, 0, len(all))
+		for name := range all {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			flagDoc, err := marshalDefinition(all[name])
+			if err != nil {
+				return err
+			}
+			doc = append(doc, bson.E{Key: name, Value: flagDoc})
+		}
+		if _, err := c.collection.ReplaceOne(ctx, bson.M{"_id": c.documentID}, doc, options.Replace().SetUpsert(true)); err != nil {
+			return fmt.Errorf("replacing doc %s: %w", c.documentID, err)
+		}
+		return nil
+	}
+
+	for _, def := range writes {
+		flagDoc, err := marshalDefinition(def)
+		if err != nil {
+			return err
+		}
+		flagDoc = append(bson.D{{Key: "_id", Value: def.FlagName}}, flagDoc...)
+		if _, err := c.collection.ReplaceOne(ctx, bson.M{"_id": def.FlagName}, flagDoc, options.Replace().SetUpsert(true)); err != nil {
+			return fmt.Errorf("replacing flag %s: %w", def.FlagName, err)
+		}
+	}
+	if len(deletes) > 0 {
+		if _, err := c.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": deletes}}); err != nil {
+			return fmt.Errorf("deleting flags: %w", err)
+		}
+	}
+	return nil
+}
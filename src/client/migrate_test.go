@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func TestMigrateRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newTestClient(t)
+	database := source.collection.Database().Name()
+	require.NoError(t, source.SetFlag(ctx, flag.Definition{
+		FlagName:       "checkout",
+		DefaultVariant: "off",
+		Rules:          []rule.ConcreteRule{{PrefixRule: &rule.PrefixRule{Key: "country", Prefix: "N", VariantID: "on"}}},
+		Environments:   map[string]flag.Environment{"prod": {DefaultVariant: "on"}},
+	}))
+	require.NoError(t, source.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "off"}))
+	require.NoError(t, source.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "on"}))
+
+	single, err := source.MigrationTarget(database, "flags_single", "flags")
+	require.NoError(t, err)
+	report, err := source.Migrate(ctx, single)
+	require.NoError(t, err)
+	assert.True(t, report.Verified())
+	assert.Equal(t, 2, report.Written)
+
+	stored, err := single.GetFlag(ctx, "search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stored.Revision, "revisions are copied verbatim")
+
+	report, err = source.Migrate(ctx, single)
+	require.NoError(t, err)
+	assert.Zero(t, report.Written, "an up-to-date target is not written")
+
+	// Back to the multi-document layout, from the single document.
+	multi, err := single.MigrationTarget(database, "flags_multi", "")
+	require.NoError(t, err)
+	report, err = single.Migrate(ctx, multi)
+	require.NoError(t, err)
+	assert.True(t, report.Verified())
+
+	mismatches, err := source.VerifyMigration(ctx, multi)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches, "a round trip keeps every flag")
+
+	// Target-only flags are removed and target edits reverted.
+	require.NoError(t, multi.SetFlag(ctx, flag.Definition{FlagName: "banner", DefaultVariant: "on"}))
+	require.NoError(t, multi.SetFlag(ctx, flag.Definition{FlagName: "search", DefaultVariant: "off"}))
+	mismatches, err = source.VerifyMigration(ctx, multi)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "banner", mismatches[0].FlagName)
+	assert.Equal(t, "source", mismatches[0].MissingIn)
+	assert.Equal(t, "search", mismatches[1].FlagName)
+
+	report, err = source.Migrate(ctx, multi)
+	require.NoError(t, err)
+	assert.True(t, report.Verified())
+	assert.Equal(t, 1, report.Deleted)
+}
+
+func TestMigrateRejectsSharedStorage(t *testing.T) {
+	source := newTestClient(t)
+	database := source.collection.Database().Name()
+
+	same, err := source.MigrationTarget(database, "flags", "")
+	require.NoError(t, err)
+	_, err = source.Migrate(context.Background(), same)
+	assert.Error(t, err)
+
+	single, err := source.MigrationTarget(database, "flags", "flags")
+	require.NoError(t, err)
+	_, err = source.Migrate(context.Background(), single)
+	assert.Error(t, err, "one collection cannot hold both layouts")
+}
+
+func TestMigrationTargetSharesCollections(t *testing.T) {
+	source := newTestClient(t)
+	target, err := source.MigrationTarget("other", "flags_single", "flags")
+	require.NoError(t, err)
+
+	assert.Equal(t, "other", target.collection.Database().Name())
+	assert.Equal(t, "flags_single", target.collection.Name())
+	assert.Same(t, source.historyCollection, target.historyCollection)
+	assert.Same(t, source.auditCollection, target.auditCollection)
+	assert.Same(t, source.scheduleCollection, target.scheduleCollection)
+	assert.Same(t, source.contextSchemaCollection, target.contextSchemaCollection)
+}
+
+func TestMirrorToRunsFinalMigrate(t *testing.T) {
+	source := newTestClient(t)
+	target, err := source.MigrationTarget(source.collection.Database().Name(), "flags_single", "flags")
+	require.NoError(t, err)
+	require.NoError(t, source.SetFlag(context.Background(), flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	firstRun := make(chan struct{})
+	type result struct {
+		report *MigrationReport
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var once bool
+		report, err := source.MirrorTo(ctx, target, time.Hour, func(*MigrationReport) {
+			if !once {
+				once = true
+				close(firstRun)
+			}
+		})
+		done <- result{report, err}
+	}()
+
+	select {
+	case <-firstRun:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the mirror did not run")
+	}
+	// Written after the last tick, before writes were frozen.
+	require.NoError(t, source.SetFlag(context.Background(), flag.Definition{FlagName: "search", DefaultVariant: "on"}))
+	cancel()
+
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+		require.NotNil(t, res.report)
+		assert.True(t, res.report.Verified())
+		assert.Equal(t, 1, res.report.Written, "the final run copies the last writes")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the mirror did not stop")
+	}
+	_, err = target.GetFlag(context.Background(), "search")
+	assert.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -29,6 +30,56 @@ type Options struct {
 	// MaxTries is the maximum number of tries to attempt
 	// queries. If not provided, it defaults to 2.
 	MaxTries int
+	// ScheduleCollection is the name of the collection that stores
+	// scheduled flag changes. If not provided, it defaults to the
+	// flag collection name with a "_scheduled" suffix.
+	ScheduleCollection string
+	// HistoryCollection is the name of the collection that stores
+	// flag versions. If not provided, it defaults to the flag
+	// collection name with a "_history" suffix.
+	HistoryCollection string
+	// AuditCollection is the name of the collection that stores the
+	// audit log of flag mutations. If not provided, it defaults to the
+	// flag collection name with an "_audit" suffix.
+	AuditCollection string
+	// WebhookCollection is the name of the collection that stores
+	// webhook targets. If not provided, it defaults to the flag
+	// collection name with a "_webhooks" suffix.
+	WebhookCollection string
+	// DeadLetterCollection is the name of the collection that stores
+	// webhook deliveries that failed every attempt. If not provided, it
+	// defaults to the flag collection name with a
+	// "_webhook_dead_letters" suffix.
+	DeadLetterCollection string
+	// ImpressionCollection is the name of the collection that stores
+	// which variants were served to which users. If not provided, it
+	// defaults to the flag collection name with an "_impressions" suffix.
+	ImpressionCollection string
+	// TrackingCollection is the name of the collection that stores
+	// tracking events such as conversions. If not provided, it defaults
+	// to the flag collection name with a "_tracking" suffix.
+	TrackingCollection string
+	// UsageCollection is the name of the collection that stores daily
+	// evaluation counts per flag. If not provided, it defaults to the
+	// flag collection name with a "_usage" suffix.
+	UsageCollection string
+	// CodeReferenceCollection is the name of the collection that stores
+	// where flags are referenced in source code. If not provided, it
+	// defaults to the flag collection name with a "_code_references"
+	// suffix.
+	CodeReferenceCollection string
+	// ContextSchemaCollection is the name of the collection that stores
+	// the declared evaluation context keys. If not provided, it defaults
+	// to the flag collection name with a "_context_schema" suffix.
+	ContextSchemaCollection string
+	// ContextObservationCollection is the name of the collection that
+	// stores the keys and types seen in sampled evaluation contexts. If
+	// not provided, it defaults to the flag collection name with a
+	// "_context_observations" suffix.
+	ContextObservationCollection string
+	// Metrics counts the failed attempts of every operation. If not
+	// provided, it defaults to metrics.Nop.
+	Metrics metrics.Metrics
 }
 
 func NewOptions(client *mongo.Client, database, collection string) *Options {
@@ -63,6 +114,102 @@ func (opts *Options) WithMaxTries(maxTries int) *Options {
 	return opts
 }
 
+func (opts *Options) WithScheduleCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ScheduleCollection = collection
+	return opts
+}
+
+func (opts *Options) WithHistoryCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.HistoryCollection = collection
+	return opts
+}
+
+func (opts *Options) WithAuditCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.AuditCollection = collection
+	return opts
+}
+
+func (opts *Options) WithWebhookCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.WebhookCollection = collection
+	return opts
+}
+
+func (opts *Options) WithDeadLetterCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.DeadLetterCollection = collection
+	return opts
+}
+
+func (opts *Options) WithImpressionCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ImpressionCollection = collection
+	return opts
+}
+
+func (opts *Options) WithTrackingCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.TrackingCollection = collection
+	return opts
+}
+
+func (opts *Options) WithUsageCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.UsageCollection = collection
+	return opts
+}
+
+func (opts *Options) WithCodeReferenceCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.CodeReferenceCollection = collection
+	return opts
+}
+
+func (opts *Options) WithContextSchemaCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ContextSchemaCollection = collection
+	return opts
+}
+
+func (opts *Options) WithContextObservationCollection(collection string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ContextObservationCollection = collection
+	return opts
+}
+
+func (opts *Options) WithMetrics(m metrics.Metrics) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Metrics = m
+	return opts
+}
+
 func (opts *Options) Validate() error {
 	if opts == nil {
 		return mongoopenfeature.ErrNilOptions
@@ -85,5 +232,41 @@ func (opts *Options) Validate() error {
 	if opts.MaxTries <= 0 {
 		opts.MaxTries = 2
 	}
+	if opts.ScheduleCollection == "" {
+		opts.ScheduleCollection = opts.Collection + "_scheduled"
+	}
+	if opts.HistoryCollection == "" {
+		opts.HistoryCollection = opts.Collection + "_history"
+	}
+	if opts.AuditCollection == "" {
+		opts.AuditCollection = opts.Collection + "_audit"
+	}
+	if opts.WebhookCollection == "" {
+		opts.WebhookCollection = opts.Collection + "_webhooks"
+	}
+	if opts.DeadLetterCollection == "" {
+		opts.DeadLetterCollection = opts.Collection + "_webhook_dead_letters"
+	}
+	if opts.ImpressionCollection == "" {
+		opts.ImpressionCollection = opts.Collection + "_impressions"
+	}
+	if opts.TrackingCollection == "" {
+		opts.TrackingCollection = opts.Collection + "_tracking"
+	}
+	if opts.UsageCollection == "" {
+		opts.UsageCollection = opts.Collection + "_usage"
+	}
+	if opts.CodeReferenceCollection == "" {
+		opts.CodeReferenceCollection = opts.Collection + "_code_references"
+	}
+	if opts.ContextSchemaCollection == "" {
+		opts.ContextSchemaCollection = opts.Collection + "_context_schema"
+	}
+	if opts.ContextObservationCollection == "" {
+		opts.ContextObservationCollection = opts.Collection + "_context_observations"
+	}
+	if opts.Metrics == nil {
+		opts.Metrics = metrics.Nop{}
+	}
 	return nil
 }
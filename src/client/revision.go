@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ExpectedRevisionKey can be added to the updates given to PartialUpdateFlag
+// to make the update conditional on the stored revision of the flag.
+const ExpectedRevisionKey = "expected_revision"
+
+// RevisionConflictError is returned when a conditional write finds the flag at
+// a different revision than the caller expected, meaning someone else changed
+// it first. It matches mongoopenfeature.ErrRevisionConflict with errors.Is.
+type RevisionConflictError struct {
+	FlagName string
+	Expected int64
+	// Actual is the stored revision, or 0 if the flag no longer exists.
+	Actual int64
+}
+
+func (e *RevisionConflictError) Error() string {
+	if e.Actual == 0 {
+		return fmt.Sprintf("flag '%s' was deleted since revision %d", e.FlagName, e.Expected)
+	}
+	return fmt.Sprintf("flag '%s' is at revision %d, expected %d", e.FlagName, e.Actual, e.Expected)
+}
+
+func (e *RevisionConflictError) Is(target error) bool {
+	return target == mongoopenfeature.ErrRevisionConflict
+}
+
+// isRevisionConflict reports whether err should stop a retry loop because
+// retrying cannot change the outcome.
+func isRevisionConflict(err error) bool {
+	var conflict *RevisionConflictError
+	return errors.As(err, &conflict)
+}
+
+// revisionConflict builds the conflict error for a conditional write that
+// matched nothing, reading the flag to report its actual revision.
+func (c *Client) revisionConflict(ctx context.Context, flagName string, expected int64) error {
+	conflict := &RevisionConflictError{FlagName: flagName, Expected: expected}
+	if current := c.currentFlag(ctx, flagName); current != nil {
+		conflict.Actual = current.Revision
+	}
+	return conflict
+}
+
+// revisionFilter matches a stored revision equal to expected. Flags written
+// before revisions were tracked have no revision field, so they match a zero
+// expected revision.
+func revisionFilter(expected int64) any {
+	if expected == 0 {
+		return bson.M{"$in": bson.A{nil, 0}}
+	}
+	return expected
+}
+
+// parseRevision converts a revision given in a partial update, which may have
+// come from JSON or BSON, into an int64.
+func parseRevision(raw any) (int64, error) {
+	switch v := raw.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("revision %v is not a whole number", v)
+		}
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("revision must be a number, got %T", raw)
+	}
+}
+
+// literalDocument wraps every value of doc in $literal so it can be used in an
+// update pipeline without values that start with "$" being read as field paths.
+func literalDocument(doc bson.D) bson.D {
+	out := make(bson.D, len(doc))
+	for i, elem := range doc {
+		out[i] = bson.E{Key: elem.Key, Value: bson.M{"$literal": elem.Value}}
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/testutil"
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newSingleDocumentTestClient returns a client that stores every flag in one
+// document, on a fresh database.
+func newSingleDocumentTestClient(t *testing.T) *Client {
+	t.Helper()
+	mongoClient := testutil.MongoClient(t)
+	c, err := New(NewOptions(mongoClient, testutil.DatabaseName(t, mongoClient), "flags").WithDocumentID("flags"))
+	require.NoError(t, err)
+	return c
+}
+
+func TestSetFlagRevisions(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.Revision, "a new flag starts at revision 1")
+
+	require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "on"}))
+	stored, err = c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stored.Revision, "unconditional writes bump the revision")
+	assert.Equal(t, "on", stored.DefaultVariant)
+
+	err = c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off", Revision: 1})
+	var conflict *RevisionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.ErrorIs(t, err, mongoopenfeature.ErrRevisionConflict)
+	assert.Equal(t, int64(1), conflict.Expected)
+	assert.Equal(t, int64(2), conflict.Actual)
+
+	require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off", Revision: 2}))
+	stored, err = c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stored.Revision)
+	assert.Equal(t, "off", stored.DefaultVariant)
+}
+
+func TestPartialUpdateFlagWithoutStoredRevision(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	// A flag written before revisions were tracked.
+	_, err := c.collection.InsertOne(ctx, bson.M{"_id": "checkout", "defaultVariant": "off"})
+	require.NoError(t, err)
+
+	require.NoError(t, c.PartialUpdateFlag(ctx, "checkout", map[string]any{
+		"defaultVariant":    "on",
+		ExpectedRevisionKey: 0,
+	}))
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "on", stored.DefaultVariant)
+	assert.Equal(t, int64(1), stored.Revision)
+
+	err = c.PartialUpdateFlag(ctx, "checkout", map[string]any{
+		"defaultVariant":    "off",
+		ExpectedRevisionKey: 0,
+	})
+	assert.ErrorIs(t, err, mongoopenfeature.ErrRevisionConflict)
+}
+
+func TestPartialUpdateMissingFlagSingleDocument(t *testing.T) {
+	ctx := context.Background()
+	c := newSingleDocumentTestClient(t)
+	require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+
+	err := c.PartialUpdateFlag(ctx, "search", map[string]any{"defaultVariant": "on"})
+	require.Error(t, err)
+
+	exists, err := c.FlagExists(ctx, "search")
+	require.NoError(t, err)
+	assert.False(t, exists, "a missing flag is not created by a partial update")
+}
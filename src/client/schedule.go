@@ -0,0 +1,397 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ScheduledAction is the kind of write a ScheduledChange performs when it is due.
+type ScheduledAction string
+
+const (
+	// ScheduledActionSet replaces the flag with Definition (SetFlag).
+	ScheduledActionSet ScheduledAction = "set"
+	// ScheduledActionUpdate applies Updates to the flag (PartialUpdateFlag).
+	ScheduledActionUpdate ScheduledAction = "update"
+	// ScheduledActionDelete removes the flag (DeleteFlag).
+	ScheduledActionDelete ScheduledAction = "delete"
+)
+
+// ScheduledStatus is the lifecycle state of a ScheduledChange.
+type ScheduledStatus string
+
+const (
+	ScheduledStatusPending   ScheduledStatus = "pending"
+	ScheduledStatusApplying  ScheduledStatus = "applying"
+	ScheduledStatusApplied   ScheduledStatus = "applied"
+	ScheduledStatusFailed    ScheduledStatus = "failed"
+	ScheduledStatusCancelled ScheduledStatus = "cancelled"
+)
+
+// ScheduledChange is a flag write queued to run at ApplyAt.
+type ScheduledChange struct {
+	ID       bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	FlagName string          `bson:"flagName" json:"flagName"`
+	Action   ScheduledAction `bson:"action" json:"action"`
+	ApplyAt  time.Time       `bson:"applyAt" json:"applyAt"`
+
+	// Definition is the flag to write for ScheduledActionSet.
+	Definition *flag.Definition `bson:"definition,omitempty" json:"definition,omitempty"`
+	// Updates are the partial updates for ScheduledActionUpdate, using the
+	// same keys as PartialUpdateFlag.
+	Updates map[string]any `bson:"updates,omitempty" json:"updates,omitempty"`
+	// Note is a free-form description shown when listing changes.
+	Note string `bson:"note,omitempty" json:"note,omitempty"`
+
+	Status    ScheduledStatus `bson:"status" json:"status"`
+	CreatedAt time.Time       `bson:"createdAt" json:"createdAt"`
+	// CreatedBy is the actor that scheduled the change.
+	CreatedBy Actor     `bson:"createdBy" json:"createdBy"`
+	AppliedAt time.Time `bson:"appliedAt,omitempty" json:"appliedAt,omitzero"`
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+
+	// LeaseOwner and LeaseExpiresAt are set while a scheduler is applying
+	// the change. An expired lease lets another scheduler take over if the
+	// owner died mid-apply.
+	LeaseOwner     string    `bson:"leaseOwner,omitempty" json:"leaseOwner,omitempty"`
+	LeaseExpiresAt time.Time `bson:"leaseExpiresAt,omitempty" json:"leaseExpiresAt,omitzero"`
+}
+
+// Validate checks that the change carries what its action needs.
+func (s *ScheduledChange) Validate() error {
+	if s.FlagName == "" {
+		return errors.New("scheduled change is missing a flag name")
+	}
+	if s.ApplyAt.IsZero() {
+		return errors.New("scheduled change is missing an apply time")
+	}
+	switch s.Action {
+	case ScheduledActionSet:
+		if s.Definition == nil {
+			return errors.New("set action requires a definition")
+		}
+		if s.Definition.FlagName != s.FlagName {
+			return fmt.Errorf("definition flag name '%s' does not match '%s'", s.Definition.FlagName, s.FlagName)
+		}
+	case ScheduledActionUpdate:
+		if len(s.Updates) == 0 {
+			return errors.New("update action requires at least one update")
+		}
+	case ScheduledActionDelete:
+	default:
+		return fmt.Errorf("unknown scheduled action '%s'", s.Action)
+	}
+	return nil
+}
+
+// ScheduleChange queues a change to be applied by a scheduler once ApplyAt
+// has passed. The stored change, including its generated ID, is returned.
+func (c *Client) ScheduleChange(ctx context.Context, change ScheduledChange) (*ScheduledChange, error) {
+	if err := change.Validate(); err != nil {
+		return nil, fmt.Errorf("validating scheduled change: %w", err)
+	}
+	change.ID = bson.NewObjectID()
+	change.Status = ScheduledStatusPending
+	change.CreatedAt = time.Now().UTC()
+	change.CreatedBy = ActorFromContext(ctx)
+	change.ApplyAt = change.ApplyAt.UTC()
+	change.AppliedAt = time.Time{}
+	change.Error = ""
+	change.LeaseOwner = ""
+	change.LeaseExpiresAt = time.Time{}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.scheduleCollection.InsertOne(ctx, change)
+		if err == nil || mongo.IsDuplicateKeyError(err) {
+			// A duplicate key means a previous attempt landed.
+			c.recordAudit(ctx, AuditEntry{
+				Operation: OperationSchedule,
+				FlagName:  change.FlagName,
+				Detail:    fmt.Sprintf("%s scheduled for %s as %s", change.Action, change.ApplyAt.Format(time.RFC3339), change.ID.Hex()),
+			})
+			return &change, nil
+		}
+		c.logger.Error("error scheduling change, retrying", slog.Int("attempt", i+1), slog.String("flagName", change.FlagName), slog.Any("error", err))
+		c.metrics.Retry("schedule_change")
+	}
+	return nil, fmt.Errorf("scheduling change for flag %s after %d attempts: %w", change.FlagName, c.maxTries, err)
+}
+
+// ListScheduledChanges returns scheduled changes ordered by apply time. When
+// flagName is empty, changes for every flag are returned.
+func (c *Client) ListScheduledChanges(ctx context.Context, flagName string) ([]ScheduledChange, error) {
+	var err error
+	var result []ScheduledChange
+	for i := 0; i < c.maxTries; i++ {
+		result, err = c.listScheduledChanges(ctx, flagName)
+		if err == nil {
+			return result, nil
+		}
+		c.logger.Error("error listing scheduled changes, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("list_scheduled_changes")
+	}
+	return nil, fmt.Errorf("listing scheduled changes after %d attempts: %w", c.maxTries, err)
+}
+
+func (c *Client) listScheduledChanges(ctx context.Context, flagName string) ([]ScheduledChange, error) {
+	filter := bson.M{}
+	if flagName != "" {
+		filter["flagName"] = flagName
+	}
+	cursor, err := c.scheduleCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "applyAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("finding scheduled changes: %w", err)
+	}
+	changes := []ScheduledChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("decoding scheduled changes: %w", err)
+	}
+	return changes, nil
+}
+
+// CancelScheduledChange cancels a pending change. Changes that are already
+// being applied, or have finished, cannot be cancelled.
+func (c *Client) CancelScheduledChange(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("parsing scheduled change ID '%s': %w", id, err)
+	}
+	var change ScheduledChange
+	err = c.scheduleCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "status": ScheduledStatusPending},
+		bson.M{"$set": bson.M{"status": ScheduledStatusCancelled}},
+	).Decode(&change)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("scheduled change '%s' not found or no longer pending", id)
+		}
+		return fmt.Errorf("cancelling scheduled change %s: %w", id, err)
+	}
+	c.recordAudit(ctx, AuditEntry{
+		Operation: OperationCancelSchedule,
+		FlagName:  change.FlagName,
+		Detail:    fmt.Sprintf("cancelled %s scheduled for %s as %s", change.Action, change.ApplyAt.Format(time.RFC3339), id),
+	})
+	return nil
+}
+
+// errLeaseLost cancels an apply whose lease was taken over by another
+// scheduler.
+var errLeaseLost = errors.New("lease on scheduled change was lost")
+
+// ApplyDueChanges applies every change whose apply time has passed and returns
+// how many were applied. Each change is claimed with an atomic status
+// transition before it is applied, and the claim is renewed while the change
+// is applied, so concurrent schedulers do not apply the same change twice.
+// owner identifies the caller and lease bounds how long a claim is honored if
+// the caller dies before finishing.
+//
+// The flag write and the applied outcome are committed in one transaction,
+// which only commits while owner still holds the lease. If the lease was
+// taken over, for example because the scheduler lost its connection to Mongo
+// mid-apply, the write is rolled back and the new owner applies the change
+// instead, so every change is applied exactly once. Standalone servers do not
+// support transactions, so there a change whose lease is taken over
+// mid-apply can be applied twice.
+func (c *Client) ApplyDueChanges(ctx context.Context, owner string, lease time.Duration) (int, error) {
+	applied := 0
+	for {
+		change, err := c.claimDueChange(ctx, owner, lease)
+		if err != nil {
+			return applied, fmt.Errorf("claiming due change: %w", err)
+		}
+		if change == nil {
+			return applied, nil
+		}
+
+		ok, err := c.applyClaimedChange(ctx, change, owner, lease)
+		if err != nil {
+			return applied, fmt.Errorf("recording outcome of scheduled change %s: %w", change.ID.Hex(), err)
+		}
+		if ok {
+			applied++
+		}
+	}
+}
+
+// applyClaimedChange applies a change claimed by owner and records the
+// outcome. It reports whether the change was applied; a failed change is
+// recorded as failed, and a change whose lease was lost is left to its new
+// owner.
+func (c *Client) applyClaimedChange(ctx context.Context, change *ScheduledChange, owner string, lease time.Duration) (bool, error) {
+	// The write is attributed to the scheduler; the audit log's schedule
+	// entry records who asked for it.
+	applyCtx, stopRenewing := c.keepLease(WithActor(ctx, Actor{Name: owner, Source: ActorSourceScheduler}), change.ID, owner, lease)
+	applyErr := c.inTransaction(applyCtx, func(ctx context.Context) error {
+		if err := c.applyScheduledChange(ctx, change); err != nil {
+			return err
+		}
+		recorded, err := c.recordScheduledOutcome(ctx, change.ID, owner, nil)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			return errLeaseLost
+		}
+		return nil
+	})
+	stopRenewing()
+	if applyErr == nil {
+		return true, nil
+	}
+	if errors.Is(applyErr, errLeaseLost) {
+		c.logger.Warn("lost lease on scheduled change before recording its outcome", slog.String("id", change.ID.Hex()), slog.String("flagName", change.FlagName), slog.String("owner", owner))
+		return false, nil
+	}
+
+	c.logger.Error("error applying scheduled change", slog.String("id", change.ID.Hex()), slog.String("flagName", change.FlagName), slog.Any("error", applyErr))
+	recorded, err := c.recordScheduledOutcome(ctx, change.ID, owner, applyErr)
+	if err != nil {
+		return false, err
+	}
+	if !recorded {
+		c.logger.Warn("lost lease on scheduled change before recording its outcome", slog.String("id", change.ID.Hex()), slog.String("flagName", change.FlagName), slog.String("owner", owner))
+	}
+	return false, nil
+}
+
+// keepLease renews owner's lease on a change until the returned stop
+// function is called. The returned context is cancelled if the lease turns
+// out to have been taken over, so the apply stops as soon as possible.
+func (c *Client) keepLease(ctx context.Context, id bson.ObjectID, owner string, lease time.Duration) (context.Context, func()) {
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(max(lease/3, time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-leaseCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			held, err := c.renewLease(leaseCtx, id, owner, lease)
+			if err != nil {
+				c.logger.Error("error renewing lease on scheduled change", slog.String("id", id.Hex()), slog.Any("error", err))
+				continue
+			}
+			if !held {
+				cancel(errLeaseLost)
+				return
+			}
+		}
+	}()
+	return leaseCtx, func() {
+		close(done)
+		<-stopped
+		cancel(nil)
+	}
+}
+
+// renewLease extends owner's lease on a change that is still being applied,
+// and reports whether owner still held it.
+func (c *Client) renewLease(ctx context.Context, id bson.ObjectID, owner string, lease time.Duration) (bool, error) {
+	res, err := c.scheduleCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": ScheduledStatusApplying, "leaseOwner": owner},
+		bson.M{"$set": bson.M{"leaseExpiresAt": time.Now().UTC().Add(lease)}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// recordScheduledOutcome marks a change applied, or failed with applyErr, and
+// releases owner's lease. It reports false, and records nothing, if owner no
+// longer holds the lease.
+func (c *Client) recordScheduledOutcome(ctx context.Context, id bson.ObjectID, owner string, applyErr error) (bool, error) {
+	update := bson.M{
+		"status":    ScheduledStatusApplied,
+		"appliedAt": time.Now().UTC(),
+	}
+	if applyErr != nil {
+		update["status"] = ScheduledStatusFailed
+		update["error"] = applyErr.Error()
+	}
+	res, err := c.scheduleCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": ScheduledStatusApplying, "leaseOwner": owner},
+		bson.M{
+			"$set":   update,
+			"$unset": bson.M{"leaseOwner": "", "leaseExpiresAt": ""},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// claimDueChange leases the next due change to owner, or returns nil when
+// nothing is due. Changes left in the applying state by an owner whose lease
+// expired are claimable again.
+func (c *Client) claimDueChange(ctx context.Context, owner string, lease time.Duration) (*ScheduledChange, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"applyAt": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"status": ScheduledStatusPending},
+			bson.M{"status": ScheduledStatusApplying, "leaseExpiresAt": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":         ScheduledStatusApplying,
+		"leaseOwner":     owner,
+		"leaseExpiresAt": now.Add(lease),
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "applyAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var change ScheduledChange
+	err := c.scheduleCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&change)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (c *Client) applyScheduledChange(ctx context.Context, change *ScheduledChange) error {
+	switch change.Action {
+	case ScheduledActionSet:
+		if change.Definition == nil {
+			return errors.New("set action requires a definition")
+		}
+		// Scheduled writes are applied as planned, whatever the flag's
+		// revision is by the time they come due.
+		def := *change.Definition
+		def.Revision = 0
+		return c.SetFlag(ctx, def)
+	case ScheduledActionUpdate:
+		// Arrays come back from Mongo as bson.A, but PartialUpdateFlag
+		// expects a plain slice for append_rules.
+		if appendRules, ok := change.Updates["append_rules"].(bson.A); ok {
+			change.Updates["append_rules"] = []any(appendRules)
+		}
+		return c.PartialUpdateFlag(ctx, change.FlagName, change.Updates)
+	case ScheduledActionDelete:
+		return c.DeleteFlag(ctx, change.FlagName)
+	default:
+		return fmt.Errorf("unknown scheduled action '%s'", change.Action)
+	}
+}
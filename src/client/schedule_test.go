@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestScheduledChangeValidate(t *testing.T) {
+	applyAt := time.Now()
+	def := &flag.Definition{FlagName: "checkout"}
+
+	for tName, tCase := range map[string]struct {
+		change ScheduledChange
+		valid  bool
+	}{
+		"Set":               {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: ScheduledActionSet, Definition: def}, valid: true},
+		"Update":            {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: ScheduledActionUpdate, Updates: map[string]any{"defaultVariant": "on"}}, valid: true},
+		"Delete":            {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: ScheduledActionDelete}, valid: true},
+		"MissingFlagName":   {change: ScheduledChange{ApplyAt: applyAt, Action: ScheduledActionDelete}},
+		"MissingApplyAt":    {change: ScheduledChange{FlagName: "checkout", Action: ScheduledActionDelete}},
+		"SetWithoutDef":     {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: ScheduledActionSet}},
+		"SetOtherFlag":      {change: ScheduledChange{FlagName: "search", ApplyAt: applyAt, Action: ScheduledActionSet, Definition: def}},
+		"UpdateWithoutData": {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: ScheduledActionUpdate}},
+		"UnknownAction":     {change: ScheduledChange{FlagName: "checkout", ApplyAt: applyAt, Action: "rename"}},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			err := tCase.change.Validate()
+			if tCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// scheduleDelete queues the deletion of flagName at applyAt.
+func scheduleDelete(t *testing.T, c *Client, flagName string, applyAt time.Time) *ScheduledChange {
+	t.Helper()
+	change, err := c.ScheduleChange(context.Background(), ScheduledChange{
+		FlagName: flagName,
+		Action:   ScheduledActionDelete,
+		ApplyAt:  applyAt,
+	})
+	require.NoError(t, err)
+	return change
+}
+
+// scheduledChange reads a change back from the schedule collection.
+func scheduledChange(t *testing.T, c *Client, id bson.ObjectID) ScheduledChange {
+	t.Helper()
+	var change ScheduledChange
+	require.NoError(t, c.scheduleCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&change))
+	return change
+}
+
+func TestClaimDueChange(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	due := scheduleDelete(t, c, "checkout", time.Now().Add(-time.Minute))
+	scheduleDelete(t, c, "search", time.Now().Add(time.Hour))
+
+	claimed, err := c.claimDueChange(ctx, "a", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, due.ID, claimed.ID)
+	assert.Equal(t, ScheduledStatusApplying, claimed.Status)
+	assert.Equal(t, "a", claimed.LeaseOwner)
+
+	claimed, err = c.claimDueChange(ctx, "b", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "a leased change and a change that is not due are not claimable")
+
+	time.Sleep(100 * time.Millisecond)
+	claimed, err = c.claimDueChange(ctx, "b", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed, "a change whose lease expired is claimable again")
+	assert.Equal(t, "b", claimed.LeaseOwner)
+}
+
+func TestCancelScheduledChange(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	pending := scheduleDelete(t, c, "checkout", time.Now().Add(time.Hour))
+	due := scheduleDelete(t, c, "search", time.Now().Add(-time.Minute))
+
+	require.NoError(t, c.CancelScheduledChange(ctx, pending.ID.Hex()))
+	assert.Equal(t, ScheduledStatusCancelled, scheduledChange(t, c, pending.ID).Status)
+	assert.Error(t, c.CancelScheduledChange(ctx, pending.ID.Hex()), "a cancelled change cannot be cancelled again")
+
+	_, err := c.claimDueChange(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.Error(t, c.CancelScheduledChange(ctx, due.ID.Hex()), "a change being applied cannot be cancelled")
+	assert.Error(t, c.CancelScheduledChange(ctx, "not-an-id"))
+}
+
+func TestApplyDueChanges(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	def := flag.Definition{FlagName: "checkout", DefaultValue: true, DefaultVariant: "on"}
+	set, err := c.ScheduleChange(ctx, ScheduledChange{
+		FlagName:   "checkout",
+		Action:     ScheduledActionSet,
+		ApplyAt:    time.Now().Add(-time.Minute),
+		Definition: &def,
+	})
+	require.NoError(t, err)
+	failing := scheduleDelete(t, c, "missing", time.Now().Add(-time.Second))
+
+	applied, err := c.ApplyDueChanges(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "on", stored.DefaultVariant)
+
+	change := scheduledChange(t, c, set.ID)
+	assert.Equal(t, ScheduledStatusApplied, change.Status)
+	assert.Empty(t, change.LeaseOwner)
+	assert.False(t, change.AppliedAt.IsZero())
+
+	change = scheduledChange(t, c, failing.ID)
+	assert.Equal(t, ScheduledStatusFailed, change.Status)
+	assert.NotEmpty(t, change.Error)
+
+	applied, err = c.ApplyDueChanges(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, applied, "applied changes are not applied again")
+}
+
+func TestKeepLease(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	due := scheduleDelete(t, c, "checkout", time.Now().Add(-time.Minute))
+
+	lease := 60 * time.Millisecond
+	_, err := c.claimDueChange(ctx, "a", lease)
+	require.NoError(t, err)
+
+	leaseCtx, stop := c.keepLease(ctx, due.ID, "a", lease)
+	time.Sleep(3 * lease)
+	claimed, err := c.claimDueChange(ctx, "b", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "a renewed lease is not claimable")
+	assert.NoError(t, leaseCtx.Err())
+	stop()
+	assert.Error(t, leaseCtx.Err(), "stopping cancels the lease context")
+}
+
+func TestKeepLeaseLost(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	due := scheduleDelete(t, c, "checkout", time.Now().Add(-time.Minute))
+	_, err := c.claimDueChange(ctx, "a", time.Minute)
+	require.NoError(t, err)
+
+	// Another scheduler took the change over.
+	_, err = c.scheduleCollection.UpdateOne(ctx, bson.M{"_id": due.ID}, bson.M{"$set": bson.M{"leaseOwner": "b"}})
+	require.NoError(t, err)
+
+	leaseCtx, stop := c.keepLease(ctx, due.ID, "a", 30*time.Millisecond)
+	defer stop()
+	select {
+	case <-leaseCtx.Done():
+		assert.ErrorIs(t, context.Cause(leaseCtx), errLeaseLost)
+	case <-time.After(time.Second):
+		t.Fatal("expected the lease context to be cancelled")
+	}
+}
+
+func TestRecordScheduledOutcomeRequiresLease(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	due := scheduleDelete(t, c, "checkout", time.Now().Add(-time.Minute))
+
+	_, err := c.claimDueChange(ctx, "a", 50*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = c.claimDueChange(ctx, "b", time.Minute)
+	require.NoError(t, err)
+
+	recorded, err := c.recordScheduledOutcome(ctx, due.ID, "a", nil)
+	require.NoError(t, err)
+	assert.False(t, recorded, "a scheduler that lost its lease does not record an outcome")
+	assert.Equal(t, "b", scheduledChange(t, c, due.ID).LeaseOwner)
+
+	recorded, err = c.recordScheduledOutcome(ctx, due.ID, "b", nil)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, ScheduledStatusApplied, scheduledChange(t, c, due.ID).Status)
+}
+
+func TestApplyClaimedChangeLostLease(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	require.NoError(t, c.SetFlag(ctx, flag.Definition{FlagName: "checkout", DefaultVariant: "off"}))
+	update, err := c.ScheduleChange(ctx, ScheduledChange{
+		FlagName: "checkout",
+		Action:   ScheduledActionUpdate,
+		ApplyAt:  time.Now().Add(-time.Minute),
+		Updates:  map[string]any{"defaultVariant": "on"},
+	})
+	require.NoError(t, err)
+
+	claimed, err := c.claimDueChange(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	// Another scheduler took the change over while a was applying it.
+	_, err = c.scheduleCollection.UpdateOne(ctx, bson.M{"_id": update.ID}, bson.M{"$set": bson.M{"leaseOwner": "b"}})
+	require.NoError(t, err)
+
+	applied, err := c.applyClaimedChange(ctx, claimed, "a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, applied)
+
+	stored, err := c.GetFlag(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "off", stored.DefaultVariant, "the write is rolled back with the lost lease")
+	assert.Equal(t, int64(1), stored.Revision)
+	change := scheduledChange(t, c, update.ID)
+	assert.Equal(t, ScheduledStatusApplying, change.Status)
+	assert.Equal(t, "b", change.LeaseOwner, "the change is left to its new owner")
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// TrackingEvent is something a user did that experiments are measured by,
+// such as a conversion, sent through the OpenFeature Track API.
+type TrackingEvent struct {
+	ID           bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string        `bson:"name" json:"name"`
+	TargetingKey string        `bson:"targetingKey,omitempty" json:"targetingKey,omitempty"`
+	// Value is the event's numeric value, such as an order total.
+	Value      float64        `bson:"value" json:"value"`
+	Attributes map[string]any `bson:"attributes,omitempty" json:"attributes,omitempty"`
+	// Impressions are the variants the user was most recently served
+	// before the event, one per flag.
+	Impressions []LinkedImpression `bson:"impressions,omitempty" json:"impressions,omitempty"`
+	Timestamp   time.Time          `bson:"timestamp" json:"timestamp"`
+}
+
+// LinkedImpression is a variant a user was served before a tracking event.
+type LinkedImpression struct {
+	FlagName  string    `bson:"flagName" json:"flagName"`
+	Variant   string    `bson:"variant" json:"variant"`
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// AddTrackingEvents writes tracking events in bulk. Events without an ID are
+// given one, so retrying a partly written batch does not duplicate them.
+func (c *Client) AddTrackingEvents(ctx context.Context, events []TrackingEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	c.trackingIndexOnce.Do(func() {
+		_, err := c.trackingCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "name", Value: 1}, {Key: "timestamp", Value: 1}}},
+			{Keys: bson.D{{Key: "impressions.flagName", Value: 1}, {Key: "timestamp", Value: 1}}},
+		})
+		if err != nil {
+			c.logger.Error("error creating tracking event indexes", slog.Any("error", err))
+		}
+	})
+
+	documents := make([]any, len(events))
+	for i := range events {
+		if events[i].ID.IsZero() {
+			events[i].ID = bson.NewObjectID()
+		}
+		documents[i] = events[i]
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.trackingCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+		if err == nil || onlyDuplicateKeys(err) {
+			// Duplicate keys are events a previous attempt wrote.
+			return nil
+		}
+		c.logger.Error("error adding tracking events, retrying", slog.Int("attempt", i+1), slog.Int("events", len(events)), slog.Any("error", err))
+		c.metrics.Retry("add_tracking_events")
+	}
+	return fmt.Errorf("adding %d tracking events after %d attempts: %w", len(events), c.maxTries, err)
+}
+
+// RecentImpressions returns the impressions of the users since the given
+// time, oldest first.
+func (c *Client) RecentImpressions(ctx context.Context, targetingKeys []string, since time.Time) ([]Impression, error) {
+	if len(targetingKeys) == 0 {
+		return []Impression{}, nil
+	}
+	filter := bson.M{
+		"targetingKey": bson.M{"$in": targetingKeys},
+		"timestamp":    bson.M{"$gte": since},
+	}
+	findOpts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.impressionCollection.Find(ctx, filter, findOpts)
+		if err == nil {
+			impressions := []Impression{}
+			if err = cursor.All(ctx, &impressions); err == nil {
+				return impressions, nil
+			}
+		}
+		c.logger.Error("error getting recent impressions, retrying", slog.Int("attempt", i+1), slog.Int("users", len(targetingKeys)), slog.Any("error", err))
+		c.metrics.Retry("recent_impressions")
+	}
+	return nil, fmt.Errorf("getting recent impressions after %d attempts: %w", c.maxTries, err)
+}
+
+// TrackingEventNames returns the names of the tracking events linked to the
+// flag's impressions, sorted.
+func (c *Client) TrackingEventNames(ctx context.Context, flagName string) ([]string, error) {
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		result := c.trackingCollection.Distinct(ctx, "name", bson.M{"impressions.flagName": flagName})
+		if err = result.Err(); err == nil {
+			names := []string{}
+			if err = result.Decode(&names); err == nil {
+				sort.Strings(names)
+				return names, nil
+			}
+		}
+		c.logger.Error("error getting tracking event names, retrying", slog.Int("attempt", i+1), slog.String("flagName", flagName), slog.Any("error", err))
+		c.metrics.Retry("tracking_event_names")
+	}
+	return nil, fmt.Errorf("getting tracking event names for %s after %d attempts: %w", flagName, c.maxTries, err)
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// illegalOperationCode is returned by standalone servers, which do not
+// support transactions.
+const illegalOperationCode = 20
+
+// indexTimeout bounds the creation of the indexes a write relies on.
+const indexTimeout = 30 * time.Second
+
+// inTransaction runs fn in a transaction, or as part of the transaction ctx
+// already belongs to. Standalone servers do not support transactions, so there
+// fn runs without one and its writes are not atomic.
+func (c *Client) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if transactional(ctx) || c.transactionsUnsupported.Load() {
+		return fn(ctx)
+	}
+
+	session, err := c.collection.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	if !isTransactionUnsupported(err) {
+		return err
+	}
+	c.logger.Warn("transactions are not supported by this deployment, writing without them")
+	c.transactionsUnsupported.Store(true)
+	return fn(ctx)
+}
+
+// transactional reports whether ctx carries a session, which the client only
+// starts for transactions.
+func transactional(ctx context.Context) bool {
+	return mongo.SessionFromContext(ctx) != nil
+}
+
+func isTransactionUnsupported(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(illegalOperationCode)
+}
+
+// lazyIndexes creates a collection's indexes the first time they are needed.
+// A failed attempt is retried by the next caller.
+type lazyIndexes struct {
+	mu      sync.Mutex
+	created bool
+}
+
+func (l *lazyIndexes) ensure(collection *mongo.Collection, models []mongo.IndexModel) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.created {
+		return nil
+	}
+
+	// Indexes cannot be created inside a transaction, and a cancelled
+	// request must not decide whether they exist, so they are created
+	// outside the caller's context.
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("creating indexes on %s: %w", collection.Name(), err)
+	}
+	l.created = true
+	return nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// UsageCount is how many times a provider served a variant of a flag since
+// it last flushed its counts.
+type UsageCount struct {
+	FlagName      string
+	Variant       string
+	Evaluations   int64
+	LastEvaluated time.Time
+}
+
+// FlagUsage is how a flag has been evaluated, summed across providers.
+type FlagUsage struct {
+	FlagName string `json:"flagName"`
+	// Evaluations and Variants count evaluations since the time the usage
+	// was asked for.
+	Evaluations int64            `json:"evaluations"`
+	Variants    map[string]int64 `json:"variants,omitempty"`
+	// LastEvaluated is the last evaluation ever recorded.
+	LastEvaluated time.Time `json:"lastEvaluated"`
+}
+
+// usageKey identifies a usage document: the evaluations of one variant of a
+// flag on one UTC day.
+type usageKey struct {
+	FlagName string    `bson:"flagName"`
+	Variant  string    `bson:"variant"`
+	Day      time.Time `bson:"day"`
+}
+
+// AddUsage adds counts to the daily usage of each flag. Counts from many
+// providers add up, as every write increments the stored counts. An attempt
+// that failed after being partly applied is counted again when retried, so
+// counts can be slightly high after errors.
+func (c *Client) AddUsage(ctx context.Context, counts []UsageCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(counts))
+	for i, count := range counts {
+		key := usageKey{
+			FlagName: count.FlagName,
+			Variant:  count.Variant,
+			Day:      count.LastEvaluated.UTC().Truncate(24 * time.Hour),
+		}
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": key}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"evaluations": count.Evaluations},
+				"$max": bson.M{"lastEvaluated": count.LastEvaluated.UTC()},
+			}).
+			SetUpsert(true)
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.usageCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err == nil {
+			return nil
+		}
+		c.logger.Error("error adding usage, retrying", slog.Int("attempt", i+1), slog.Int("counts", len(counts)), slog.Any("error", err))
+		c.metrics.Retry("add_usage")
+	}
+	return fmt.Errorf("adding usage of %d variants after %d attempts: %w", len(counts), c.maxTries, err)
+}
+
+// FlagUsage returns the usage of every flag that was ever evaluated, keyed
+// by flag name, with evaluations counted since the given day.
+func (c *Client) FlagUsage(ctx context.Context, since time.Time) (map[string]FlagUsage, error) {
+	since = since.UTC().Truncate(24 * time.Hour)
+	var rows []struct {
+		ID struct {
+			FlagName string `bson:"flagName"`
+			Variant  string `bson:"variant"`
+		} `bson:"_id"`
+		Evaluations   int64     `bson:"evaluations"`
+		LastEvaluated time.Time `bson:"lastEvaluated"`
+	}
+	err := c.aggregate(ctx, c.usageCollection, "flag_usage", mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"flagName": "$_id.flagName", "variant": "$_id.variant"},
+			"evaluations": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{"$_id.day", since}}, "$evaluations", 0,
+			}}},
+			"lastEvaluated": bson.M{"$max": "$lastEvaluated"},
+		}}},
+	}, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]FlagUsage{}
+	for _, row := range rows {
+		u, ok := usage[row.ID.FlagName]
+		if !ok {
+			u = FlagUsage{FlagName: row.ID.FlagName, Variants: map[string]int64{}}
+		}
+		if row.Evaluations > 0 {
+			u.Evaluations += row.Evaluations
+			u.Variants[row.ID.Variant] += row.Evaluations
+		}
+		if row.LastEvaluated.After(u.LastEvaluated) {
+			u.LastEvaluated = row.LastEvaluated
+		}
+		usage[row.ID.FlagName] = u
+	}
+	return usage, nil
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Webhook is a target that is sent a signed HTTP POST whenever a flag it
+// covers changes. A webhook with no flag names and no categories covers
+// every flag.
+type Webhook struct {
+	ID   bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name string        `bson:"name" json:"name"`
+	URL  string        `bson:"url" json:"url"`
+	// Secret signs every delivery with HMAC-SHA256 so the receiver can
+	// check it came from us.
+	Secret string `bson:"secret" json:"secret,omitempty"`
+	// FlagNames and Categories select the flags the webhook covers. A
+	// flag is covered if it is named or in one of the categories.
+	FlagNames  []string `bson:"flagNames,omitempty" json:"flagNames,omitempty"`
+	Categories []string `bson:"categories,omitempty" json:"categories,omitempty"`
+	// Disabled webhooks are kept but not sent anything.
+	Disabled bool `bson:"disabled,omitempty" json:"disabled,omitempty"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	CreatedBy Actor     `bson:"createdBy" json:"createdBy"`
+}
+
+// Validate checks that the webhook can be delivered to.
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return errors.New("webhook is missing a name")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL '%s' is not an http or https URL", w.URL)
+	}
+	if w.Secret == "" {
+		return errors.New("webhook is missing a secret")
+	}
+	return nil
+}
+
+// Covers reports whether the webhook should be sent changes to the flag.
+func (w *Webhook) Covers(flagName, category string) bool {
+	if w.Disabled {
+		return false
+	}
+	if len(w.FlagNames) == 0 && len(w.Categories) == 0 {
+		return true
+	}
+	return slices.Contains(w.FlagNames, flagName) || (category != "" && slices.Contains(w.Categories, category))
+}
+
+// DeadLetter is a webhook delivery that failed every attempt.
+type DeadLetter struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID bson.ObjectID `bson:"webhookId" json:"webhookId"`
+	URL       string        `bson:"url" json:"url"`
+	FlagName  string        `bson:"flagName" json:"flagName"`
+	// Payload is the JSON body that could not be delivered.
+	Payload   string    `bson:"payload" json:"payload"`
+	Attempts  int       `bson:"attempts" json:"attempts"`
+	LastError string    `bson:"lastError" json:"lastError"`
+	FailedAt  time.Time `bson:"failedAt" json:"failedAt"`
+}
+
+// SetWebhook creates the webhook, or replaces it when it has an ID. The
+// stored webhook is returned.
+func (c *Client) SetWebhook(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, fmt.Errorf("validating webhook: %w", err)
+	}
+	if webhook.ID.IsZero() {
+		webhook.ID = bson.NewObjectID()
+		webhook.CreatedAt = time.Now().UTC()
+		webhook.CreatedBy = ActorFromContext(ctx)
+	}
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.webhookCollection.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, webhook, options.Replace().SetUpsert(true))
+		if err == nil {
+			return &webhook, nil
+		}
+		c.logger.Error("error setting webhook, retrying", slog.Int("attempt", i+1), slog.String("name", webhook.Name), slog.Any("error", err))
+		c.metrics.Retry("set_webhook")
+	}
+	return nil, fmt.Errorf("setting webhook %s after %d attempts: %w", webhook.Name, c.maxTries, err)
+}
+
+// ListWebhooks returns every webhook ordered by name.
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.webhookCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err == nil {
+			webhooks := []Webhook{}
+			if err = cursor.All(ctx, &webhooks); err == nil {
+				return webhooks, nil
+			}
+		}
+		c.logger.Error("error listing webhooks, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("list_webhooks")
+	}
+	return nil, fmt.Errorf("listing webhooks after %d attempts: %w", c.maxTries, err)
+}
+
+// DeleteWebhook removes a webhook. Its dead letters are kept.
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("parsing webhook ID '%s': %w", id, err)
+	}
+	for i := 0; i < c.maxTries; i++ {
+		var result *mongo.DeleteResult
+		result, err = c.webhookCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+		if err == nil {
+			if result.DeletedCount == 0 {
+				return fmt.Errorf("webhook '%s' not found", id)
+			}
+			return nil
+		}
+		c.logger.Error("error deleting webhook, retrying", slog.Int("attempt", i+1), slog.String("id", id), slog.Any("error", err))
+		c.metrics.Retry("delete_webhook")
+	}
+	return fmt.Errorf("deleting webhook %s after %d attempts: %w", id, c.maxTries, err)
+}
+
+// AddDeadLetter records a delivery that failed every attempt.
+func (c *Client) AddDeadLetter(ctx context.Context, deadLetter DeadLetter) error {
+	if deadLetter.ID.IsZero() {
+		deadLetter.ID = bson.NewObjectID()
+	}
+	if deadLetter.FailedAt.IsZero() {
+		deadLetter.FailedAt = time.Now().UTC()
+	}
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.deadLetterCollection.InsertOne(ctx, deadLetter)
+		if err == nil || mongo.IsDuplicateKeyError(err) {
+			// A duplicate key means a previous attempt landed.
+			return nil
+		}
+		c.logger.Error("error adding dead letter, retrying", slog.Int("attempt", i+1), slog.String("url", deadLetter.URL), slog.Any("error", err))
+		c.metrics.Retry("add_dead_letter")
+	}
+	return fmt.Errorf("adding dead letter after %d attempts: %w", c.maxTries, err)
+}
+
+// ListDeadLetters returns the most recent failed deliveries, newest first.
+// When webhookID is empty, dead letters for every webhook are returned.
+func (c *Client) ListDeadLetters(ctx context.Context, webhookID string, limit int) ([]DeadLetter, error) {
+	filter := bson.M{}
+	if webhookID != "" {
+		objectID, err := bson.ObjectIDFromHex(webhookID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing webhook ID '%s': %w", webhookID, err)
+		}
+		filter["webhookId"] = objectID
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	findOpts := options.Find().SetSort(bson.D{{Key: "failedAt", Value: -1}}).SetLimit(int64(limit))
+
+	var err error
+	for i := 0; i < c.maxTries; i++ {
+		var cursor *mongo.Cursor
+		cursor, err = c.deadLetterCollection.Find(ctx, filter, findOpts)
+		if err == nil {
+			deadLetters := []DeadLetter{}
+			if err = cursor.All(ctx, &deadLetters); err == nil {
+				return deadLetters, nil
+			}
+		}
+		c.logger.Error("error listing dead letters, retrying", slog.Int("attempt", i+1), slog.Any("error", err))
+		c.metrics.Retry("list_dead_letters")
+	}
+	return nil, fmt.Errorf("listing dead letters after %d attempts: %w", c.maxTries, err)
+}
+
+// DeleteDeadLetter removes a dead letter, for example once it has been
+// handled by hand.
+func (c *Client) DeleteDeadLetter(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("parsing dead letter ID '%s': %w", id, err)
+	}
+	for i := 0; i < c.maxTries; i++ {
+		_, err = c.deadLetterCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+		if err == nil {
+			return nil
+		}
+		c.logger.Error("error deleting dead letter, retrying", slog.Int("attempt", i+1), slog.String("id", id), slog.Any("error", err))
+		c.metrics.Retry("delete_dead_letter")
+	}
+	return fmt.Errorf("deleting dead letter %s after %d attempts: %w", id, c.maxTries, err)
+}
@@ -0,0 +1,159 @@
+// Package contextsampler observes the evaluation contexts callers send, so
+// keys missing from the context schema, or sent with another type than
+// declared, can be reported. The Sampler is an OpenFeature hook that counts
+// the keys and value types of a sample of contexts in memory and adds them
+// to MongoDB periodically, where the counts of every provider add up.
+package contextsampler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+)
+
+// writeTimeout bounds a single flush.
+const writeTimeout = 30 * time.Second
+
+var _ openfeature.Hook = (*Sampler)(nil)
+
+func New(opts *Options) (*Sampler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating options: %w", err)
+	}
+	return start(opts, opts.Client.AddContextObservations), nil
+}
+
+// start creates a sampler that flushes with write and starts it.
+func start(opts *Options, write func(context.Context, []contextschema.Observation) error) *Sampler {
+	s := &Sampler{
+		sampleRate:    opts.SampleRate,
+		flushInterval: opts.FlushInterval,
+		logger:        opts.Logger,
+		write:         write,
+		observations:  map[observationKey]*contextschema.Observation{},
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Sampler is a hook that counts the keys of sampled evaluation contexts by
+// the type of their values.
+type Sampler struct {
+	openfeature.UnimplementedHook
+
+	sampleRate    float64
+	flushInterval time.Duration
+	logger        *slog.Logger
+	write         func(context.Context, []contextschema.Observation) error
+
+	mu           sync.Mutex
+	observations map[observationKey]*contextschema.Observation
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	// done is closed once the last observations are flushed.
+	done chan struct{}
+}
+
+type observationKey struct {
+	key string
+	typ contextschema.Type
+}
+
+// Before observes the evaluation's context when it is sampled. Only the
+// top-level keys are observed, as those are what rules read.
+func (s *Sampler) Before(ctx context.Context, hookContext openfeature.HookContext, hookHints openfeature.HookHints) (*openfeature.EvaluationContext, error) {
+	if s.sampleRate < 1 && rand.Float64() >= s.sampleRate {
+		return nil, nil
+	}
+	evaluationContext := hookContext.EvaluationContext()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if targetingKey := evaluationContext.TargetingKey(); targetingKey != "" {
+		s.observe(contextschema.TargetingKey, contextschema.TypeString, now)
+	}
+	for key, value := range evaluationContext.Attributes() {
+		s.observe(key, contextschema.TypeOf(value), now)
+	}
+	return nil, nil
+}
+
+func (s *Sampler) observe(key string, typ contextschema.Type, now time.Time) {
+	k := observationKey{key: key, typ: typ}
+	observation, ok := s.observations[k]
+	if !ok {
+		observation = &contextschema.Observation{Key: key, Type: typ}
+		s.observations[k] = observation
+	}
+	observation.Count++
+	observation.LastSeen = now
+}
+
+// Close stops sampling and flushes the observations not yet written.
+func (s *Sampler) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+func (s *Sampler) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush writes the observations and starts new ones. Observations that fail
+// to write are kept for the next flush.
+func (s *Sampler) flush() {
+	s.mu.Lock()
+	pending := s.observations
+	s.observations = map[observationKey]*contextschema.Observation{}
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	observations := make([]contextschema.Observation, 0, len(pending))
+	for _, observation := range pending {
+		observations = append(observations, *observation)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	err := s.write(ctx, observations)
+	if err == nil {
+		return
+	}
+	s.logger.Error("error writing context observations, keeping them for the next flush", "observations", len(observations), "error", err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, old := range pending {
+		observation, ok := s.observations[key]
+		if !ok {
+			s.observations[key] = old
+			continue
+		}
+		observation.Count += old.Count
+		if old.LastSeen.After(observation.LastSeen) {
+			observation.LastSeen = old.LastSeen
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package contextsampler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextschema"
+)
+
+// fakeWriter records the observations a sampler writes.
+type fakeWriter struct {
+	mu           sync.Mutex
+	observations []contextschema.Observation
+	err          error
+}
+
+func (w *fakeWriter) write(ctx context.Context, observations []contextschema.Observation) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.observations = append(w.observations, observations...)
+	return nil
+}
+
+func (w *fakeWriter) written() []contextschema.Observation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	observations := append([]contextschema.Observation(nil), w.observations...)
+	sort.Slice(observations, func(i, j int) bool {
+		if observations[i].Key != observations[j].Key {
+			return observations[i].Key < observations[j].Key
+		}
+		return observations[i].Type < observations[j].Type
+	})
+	return observations
+}
+
+func newSampler(t *testing.T, opts *Options, write func(context.Context, []contextschema.Observation) error) *Sampler {
+	t.Helper()
+	opts.Client = &client.Client{}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = time.Hour
+	}
+	require.NoError(t, opts.Validate())
+	return start(opts, write)
+}
+
+func evaluate(t *testing.T, s *Sampler, evaluationContext openfeature.EvaluationContext) {
+	t.Helper()
+	hookContext := openfeature.NewHookContext("checkout", openfeature.Boolean, false, openfeature.ClientMetadata{}, openfeature.Metadata{}, evaluationContext)
+	_, err := s.Before(context.Background(), hookContext, openfeature.HookHints{})
+	require.NoError(t, err)
+}
+
+func TestSampler(t *testing.T) {
+	writer := &fakeWriter{}
+	sampler := newSampler(t, NewOptions(nil).WithSampleRate(1), writer.write)
+	evaluate(t, sampler, openfeature.NewEvaluationContext("alice", map[string]any{"plan": "pro", "age": 30}))
+	evaluate(t, sampler, openfeature.NewEvaluationContext("bob", map[string]any{"plan": "free", "age": "31"}))
+	evaluate(t, sampler, openfeature.NewTargetlessEvaluationContext(map[string]any{"signup": time.Now()}))
+	sampler.Close()
+
+	observations := writer.written()
+	type seen struct {
+		Key   string
+		Type  contextschema.Type
+		Count int64
+	}
+	got := make([]seen, len(observations))
+	for i, o := range observations {
+		got[i] = seen{o.Key, o.Type, o.Count}
+		assert.False(t, o.LastSeen.IsZero())
+	}
+	assert.Equal(t, []seen{
+		{"age", contextschema.TypeNumber, 1},
+		{"age", contextschema.TypeString, 1},
+		{"plan", contextschema.TypeString, 2},
+		{"signup", contextschema.TypeTime, 1},
+		{"targetingKey", contextschema.TypeString, 2},
+	}, got)
+}
+
+func TestSamplerSampleRate(t *testing.T) {
+	writer := &fakeWriter{}
+	sampler := newSampler(t, NewOptions(nil).WithSampleRate(0.1), writer.write)
+	for range 10000 {
+		evaluate(t, sampler, openfeature.NewTargetlessEvaluationContext(map[string]any{"plan": "pro"}))
+	}
+	sampler.Close()
+
+	observations := writer.written()
+	require.Len(t, observations, 1)
+	assert.InDelta(t, 1000, observations[0].Count, 200)
+}
+
+func TestSamplerKeepsFailedObservations(t *testing.T) {
+	writer := &fakeWriter{err: assert.AnError}
+	sampler := newSampler(t, NewOptions(nil).WithSampleRate(1), writer.write)
+	evaluationContext := openfeature.NewTargetlessEvaluationContext(map[string]any{"plan": "pro"})
+	evaluate(t, sampler, evaluationContext)
+	sampler.flush()
+	evaluate(t, sampler, evaluationContext)
+
+	writer.mu.Lock()
+	writer.err = nil
+	writer.mu.Unlock()
+	sampler.Close()
+
+	observations := writer.written()
+	require.Len(t, observations, 1)
+	assert.Equal(t, int64(2), observations[0].Count)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions(&client.Client{})
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, 0.01, opts.SampleRate)
+
+	assert.Error(t, NewOptions(&client.Client{}).WithSampleRate(2).Validate())
+}
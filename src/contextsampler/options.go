@@ -0,0 +1,83 @@
+package contextsampler
+
+import (
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client writes the observations. mongoprovider replaces it with the
+	// provider's own client.
+	Client *client.Client
+
+	// ===== Optional =====
+
+	// SampleRate is the fraction of evaluations, between 0 and 1, whose
+	// context is observed. If not provided, it defaults to 0.01.
+	SampleRate float64
+	// FlushInterval is how often the observations are added to MongoDB.
+	// If not provided, it defaults to 1 minute.
+	FlushInterval time.Duration
+	// Logger is the logger to use for the sampler.
+	Logger *slog.Logger
+}
+
+func NewOptions(client *client.Client) *Options {
+	return &Options{
+		Client: client,
+	}
+}
+
+func (opts *Options) WithSampleRate(sampleRate float64) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.SampleRate = sampleRate
+	return opts
+}
+
+func (opts *Options) WithFlushInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.FlushInterval = interval
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		return mongoopenfeature.ErrInvalidSampleRate
+	}
+
+	// Setting defaults
+	if opts.SampleRate == 0 {
+		opts.SampleRate = 0.01
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Minute
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
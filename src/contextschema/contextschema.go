@@ -0,0 +1,359 @@
+// Package contextschema describes the keys callers send in evaluation
+// contexts, and checks flag rules and sampled contexts against them so a
+// misspelt or mistyped key is caught instead of silently never matching.
+package contextschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TargetingKey is the key the OpenFeature targeting key is flattened into.
+// It is always known, as every SDK can send it.
+const TargetingKey = rule.TargetingKey
+
+// Type is the type of a context value.
+type Type string
+
+const (
+	TypeString  Type = "string"
+	TypeNumber  Type = "number"
+	TypeBoolean Type = "boolean"
+	TypeTime    Type = "time"
+	TypeList    Type = "list"
+	TypeObject  Type = "object"
+)
+
+// Types lists every type a key can be declared with.
+var Types = []Type{TypeString, TypeNumber, TypeBoolean, TypeTime, TypeList, TypeObject}
+
+var (
+	ErrMissingName = errors.New("missing key name")
+	ErrInvalidType = errors.New("invalid key type")
+)
+
+// ParseType returns the type with the given name.
+func ParseType(name string) (Type, error) {
+	for _, t := range Types {
+		if string(t) == name {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%w '%s', expected one of %s", ErrInvalidType, name, joinTypes(Types))
+}
+
+func joinTypes(types []Type) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+// TypeOf returns the type of a context value, or "" for nil and values of
+// no known type.
+func TypeOf(value any) Type {
+	switch value.(type) {
+	case nil:
+		return ""
+	case string:
+		return TypeString
+	case bool:
+		return TypeBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return TypeNumber
+	case time.Time, bson.DateTime:
+		return TypeTime
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return TypeList
+	case reflect.Map, reflect.Struct:
+		return TypeObject
+	}
+	return ""
+}
+
+// Key is a context key callers send.
+type Key struct {
+	Name        string `bson:"_id" json:"name"`
+	Type        Type   `bson:"type" json:"type"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// Validate checks that the key has a name and a known type.
+func (k Key) Validate() error {
+	if strings.TrimSpace(k.Name) == "" {
+		return ErrMissingName
+	}
+	_, err := ParseType(string(k.Type))
+	return err
+}
+
+// Schema is the set of keys callers send, sorted by name. An empty schema
+// checks nothing, so the registry is only enforced once keys are added.
+type Schema []Key
+
+// Lookup returns the key with the given name.
+func (s Schema) Lookup(name string) (Key, bool) {
+	for _, k := range s {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// known reports whether a key is declared or is the targeting key.
+func (s Schema) known(name string) (Key, bool) {
+	if name == TargetingKey {
+		if k, ok := s.Lookup(name); ok {
+			return k, true
+		}
+		return Key{Name: TargetingKey, Type: TypeString}, true
+	}
+	return s.Lookup(name)
+}
+
+// suggest returns the declared key closest to an unknown name, or "" when
+// none is close. Names that only differ in case and separators, such as
+// userId and user_id, are closest.
+func (s Schema) suggest(name string) string {
+	normalized := normalize(name)
+	best, bestDistance := "", 3
+	for _, k := range s {
+		if normalize(k.Name) == normalized {
+			return k.Name
+		}
+		if d := distance(strings.ToLower(name), strings.ToLower(k.Name)); d < bestDistance {
+			best, bestDistance = k.Name, d
+		}
+	}
+	return best
+}
+
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '.', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// distance is the Levenshtein distance between two strings.
+func distance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// ruleTypes are the types of value each rule reads. Rules missing from the
+// map compare values of any type.
+var ruleTypes = map[string]Type{
+	"exactMatchRule": TypeString,
+	"regexRule":      TypeString,
+	"prefixRule":     TypeString,
+	"suffixRule":     TypeString,
+	"containsRule":   TypeString,
+	"ipRangeRule":    TypeString,
+	"semVerRule":     TypeString,
+	"rangeRule":      TypeNumber,
+	"geoFenceRule":   TypeNumber,
+	"dateTimeRule":   TypeTime,
+	"cronRule":       TypeTime,
+}
+
+// IssueKind is what is wrong with a use of a context key.
+type IssueKind string
+
+const (
+	// IssueUnknownKey is a key that is not in the schema.
+	IssueUnknownKey IssueKind = "unknown_key"
+	// IssueTypeMismatch is a key used or sent with another type than the
+	// schema declares.
+	IssueTypeMismatch IssueKind = "type_mismatch"
+)
+
+// Issue is a use of a context key that does not match the schema, either by
+// a rule or in contexts callers sent.
+type Issue struct {
+	Kind IssueKind `json:"kind"`
+	Key  string    `json:"key"`
+	// Rule labels the rule reading the key, such as "#2 andRule ·
+	// regexRule"; Environment is set when the rule is an environment's.
+	Rule        string `json:"rule,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	// Declared is the type in the schema and Actual the type the rule
+	// reads or the context held.
+	Declared Type `json:"declared,omitempty"`
+	Actual   Type `json:"actual,omitempty"`
+	// Suggestion is the declared key closest to an unknown key.
+	Suggestion string `json:"suggestion,omitempty"`
+	// Count and LastSeen describe sampled contexts.
+	Count    int64     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen,omitzero"`
+}
+
+func (i Issue) String() string {
+	subject, verb := "callers", "send"
+	if i.Rule != "" {
+		subject, verb = "rule "+i.Rule, "reads"
+		if i.Environment != "" {
+			subject += " in " + i.Environment
+		}
+	}
+	if i.Kind == IssueTypeMismatch {
+		return fmt.Sprintf("%s %s '%s' as %s, but it is declared as %s", subject, verb, i.Key, i.Actual, i.Declared)
+	}
+	message := fmt.Sprintf("%s %s unknown key '%s'", subject, verb, i.Key)
+	if i.Suggestion != "" {
+		message += fmt.Sprintf(", did you mean '%s'?", i.Suggestion)
+	}
+	return message
+}
+
+// CheckRules returns the rules' reads of keys missing from the schema or of
+// another type than declared.
+func (s Schema) CheckRules(rules []rule.ConcreteRule) []Issue {
+	if len(s) == 0 {
+		return nil
+	}
+	var issues []Issue
+	for _, use := range rule.CollectContextKeyUses(rules) {
+		key, ok := s.known(use.Key)
+		if !ok {
+			issues = append(issues, Issue{
+				Kind:       IssueUnknownKey,
+				Key:        use.Key,
+				Rule:       use.Ref.Label,
+				Suggestion: s.suggest(use.Key),
+			})
+			continue
+		}
+		if actual, ok := ruleTypes[use.RuleType]; ok && !compatible(actual, key.Type) {
+			issues = append(issues, Issue{
+				Kind:     IssueTypeMismatch,
+				Key:      use.Key,
+				Rule:     use.Ref.Label,
+				Declared: key.Type,
+				Actual:   actual,
+			})
+		}
+	}
+	return issues
+}
+
+// CheckDefinition checks the rules of a flag and of each of its
+// environments.
+func (s Schema) CheckDefinition(def flag.Definition) []Issue {
+	issues := s.CheckRules(def.Rules)
+	environments := make([]string, 0, len(def.Environments))
+	for name := range def.Environments {
+		environments = append(environments, name)
+	}
+	sort.Strings(environments)
+	for _, name := range environments {
+		for _, issue := range s.CheckRules(def.Environments[name].Rules) {
+			issue.Environment = name
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// compatible reports whether a value of the given type can be used where
+// the expected type is. Time rules also read RFC 3339 strings and Unix epoch
+// numbers (see rule.ParseTime), so those are accepted for times.
+func compatible(expected, given Type) bool {
+	if expected == TypeTime {
+		return given == TypeTime || given == TypeString || given == TypeNumber
+	}
+	return expected == given
+}
+
+// Observation counts the contexts a key was sent in with values of one
+// type.
+type Observation struct {
+	Key      string    `json:"key"`
+	Type     Type      `json:"type"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// CheckObservations returns the observed keys missing from the schema and
+// those sent with another type than declared, most seen first.
+func (s Schema) CheckObservations(observations []Observation) []Issue {
+	if len(s) == 0 {
+		return nil
+	}
+	var issues []Issue
+	for _, o := range observations {
+		issue := Issue{Key: o.Key, Count: o.Count, LastSeen: o.LastSeen}
+		key, ok := s.known(o.Key)
+		switch {
+		case !ok:
+			issue.Kind = IssueUnknownKey
+			issue.Suggestion = s.suggest(o.Key)
+		case o.Type != "" && !compatible(key.Type, o.Type):
+			issue.Kind = IssueTypeMismatch
+			issue.Declared = key.Type
+			issue.Actual = o.Type
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	// An unknown key sent with several types is reported once.
+	issues = mergeUnknown(issues)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Count != issues[j].Count {
+			return issues[i].Count > issues[j].Count
+		}
+		return issues[i].Key < issues[j].Key
+	})
+	return issues
+}
+
+func mergeUnknown(issues []Issue) []Issue {
+	merged := issues[:0]
+	unknown := map[string]int{}
+	for _, issue := range issues {
+		if issue.Kind == IssueUnknownKey {
+			if i, ok := unknown[issue.Key]; ok {
+				merged[i].Count += issue.Count
+				if issue.LastSeen.After(merged[i].LastSeen) {
+					merged[i].LastSeen = issue.LastSeen
+				}
+				continue
+			}
+			unknown[issue.Key] = len(merged)
+		}
+		merged = append(merged, issue)
+	}
+	return merged
+}
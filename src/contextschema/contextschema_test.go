@@ -0,0 +1,130 @@
+package contextschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var schema = Schema{
+	{Name: "age", Type: TypeNumber},
+	{Name: "plan", Type: TypeString, Description: "Billing plan"},
+	{Name: "signup", Type: TypeTime},
+	{Name: "user_id", Type: TypeString},
+}
+
+func TestParseType(t *testing.T) {
+	typ, err := ParseType("number")
+	require.NoError(t, err)
+	assert.Equal(t, TypeNumber, typ)
+
+	_, err = ParseType("integer")
+	assert.ErrorIs(t, err, ErrInvalidType)
+}
+
+func TestKeyValidate(t *testing.T) {
+	assert.NoError(t, Key{Name: "plan", Type: TypeString}.Validate())
+	assert.ErrorIs(t, Key{Name: " ", Type: TypeString}.Validate(), ErrMissingName)
+	assert.ErrorIs(t, Key{Name: "plan", Type: "text"}.Validate(), ErrInvalidType)
+}
+
+func TestTypeOf(t *testing.T) {
+	assert.Equal(t, TypeString, TypeOf("a"))
+	assert.Equal(t, TypeNumber, TypeOf(3))
+	assert.Equal(t, TypeNumber, TypeOf(2.5))
+	assert.Equal(t, TypeBoolean, TypeOf(true))
+	assert.Equal(t, TypeTime, TypeOf(time.Now()))
+	assert.Equal(t, TypeTime, TypeOf(bson.NewDateTimeFromTime(time.Now())))
+	assert.Equal(t, TypeList, TypeOf([]any{"a"}))
+	assert.Equal(t, TypeObject, TypeOf(map[string]any{"a": 1}))
+	assert.Equal(t, Type(""), TypeOf(nil))
+}
+
+func TestCheckRules(t *testing.T) {
+	rules := []rule.ConcreteRule{
+		{ExactMatchRule: &rule.ExactMatchRule{Key: "userId"}},
+		{AndRule: &rule.AndRule{Rules: []rule.ConcreteRule{
+			{RangeRule: &rule.RangeRule{Key: "plan"}},
+			{DateTimeRule: &rule.DateTimeRule{Key: "signup"}},
+		}}},
+		{FractionalRule: &rule.FractionalRule{Key: "targetingKey"}},
+		{InListRule: &rule.InListRule{Key: "age"}},
+		{PrefixRule: &rule.PrefixRule{Key: "contry"}},
+		{SuffixRule: &rule.SuffixRule{Key: "zzz"}},
+	}
+
+	assert.Equal(t, []Issue{
+		{Kind: IssueUnknownKey, Key: "userId", Rule: "#1 exactMatchRule", Suggestion: "user_id"},
+		{Kind: IssueTypeMismatch, Key: "plan", Rule: "#2 andRule · rangeRule", Declared: TypeString, Actual: TypeNumber},
+		{Kind: IssueUnknownKey, Key: "contry", Rule: "#5 prefixRule"},
+		{Kind: IssueUnknownKey, Key: "zzz", Rule: "#6 suffixRule"},
+	}, schema.CheckRules(rules))
+
+	assert.Nil(t, Schema{}.CheckRules(rules), "an empty schema checks nothing")
+}
+
+func TestCheckRulesSuggestsCloseKeys(t *testing.T) {
+	issues := Schema{{Name: "country", Type: TypeString}}.CheckRules([]rule.ConcreteRule{
+		{PrefixRule: &rule.PrefixRule{Key: "contry"}},
+	})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "country", issues[0].Suggestion)
+	assert.Equal(t, "rule #1 prefixRule reads unknown key 'contry', did you mean 'country'?", issues[0].String())
+}
+
+func TestCheckTimeKeys(t *testing.T) {
+	schema := Schema{
+		{Name: "created", Type: TypeNumber},
+		{Name: "signup", Type: TypeTime},
+	}
+
+	assert.Empty(t, schema.CheckRules([]rule.ConcreteRule{
+		{DateTimeRule: &rule.DateTimeRule{Key: "created"}},
+	}), "time rules read Unix epoch numbers")
+	assert.Empty(t, schema.CheckObservations([]Observation{
+		{Key: "signup", Type: TypeString, Count: 1},
+		{Key: "signup", Type: TypeNumber, Count: 1},
+	}), "times can be sent as RFC 3339 strings or Unix epoch numbers")
+	assert.Len(t, schema.CheckRules([]rule.ConcreteRule{
+		{PrefixRule: &rule.PrefixRule{Key: "signup"}},
+	}), 1, "string rules do not read times")
+}
+
+func TestCheckDefinition(t *testing.T) {
+	def := flag.Definition{
+		FlagName: "checkout",
+		Rules:    []rule.ConcreteRule{{RegexRule: &rule.RegexRule{Key: "plan"}}},
+		Environments: map[string]flag.Environment{
+			"prod": {Rules: []rule.ConcreteRule{{RegexRule: &rule.RegexRule{Key: "age"}}}},
+		},
+	}
+
+	issues := schema.CheckDefinition(def)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "prod", issues[0].Environment)
+	assert.Equal(t, "rule #1 regexRule in prod reads 'age' as string, but it is declared as number", issues[0].String())
+}
+
+func TestCheckObservations(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	observations := []Observation{
+		{Key: "plan", Type: TypeString, Count: 100, LastSeen: now},
+		{Key: "age", Type: TypeString, Count: 5, LastSeen: now},
+		{Key: "age", Type: TypeNumber, Count: 50, LastSeen: now},
+		{Key: "userId", Type: TypeString, Count: 20, LastSeen: now.Add(-time.Hour)},
+		{Key: "userId", Type: TypeNumber, Count: 10, LastSeen: now},
+		{Key: "targetingKey", Type: TypeString, Count: 200, LastSeen: now},
+	}
+
+	issues := schema.CheckObservations(observations)
+	assert.Equal(t, []Issue{
+		{Kind: IssueUnknownKey, Key: "userId", Suggestion: "user_id", Count: 30, LastSeen: now},
+		{Kind: IssueTypeMismatch, Key: "age", Declared: TypeNumber, Actual: TypeString, Count: 5, LastSeen: now},
+	}, issues)
+	assert.Equal(t, "callers send 'age' as string, but it is declared as number", issues[1].String())
+}
@@ -10,4 +10,9 @@ var (
 	ErrMissingCache           = errors.New("missing cache")
 	ErrMissingDocumentID      = errors.New("missing document ID")
 	ErrNilDroppedEventHandler = errors.New("missing dropped event handler")
+	ErrRevisionConflict       = errors.New("flag was changed by someone else")
+	ErrMissingDirectory       = errors.New("missing directory")
+	ErrMissingURL             = errors.New("missing URL")
+	ErrInvalidSampleRate      = errors.New("sample rate must be between 0 and 1")
+	ErrContextSchema          = errors.New("flag does not match the context schema")
 )
@@ -0,0 +1,256 @@
+// Package experiment analyses the results of an experiment run with a flag:
+// how many users were exposed to each variant, how many of them converted,
+// whether the differences from the control variant are significant, and
+// whether users were split between the variants as intended.
+package experiment
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// Confidence is the confidence level of intervals and of significance.
+	Confidence = 0.95
+	// SRMThreshold is the p-value below which the split of users between
+	// variants is reported as a sample ratio mismatch. It is strict, as
+	// every experiment is checked and a mismatch invalidates the results.
+	SRMThreshold = 0.001
+
+	// z is the standard normal quantile for Confidence.
+	z = 1.959963984540054
+)
+
+// VariantCounts are the raw counts for one variant of an experiment.
+type VariantCounts struct {
+	Variant string `json:"variant"`
+	// Users is how many distinct users were served the variant.
+	Users int64 `json:"users"`
+	// Impressions is how many times the variant was served, including to
+	// users without a targeting key.
+	Impressions int64 `json:"impressions"`
+	// Converters is how many of the users sent the event after being
+	// served the variant.
+	Converters int64 `json:"converters"`
+	// Events is how many events those users sent.
+	Events int64 `json:"events"`
+	// Value is the sum of the values of those events.
+	Value float64 `json:"value"`
+}
+
+// Results is the analysis of an experiment.
+type Results struct {
+	Control  string          `json:"control"`
+	Variants []VariantResult `json:"variants"`
+	SRM      SRMCheck        `json:"srm"`
+}
+
+// VariantResult is the analysis of one variant.
+type VariantResult struct {
+	VariantCounts
+	// ConversionRate is the fraction of users who converted, with its
+	// Wilson score interval.
+	ConversionRate float64 `json:"conversionRate"`
+	Low            float64 `json:"low"`
+	High           float64 `json:"high"`
+	// ValuePerUser is the event value per user served the variant.
+	ValuePerUser float64 `json:"valuePerUser"`
+	// Comparison compares the variant with the control. It is nil for the
+	// control itself.
+	Comparison *Comparison `json:"comparison,omitempty"`
+}
+
+// Comparison compares a variant's conversion rate with the control's.
+type Comparison struct {
+	// Lift is the relative difference in conversion rate, e.g. 0.1 for
+	// 10% more conversions. It is 0 when the control had none.
+	Lift float64 `json:"lift"`
+	// PValue is from a two-sided two-proportion z-test.
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+// SRMCheck tests whether users were split between the variants in the
+// expected ratio. A mismatch usually means users were lost or duplicated
+// along the way, and the results can't be trusted.
+type SRMCheck struct {
+	ChiSquare float64 `json:"chiSquare"`
+	PValue    float64 `json:"pValue"`
+	Mismatch  bool    `json:"mismatch"`
+}
+
+// Analyze computes the results of an experiment from its counts. Variants
+// are compared with control; when it is empty or has no counts, the variant
+// named "control" is used, or else the first variant by name. weights is the
+// expected share of users per variant, in any unit; when it is empty, users
+// are expected to be split evenly.
+func Analyze(counts []VariantCounts, control string, weights map[string]float64) Results {
+	counts = append([]VariantCounts(nil), counts...)
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Variant < counts[j].Variant })
+
+	results := Results{Control: chooseControl(counts, control), Variants: make([]VariantResult, len(counts))}
+	var controlCounts VariantCounts
+	for _, c := range counts {
+		if c.Variant == results.Control {
+			controlCounts = c
+		}
+	}
+	for i, c := range counts {
+		result := VariantResult{VariantCounts: c}
+		result.ConversionRate, result.Low, result.High = rate(c.Converters, c.Users)
+		if c.Users > 0 {
+			result.ValuePerUser = c.Value / float64(c.Users)
+		}
+		if c.Variant != results.Control {
+			result.Comparison = compare(controlCounts, c)
+		}
+		results.Variants[i] = result
+	}
+	results.SRM = checkSRM(counts, weights)
+	return results
+}
+
+func chooseControl(counts []VariantCounts, control string) string {
+	for _, name := range []string{control, "control"} {
+		for _, c := range counts {
+			if name != "" && c.Variant == name {
+				return name
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return control
+	}
+	return counts[0].Variant
+}
+
+// rate returns the conversion rate and its Wilson score interval.
+func rate(converters, users int64) (p, low, high float64) {
+	if users == 0 {
+		return 0, 0, 0
+	}
+	n := float64(users)
+	p = float64(converters) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return p, math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// compare tests the variant's conversion rate against the control's.
+func compare(control, variant VariantCounts) *Comparison {
+	comparison := &Comparison{PValue: 1}
+	if control.Users == 0 || variant.Users == 0 {
+		return comparison
+	}
+	p1 := float64(control.Converters) / float64(control.Users)
+	p2 := float64(variant.Converters) / float64(variant.Users)
+	if p1 > 0 {
+		comparison.Lift = (p2 - p1) / p1
+	}
+	pooled := float64(control.Converters+variant.Converters) / float64(control.Users+variant.Users)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(control.Users) + 1/float64(variant.Users)))
+	if se == 0 {
+		return comparison
+	}
+	comparison.PValue = math.Erfc(math.Abs(p2-p1) / se / math.Sqrt2)
+	comparison.Significant = comparison.PValue < 1-Confidence
+	return comparison
+}
+
+// checkSRM runs a chi-square goodness of fit test of the users per variant
+// against the expected weights.
+func checkSRM(counts []VariantCounts, weights map[string]float64) SRMCheck {
+	var total int64
+	var totalWeight float64
+	variants := 0
+	for _, c := range counts {
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[c.Variant]
+		}
+		if weight <= 0 {
+			continue
+		}
+		total += c.Users
+		totalWeight += weight
+		variants++
+	}
+	check := SRMCheck{PValue: 1}
+	if variants < 2 || total == 0 {
+		return check
+	}
+	for _, c := range counts {
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[c.Variant]
+		}
+		if weight <= 0 {
+			continue
+		}
+		expected := float64(total) * weight / totalWeight
+		diff := float64(c.Users) - expected
+		check.ChiSquare += diff * diff / expected
+	}
+	check.PValue = chiSquareSurvival(check.ChiSquare, float64(variants-1))
+	check.Mismatch = check.PValue < SRMThreshold
+	return check
+}
+
+// chiSquareSurvival returns P(X > x) for X chi-square distributed with df
+// degrees of freedom.
+func chiSquareSurvival(x, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperGamma(df/2, x/2)
+}
+
+// upperGamma returns the regularized upper incomplete gamma function Q(a, x),
+// using its series when x is small and its continued fraction otherwise.
+func upperGamma(a, x float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Modified Lentz's method.
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return prefix * h
+}
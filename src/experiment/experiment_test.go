@@ -0,0 +1,103 @@
+package experiment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	results := Analyze([]VariantCounts{
+		{Variant: "treatment", Users: 1000, Impressions: 1500, Converters: 130, Events: 140, Value: 2600},
+		{Variant: "control", Users: 1000, Impressions: 1400, Converters: 100, Events: 110, Value: 2000},
+	}, "", nil)
+
+	assert.Equal(t, "control", results.Control)
+	require.Len(t, results.Variants, 2)
+
+	control := results.Variants[0]
+	assert.Equal(t, "control", control.Variant)
+	assert.InDelta(t, 0.1, control.ConversionRate, 1e-9)
+	assert.InDelta(t, 0.0829, control.Low, 1e-4)
+	assert.InDelta(t, 0.1202, control.High, 1e-4)
+	assert.InDelta(t, 2.0, control.ValuePerUser, 1e-9)
+	assert.Nil(t, control.Comparison)
+
+	treatment := results.Variants[1]
+	require.NotNil(t, treatment.Comparison)
+	assert.InDelta(t, 0.3, treatment.Comparison.Lift, 1e-9)
+	assert.InDelta(t, 0.0355, treatment.Comparison.PValue, 1e-4)
+	assert.True(t, treatment.Comparison.Significant)
+
+	assert.InDelta(t, 0, results.SRM.ChiSquare, 1e-9)
+	assert.False(t, results.SRM.Mismatch)
+}
+
+func TestAnalyzeNotSignificant(t *testing.T) {
+	results := Analyze([]VariantCounts{
+		{Variant: "off", Users: 200, Converters: 20},
+		{Variant: "on", Users: 200, Converters: 22},
+	}, "off", nil)
+
+	assert.Equal(t, "off", results.Control)
+	comparison := results.Variants[1].Comparison
+	require.NotNil(t, comparison)
+	assert.InDelta(t, 0.1, comparison.Lift, 1e-9)
+	assert.False(t, comparison.Significant)
+}
+
+func TestAnalyzeNoConversions(t *testing.T) {
+	results := Analyze([]VariantCounts{
+		{Variant: "a", Users: 100},
+		{Variant: "b", Users: 100},
+		{Variant: "c"},
+	}, "missing", nil)
+
+	assert.Equal(t, "a", results.Control)
+	for _, variant := range results.Variants[1:] {
+		require.NotNil(t, variant.Comparison)
+		assert.Equal(t, 0.0, variant.Comparison.Lift)
+		assert.Equal(t, 1.0, variant.Comparison.PValue)
+		assert.False(t, variant.Comparison.Significant)
+	}
+	assert.Equal(t, 0.0, results.Variants[2].ConversionRate)
+}
+
+func TestSRM(t *testing.T) {
+	t.Run("Even", func(t *testing.T) {
+		check := Analyze([]VariantCounts{{Variant: "a", Users: 5000}, {Variant: "b", Users: 5400}}, "", nil).SRM
+		assert.InDelta(t, 15.38, check.ChiSquare, 0.01)
+		assert.Less(t, check.PValue, SRMThreshold)
+		assert.True(t, check.Mismatch)
+	})
+	t.Run("Weighted", func(t *testing.T) {
+		check := Analyze([]VariantCounts{{Variant: "a", Users: 9000}, {Variant: "b", Users: 1000}}, "", map[string]float64{"a": 90, "b": 10}).SRM
+		assert.InDelta(t, 0, check.ChiSquare, 1e-9)
+		assert.False(t, check.Mismatch)
+	})
+	t.Run("UnweightedVariantsIgnored", func(t *testing.T) {
+		check := Analyze([]VariantCounts{{Variant: "a", Users: 500}, {Variant: "b", Users: 500}, {Variant: "c", Users: 9000}}, "", map[string]float64{"a": 1, "b": 1}).SRM
+		assert.False(t, check.Mismatch)
+	})
+	t.Run("SingleVariant", func(t *testing.T) {
+		check := Analyze([]VariantCounts{{Variant: "a", Users: 500}}, "", nil).SRM
+		assert.Equal(t, 1.0, check.PValue)
+		assert.False(t, check.Mismatch)
+	})
+}
+
+func TestChiSquareSurvival(t *testing.T) {
+	for _, tc := range []struct {
+		x, df, want float64
+	}{
+		{3.841459, 1, 0.05},
+		{5.991465, 2, 0.05},
+		{16.26624, 3, 0.001},
+		{1, 1, 0.3173},
+		{0.5, 4, 0.9735},
+		{0, 2, 1},
+	} {
+		assert.InDelta(t, tc.want, chiSquareSurvival(tc.x, tc.df), 1e-4, "x=%v df=%v", tc.x, tc.df)
+	}
+}
@@ -0,0 +1,149 @@
+package flag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind describes how a field differs between two definitions.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a single field-level difference between two definitions. Path uses
+// the JSON field names, e.g. "Rules[1].exactMatchRule.KeyValue".
+type Change struct {
+	Kind ChangeKind `bson:"kind" json:"kind"`
+	Path string     `bson:"path" json:"path"`
+	Old  any        `bson:"old,omitempty" json:"old,omitempty"`
+	New  any        `bson:"new,omitempty" json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, formatDiffValue(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, formatDiffValue(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+	}
+}
+
+// Diff returns the field-level changes needed to turn old into new. A nil
+// definition is treated as empty, so diffing against nil lists every field.
+// Both sides are compared in their JSON form so the result matches what the
+// editor and the MCP tools show.
+func Diff(old, new *Definition) []Change {
+	oldValue, err := toDiffValue(old)
+	if err != nil {
+		return []Change{{Kind: ChangeChanged, Path: "", Old: fmt.Sprint(old), New: fmt.Sprint(new)}}
+	}
+	newValue, err := toDiffValue(new)
+	if err != nil {
+		return []Change{{Kind: ChangeChanged, Path: "", Old: fmt.Sprint(old), New: fmt.Sprint(new)}}
+	}
+
+	var changes []Change
+	diffValues("", oldValue, newValue, &changes)
+	return changes
+}
+
+func toDiffValue(def *Definition) (any, error) {
+	if def == nil {
+		return map[string]any{}, nil
+	}
+	b, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	// The revision changes on every write, so it is bookkeeping rather
+	// than a change worth reporting.
+	delete(out, "Revision")
+	return out, nil
+}
+
+func diffValues(path string, old, new any, changes *[]Change) {
+	oldMap, oldIsMap := old.(map[string]any)
+	newMap, newIsMap := new.(map[string]any)
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys = append(keys, k)
+		}
+		for k := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			oldChild, inOld := oldMap[k]
+			newChild, inNew := newMap[k]
+			childPath := joinDiffPath(path, k)
+			switch {
+			case !inOld && newChild != nil:
+				*changes = append(*changes, Change{Kind: ChangeAdded, Path: childPath, New: newChild})
+			case !inNew && oldChild != nil:
+				*changes = append(*changes, Change{Kind: ChangeRemoved, Path: childPath, Old: oldChild})
+			default:
+				diffValues(childPath, oldChild, newChild, changes)
+			}
+		}
+		return
+	}
+
+	oldSlice, oldIsSlice := old.([]any)
+	newSlice, newIsSlice := new.([]any)
+	if oldIsSlice && newIsSlice {
+		for i := 0; i < len(oldSlice) || i < len(newSlice); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(oldSlice):
+				*changes = append(*changes, Change{Kind: ChangeAdded, Path: childPath, New: newSlice[i]})
+			case i >= len(newSlice):
+				*changes = append(*changes, Change{Kind: ChangeRemoved, Path: childPath, Old: oldSlice[i]})
+			default:
+				diffValues(childPath, oldSlice[i], newSlice[i], changes)
+			}
+		}
+		return
+	}
+
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	switch {
+	case old == nil:
+		*changes = append(*changes, Change{Kind: ChangeAdded, Path: path, New: new})
+	case new == nil:
+		*changes = append(*changes, Change{Kind: ChangeRemoved, Path: path, Old: old})
+	default:
+		*changes = append(*changes, Change{Kind: ChangeChanged, Path: path, Old: old, New: new})
+	}
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func formatDiffValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
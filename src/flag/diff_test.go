@@ -0,0 +1,85 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func TestDiff(t *testing.T) {
+	base := &Definition{
+		FlagName:       "checkout",
+		DefaultValue:   false,
+		DefaultVariant: "off",
+		Rules: []rule.ConcreteRule{
+			{ExactMatchRule: &rule.ExactMatchRule{Key: "user_id", KeyValue: "alice", VariantID: "on", ValueData: true}},
+		},
+	}
+
+	t.Run("Identical", func(t *testing.T) {
+		assert.Empty(t, Diff(base, base))
+	})
+
+	t.Run("ChangedField", func(t *testing.T) {
+		updated := *base
+		updated.DefaultVariant = "control"
+
+		changes := Diff(base, &updated)
+		assert.Equal(t, []Change{{Kind: ChangeChanged, Path: "DefaultVariant", Old: "off", New: "control"}}, changes)
+	})
+
+	t.Run("NestedRuleField", func(t *testing.T) {
+		updated := *base
+		updated.Rules = []rule.ConcreteRule{
+			{ExactMatchRule: &rule.ExactMatchRule{Key: "user_id", KeyValue: "bob", VariantID: "on", ValueData: true}},
+		}
+
+		changes := Diff(base, &updated)
+		assert.Equal(t, []Change{{Kind: ChangeChanged, Path: "Rules[0].exactMatchRule.KeyValue", Old: "alice", New: "bob"}}, changes)
+	})
+
+	t.Run("AddedAndRemovedRules", func(t *testing.T) {
+		updated := *base
+		updated.Rules = append([]rule.ConcreteRule{}, base.Rules...)
+		updated.Rules = append(updated.Rules, rule.ConcreteRule{OverrideRule: &rule.OverrideRule{ValueData: true, VariantID: "on"}})
+
+		changes := Diff(base, &updated)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, ChangeAdded, changes[0].Kind)
+		assert.Equal(t, "Rules[1]", changes[0].Path)
+
+		changes = Diff(&updated, base)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, ChangeRemoved, changes[0].Kind)
+		assert.Equal(t, "Rules[1]", changes[0].Path)
+	})
+
+	t.Run("NilDefinition", func(t *testing.T) {
+		changes := Diff(nil, base)
+		paths := make([]string, len(changes))
+		for i, c := range changes {
+			assert.Equal(t, ChangeAdded, c.Kind)
+			paths[i] = c.Path
+		}
+		assert.Contains(t, paths, "FlagName")
+		assert.Contains(t, paths, "Rules")
+
+		changes = Diff(base, nil)
+		for _, c := range changes {
+			assert.Equal(t, ChangeRemoved, c.Kind)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, `~ DefaultVariant: "off" -> "on"`, Change{Kind: ChangeChanged, Path: "DefaultVariant", Old: "off", New: "on"}.String())
+		assert.Equal(t, `+ Category: "beta"`, Change{Kind: ChangeAdded, Path: "Category", New: "beta"}.String())
+		assert.Equal(t, `- Category: "beta"`, Change{Kind: ChangeRemoved, Path: "Category", Old: "beta"}.String())
+	})
+}
+
+func TestDiffIgnoresRevision(t *testing.T) {
+	old := &Definition{FlagName: "checkout", Revision: 3}
+	new := &Definition{FlagName: "checkout", Revision: 4}
+	assert.Empty(t, Diff(old, new))
+}
@@ -13,8 +13,91 @@ type Definition struct {
 	Category       string `bson:"category,omitempty"` // UI-only grouping in the flag editor
 
 	Rules []rule.ConcreteRule `bson:"rules"`
+
+	// Environments overrides the default value, default variant and rules
+	// for specific environments, keyed by environment name. An environment
+	// without an entry uses the values above.
+	Environments map[string]Environment `bson:"environments,omitempty" json:",omitempty"`
+
+	// ManagedBy names the automation that owns the flag, such as a
+	// reconciler applying definitions from a repository. It is empty for
+	// flags created by hand.
+	ManagedBy string `bson:"managedBy,omitempty" json:",omitempty"`
+
+	// Revision is incremented by the client on every write. Writing a
+	// definition with a non-zero Revision only succeeds if the stored
+	// flag is still at that revision.
+	Revision int64 `bson:"revision"`
+}
+
+// Environment is the targeting configuration of a flag in one environment.
+type Environment struct {
+	DefaultValue   any
+	DefaultVariant string
+	Rules          []rule.ConcreteRule `bson:"rules"`
+}
+
+// Environment returns the configuration used in the named environment: its
+// override if it has one, otherwise the base definition. The empty name
+// always refers to the base definition.
+func (def *Definition) Environment(name string) Environment {
+	if env, ok := def.Environments[name]; ok && name != "" {
+		return env
+	}
+	return Environment{
+		DefaultValue:   def.DefaultValue,
+		DefaultVariant: def.DefaultVariant,
+		Rules:          def.Rules,
+	}
 }
 
+// HasEnvironment reports whether the named environment overrides the base
+// definition.
+func (def *Definition) HasEnvironment(name string) bool {
+	_, ok := def.Environments[name]
+	return ok && name != ""
+}
+
+// SetEnvironment replaces the configuration of the named environment. The
+// empty name sets the base definition.
+func (def *Definition) SetEnvironment(name string, env Environment) {
+	if name == "" {
+		def.DefaultValue = env.DefaultValue
+		def.DefaultVariant = env.DefaultVariant
+		def.Rules = env.Rules
+		return
+	}
+	environments := make(map[string]Environment, len(def.Environments)+1)
+	for k, v := range def.Environments {
+		environments[k] = v
+	}
+	environments[name] = env
+	def.Environments = environments
+}
+
+// ForEnvironment returns the definition as it is evaluated in the named
+// environment, with that environment's configuration in place of the base
+// one and no further overrides.
+func (def *Definition) ForEnvironment(name string) Definition {
+	resolved := *def
+	resolved.Environments = nil
+	resolved.SetEnvironment("", def.Environment(name))
+	return resolved
+}
+
+// Promote returns a copy of the definition with the configuration used in
+// the from environment copied to the to environment. Either name may be
+// empty to refer to the base definition.
+func (def *Definition) Promote(from, to string) Definition {
+	promoted := *def
+	promoted.SetEnvironment(to, def.Environment(from))
+	return promoted
+}
+
+// MetadataRuleIndex is the flag metadata key holding the index of the rule
+// that matched an evaluation. It is absent when the default value was used.
+const MetadataRuleIndex = "ruleIndex"
+
 // EvaluationMatch is the full outcome of evaluating a flag definition, including
 // which top-level rule won (if any).
 type EvaluationMatch struct {
@@ -60,8 +143,9 @@ func (def *Definition) EvaluateWithMatch(ctx map[string]any) EvaluationMatch {
 		return EvaluationMatch{
 			Value: currentRule.Value(),
 			Detail: openfeature.ProviderResolutionDetail{
-				Reason:  openfeature.TargetingMatchReason,
-				Variant: currentRule.Variant(),
+				Reason:       openfeature.TargetingMatchReason,
+				Variant:      currentRule.Variant(),
+				FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: currentIndex},
 			},
 			MatchedRuleIndex: currentIndex,
 		}
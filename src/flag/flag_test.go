@@ -137,6 +137,9 @@ func TestDefinition(t *testing.T) {
 		assert.Equal(t, "standard_2", match.Value)
 		assert.Equal(t, 1, match.MatchedRuleIndex)
 		assert.Equal(t, openfeature.TargetingMatchReason, match.Detail.Reason)
+		ruleIndex, err := match.Detail.FlagMetadata.GetInt(MetadataRuleIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), ruleIndex)
 	})
 
 	t.Run("EvaluateWithMatchReturnsNegativeIndexForDefault", func(t *testing.T) {
@@ -154,3 +157,59 @@ func TestDefinition(t *testing.T) {
 		assert.Equal(t, openfeature.DefaultReason, match.Detail.Reason)
 	})
 }
+
+func TestEnvironments(t *testing.T) {
+	devRule := rule.ConcreteRule{OverrideRule: &rule.OverrideRule{ValueData: "dev", VariantID: "v_dev"}}
+	def := &Definition{
+		FlagName:       "checkout",
+		DefaultValue:   "base",
+		DefaultVariant: "v_base",
+		Environments: map[string]Environment{
+			"dev": {DefaultValue: "dev_default", DefaultVariant: "v_dev_default", Rules: []rule.ConcreteRule{devRule}},
+		},
+	}
+
+	t.Run("EnvironmentFallsBackToBase", func(t *testing.T) {
+		assert.True(t, def.HasEnvironment("dev"))
+		assert.False(t, def.HasEnvironment("prod"))
+		assert.Equal(t, "base", def.Environment("prod").DefaultValue)
+		assert.Equal(t, "base", def.Environment("").DefaultValue)
+		assert.Equal(t, "dev_default", def.Environment("dev").DefaultValue)
+	})
+
+	t.Run("ForEnvironmentEvaluates", func(t *testing.T) {
+		dev := def.ForEnvironment("dev")
+		assert.Nil(t, dev.Environments)
+		val, detail := dev.Evaluate(map[string]any{})
+		assert.Equal(t, "dev", val)
+		assert.Equal(t, "v_dev", detail.Variant)
+
+		prod := def.ForEnvironment("prod")
+		val, detail = prod.Evaluate(map[string]any{})
+		assert.Equal(t, "base", val)
+		assert.Equal(t, openfeature.DefaultReason, detail.Reason)
+	})
+
+	t.Run("SetEnvironmentDoesNotMutateCopies", func(t *testing.T) {
+		copied := *def
+		copied.SetEnvironment("prod", Environment{DefaultValue: "prod"})
+		assert.True(t, copied.HasEnvironment("prod"))
+		assert.False(t, def.HasEnvironment("prod"))
+	})
+
+	t.Run("Promote", func(t *testing.T) {
+		promoted := def.Promote("dev", "staging")
+		assert.Equal(t, def.Environment("dev"), promoted.Environment("staging"))
+		assert.False(t, def.HasEnvironment("staging"))
+
+		changes := Diff(def, &promoted)
+		assert.NotEmpty(t, changes)
+		for _, c := range changes {
+			assert.Contains(t, c.Path, "Environments.staging")
+		}
+
+		base := def.Promote("dev", "")
+		assert.Equal(t, "dev_default", base.DefaultValue)
+		assert.Equal(t, []rule.ConcreteRule{devRule}, base.Rules)
+	})
+}
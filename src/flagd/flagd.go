@@ -0,0 +1,261 @@
+// Package flagd translates between flagd flag definition files and
+// flag.Definition. flagd targeting is JsonLogic; the supported subset
+// (if/else chains, boolean logic, comparisons, in, starts_with, ends_with,
+// sem_ver and fractional) maps onto the existing rule types. Flags that use
+// anything else are reported as issues rather than translated partially.
+package flagd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// SchemaURL is the JSON schema written by Encode.
+const SchemaURL = "https://flagd.dev/schema/v0/flags.json"
+
+const (
+	StateEnabled  = "ENABLED"
+	StateDisabled = "DISABLED"
+)
+
+// File is a flagd flag definition file.
+type File struct {
+	Schema string          `json:"$schema,omitempty"`
+	Flags  map[string]Flag `json:"flags"`
+	// Evaluators holds shared targeting rules referenced with {"$ref": name}.
+	Evaluators map[string]any `json:"$evaluators,omitempty"`
+}
+
+// Flag is a single flagd flag.
+type Flag struct {
+	State          string         `json:"state"`
+	Variants       map[string]any `json:"variants"`
+	DefaultVariant string         `json:"defaultVariant"`
+	Targeting      any            `json:"targeting,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+}
+
+// Issue is something that could not be translated. The flag it names was
+// left out of the result.
+type Issue struct {
+	FlagName string `json:"flagName"`
+	Message  string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.FlagName, i.Message)
+}
+
+// Decode reads a flagd file. Whole numbers are decoded as int64 so integer
+// flags keep their type.
+func Decode(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading flagd file: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var file File
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("decoding flagd file: %w", err)
+	}
+	for name, f := range file.Flags {
+		for variant, value := range f.Variants {
+			f.Variants[variant] = normalizeNumbers(value)
+		}
+		f.Targeting = normalizeNumbers(f.Targeting)
+		file.Flags[name] = f
+	}
+	for name, evaluator := range file.Evaluators {
+		file.Evaluators[name] = normalizeNumbers(evaluator)
+	}
+	return &file, nil
+}
+
+// Encode writes a flagd file as indented JSON.
+func Encode(w io.Writer, file *File) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// Import translates the flags in file into definitions, sorted by name.
+// Flags that are disabled or use targeting without an equivalent rule are
+// skipped and reported.
+func Import(file *File) ([]flag.Definition, []Issue) {
+	var definitions []flag.Definition
+	var issues []Issue
+	for _, name := range sortedKeys(file.Flags) {
+		def, err := importFlag(name, file.Flags[name], file.Evaluators)
+		if err != nil {
+			issues = append(issues, Issue{FlagName: name, Message: err.Error()})
+			continue
+		}
+		definitions = append(definitions, *def)
+	}
+	return definitions, issues
+}
+
+func importFlag(name string, f Flag, evaluators map[string]any) (*flag.Definition, error) {
+	if f.State == StateDisabled {
+		return nil, fmt.Errorf("flag is disabled")
+	}
+	if len(f.Variants) == 0 {
+		return nil, fmt.Errorf("flag has no variants")
+	}
+	defaultValue, ok := f.Variants[f.DefaultVariant]
+	if !ok {
+		return nil, fmt.Errorf("default variant '%s' is not one of the variants", f.DefaultVariant)
+	}
+
+	def := &flag.Definition{
+		FlagName:       name,
+		DefaultValue:   defaultValue,
+		DefaultVariant: f.DefaultVariant,
+	}
+	targeting, err := resolveRefs(f.Targeting, evaluators, 0)
+	if err != nil {
+		return nil, err
+	}
+	if def.Rules, err = importTargeting(targeting, f.Variants, f.DefaultVariant); err != nil {
+		return nil, fmt.Errorf("translating targeting: %w", err)
+	}
+	return def, nil
+}
+
+// Export translates definitions into a flagd file. Each variant is named
+// after the rule or default variant it comes from. Definitions whose rules
+// have no JsonLogic equivalent are skipped and reported. Environment
+// overrides are not part of the flagd format, so only the base definition
+// is exported.
+func Export(definitions []flag.Definition) (*File, []Issue) {
+	file := &File{Schema: SchemaURL, Flags: map[string]Flag{}}
+	var issues []Issue
+	sorted := make([]flag.Definition, len(definitions))
+	copy(sorted, definitions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FlagName < sorted[j].FlagName })
+
+	for _, def := range sorted {
+		f, err := exportFlag(def)
+		if err != nil {
+			issues = append(issues, Issue{FlagName: def.FlagName, Message: err.Error()})
+			continue
+		}
+		file.Flags[def.FlagName] = *f
+	}
+	return file, issues
+}
+
+func exportFlag(def flag.Definition) (*Flag, error) {
+	defaultVariant := def.DefaultVariant
+	if defaultVariant == "" {
+		defaultVariant = "default"
+	}
+	variants := map[string]any{defaultVariant: def.DefaultValue}
+	addVariant := func(name string, value any) error {
+		if existing, ok := variants[name]; ok && !jsonEqual(existing, value) {
+			return fmt.Errorf("variant '%s' has more than one value", name)
+		}
+		variants[name] = value
+		return nil
+	}
+	for _, r := range def.Rules {
+		if err := addVariant(r.Variant(), r.Value()); err != nil {
+			return nil, err
+		}
+	}
+
+	targeting, err := exportTargeting(def.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("translating rules: %w", err)
+	}
+	return &Flag{
+		State:          StateEnabled,
+		Variants:       variants,
+		DefaultVariant: defaultVariant,
+		Targeting:      targeting,
+	}, nil
+}
+
+// resolveRefs replaces {"$ref": name} with the named evaluator.
+func resolveRefs(v any, evaluators map[string]any, depth int) (any, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("$ref nesting is too deep")
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		if ref, ok := x["$ref"]; ok && len(x) == 1 {
+			name, _ := ref.(string)
+			evaluator, ok := evaluators[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown evaluator '%v'", ref)
+			}
+			return resolveRefs(evaluator, evaluators, depth+1)
+		}
+		out := make(map[string]any, len(x))
+		for k, child := range x {
+			resolved, err := resolveRefs(child, evaluators, depth)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(x))
+		for i, child := range x {
+			resolved, err := resolveRefs(child, evaluators, depth)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// normalizeNumbers converts json.Number values to int64 or float64.
+func normalizeNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, child := range x {
+			x[k] = normalizeNumbers(child)
+		}
+		return x
+	case []any:
+		for i, child := range x {
+			x[i] = normalizeNumbers(child)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	ab, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ab, bb)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,260 @@
+package flagd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+const testFile = `{
+  "$schema": "https://flagd.dev/schema/v0/flags.json",
+  "flags": {
+    "new-welcome-banner": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "off",
+      "targeting": {
+        "if": [
+          {"$ref": "internalUsers"}, "on",
+          {"and": [{"in": [{"var": "country"}, ["FR", "DE"]]}, {"sem_ver": [{"var": "version"}, ">=", "2.0.0"]}]}, "on",
+          {"ends_with": [{"var": "email"}, "@example.com"]}, "off"
+        ]
+      }
+    },
+    "max-items": {
+      "state": "ENABLED",
+      "variants": {"small": 10, "large": 100},
+      "defaultVariant": "small",
+      "targeting": {"if": [{">=": [{"var": "seats"}, 50]}, "large"]}
+    },
+    "color": {
+      "state": "ENABLED",
+      "variants": {"red": "#f00", "blue": "#00f", "grey": "#888"},
+      "defaultVariant": "grey",
+      "targeting": {
+        "if": [
+          {"!": {"starts_with": [{"var": "email"}, "test-"]}},
+          {"fractional": [{"var": "userId"}, ["red", 50], ["blue", 50]]},
+          "grey"
+        ]
+      }
+    },
+    "no-targeting": {
+      "state": "ENABLED",
+      "variants": {"on": true},
+      "defaultVariant": "on",
+      "targeting": {}
+    },
+    "disabled": {
+      "state": "DISABLED",
+      "variants": {"on": true},
+      "defaultVariant": "on"
+    },
+    "uses-regex-like-op": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "off",
+      "targeting": {"if": [{"cat": ["a", "b"]}, "on"]}
+    }
+  },
+  "$evaluators": {
+    "internalUsers": {"in": ["@corp.example", {"var": "email"}]}
+  }
+}`
+
+func importTestFile(t *testing.T) (map[string]flag.Definition, []Issue) {
+	t.Helper()
+	file, err := Decode(strings.NewReader(testFile))
+	require.NoError(t, err)
+	definitions, issues := Import(file)
+	byName := map[string]flag.Definition{}
+	for _, def := range definitions {
+		byName[def.FlagName] = def
+	}
+	return byName, issues
+}
+
+func evaluate(def flag.Definition, ctx map[string]any) (any, string) {
+	value, detail := def.Evaluate(ctx)
+	if detail.Variant == "" {
+		return value, def.DefaultVariant
+	}
+	return value, detail.Variant
+}
+
+func TestImport(t *testing.T) {
+	definitions, issues := importTestFile(t)
+
+	t.Run("Issues", func(t *testing.T) {
+		require.Len(t, issues, 2)
+		assert.Equal(t, "disabled", issues[0].FlagName)
+		assert.Equal(t, "uses-regex-like-op", issues[1].FlagName)
+		assert.Contains(t, issues[1].Message, "unsupported operator 'cat'")
+		assert.NotContains(t, definitions, "disabled")
+	})
+
+	t.Run("IfChain", func(t *testing.T) {
+		def := definitions["new-welcome-banner"]
+		assert.Equal(t, false, def.DefaultValue)
+
+		for name, tc := range map[string]struct {
+			ctx     map[string]any
+			variant string
+			value   any
+		}{
+			"Ref":           {map[string]any{"email": "a@corp.example"}, "on", true},
+			"And":           {map[string]any{"country": "FR", "version": "2.1.0"}, "on", true},
+			"AndOldVersion": {map[string]any{"country": "FR", "version": "1.9.0", "email": "x@example.com"}, "off", false},
+			"FirstWins":     {map[string]any{"email": "ops@corp.example.com"}, "on", true},
+			"NoMatch":       {map[string]any{"country": "US"}, "off", false},
+		} {
+			t.Run(name, func(t *testing.T) {
+				value, variant := evaluate(def, tc.ctx)
+				assert.Equal(t, tc.variant, variant)
+				assert.Equal(t, tc.value, value)
+			})
+		}
+	})
+
+	t.Run("Comparison", func(t *testing.T) {
+		def := definitions["max-items"]
+		value, _ := evaluate(def, map[string]any{"seats": int64(80)})
+		assert.Equal(t, int64(100), value)
+		value, _ = evaluate(def, map[string]any{"seats": int64(5)})
+		assert.Equal(t, int64(10), value)
+	})
+
+	t.Run("Fractional", func(t *testing.T) {
+		def := definitions["color"]
+		counts := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			_, variant := evaluate(def, map[string]any{"userId": i, "email": "user@example.com"})
+			counts[variant]++
+		}
+		assert.Equal(t, 1000, counts["red"]+counts["blue"])
+		assert.InDelta(t, 500, counts["red"], 100)
+
+		_, variant := evaluate(def, map[string]any{"userId": 1, "email": "test-1@example.com"})
+		assert.Equal(t, "grey", variant)
+	})
+
+	t.Run("NoTargeting", func(t *testing.T) {
+		assert.Empty(t, definitions["no-targeting"].Rules)
+	})
+}
+
+func TestExport(t *testing.T) {
+	definitions, _ := importTestFile(t)
+	var list []flag.Definition
+	for _, def := range definitions {
+		list = append(list, def)
+	}
+
+	exported, issues := Export(list)
+	require.Empty(t, issues)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, exported))
+	file, err := Decode(&buf)
+	require.NoError(t, err)
+	reimported, issues := Import(file)
+	require.Empty(t, issues)
+	require.Len(t, reimported, len(definitions))
+
+	contexts := []map[string]any{
+		{"email": "a@corp.example"},
+		{"country": "DE", "version": "3.0.0"},
+		{"email": "x@example.com"},
+		{"seats": int64(50)},
+		{"seats": int64(49)},
+		{"userId": 7, "email": "user@example.com"},
+		{"userId": 8, "email": "test-8@example.com"},
+		{},
+	}
+	for _, def := range reimported {
+		original := definitions[def.FlagName]
+		for _, ctx := range contexts {
+			wantValue, wantVariant := evaluate(original, ctx)
+			gotValue, gotVariant := evaluate(def, ctx)
+			assert.Equal(t, wantVariant, gotVariant, "%s with %v", def.FlagName, ctx)
+			assert.Equal(t, wantValue, gotValue, "%s with %v", def.FlagName, ctx)
+		}
+	}
+}
+
+func TestExportIssues(t *testing.T) {
+	_, issues := Export([]flag.Definition{
+		{
+			FlagName:     "regex",
+			DefaultValue: false,
+			Rules: []rule.ConcreteRule{
+				{RegexRule: &rule.RegexRule{Key: "email", Pattern: ".*", VariantID: "on", ValueData: true}},
+			},
+		},
+		{
+			FlagName:     "partial-rollout",
+			DefaultValue: false,
+			Rules: []rule.ConcreteRule{
+				{FractionalRule: &rule.FractionalRule{Key: "userId", Percentage: 20, VariantID: "on", ValueData: true}},
+			},
+		},
+		{
+			FlagName:       "conflicting-variants",
+			DefaultValue:   false,
+			DefaultVariant: "on",
+			Rules: []rule.ConcreteRule{
+				{ExistsRule: &rule.ExistsRule{Key: "email", VariantID: "on", ValueData: true}},
+			},
+		},
+	})
+
+	require.Len(t, issues, 3)
+	assert.Contains(t, issues[0].String(), "conflicting-variants: variant 'on' has more than one value")
+	assert.Contains(t, issues[1].String(), "only cover 20%")
+	assert.Contains(t, issues[2].String(), "regexRule has no flagd equivalent")
+}
+
+func TestExportOrder(t *testing.T) {
+	exported, issues := Export([]flag.Definition{{
+		FlagName:       "ordered",
+		DefaultValue:   "default",
+		DefaultVariant: "default",
+		Rules: []rule.ConcreteRule{
+			{PrefixRule: &rule.PrefixRule{Key: "name", Prefix: "a", VariantID: "low", ValueData: "low", Priority: 1}},
+			{OverrideRule: &rule.OverrideRule{VariantID: "forced", ValueData: "forced", Priority: 5}},
+			{SuffixRule: &rule.SuffixRule{Key: "name", Suffix: "z", VariantID: "high", ValueData: "high", Priority: 10}},
+		},
+	}})
+	require.Empty(t, issues)
+
+	targeting := exported.Flags["ordered"].Targeting
+	assert.Equal(t, map[string]any{"if": []any{
+		map[string]any{"ends_with": []any{map[string]any{"var": "name"}, "z"}}, "high",
+		"forced",
+	}}, targeting)
+}
+
+func TestExportTargetingKey(t *testing.T) {
+	exported, issues := Export([]flag.Definition{{
+		FlagName:     "rollout",
+		DefaultValue: false,
+		Rules: []rule.ConcreteRule{
+			{ExactMatchRule: &rule.ExactMatchRule{KeyValue: "admin", VariantID: "on", ValueData: true, Priority: 10}},
+			{FractionalRule: &rule.FractionalRule{Percentage: 20, VariantID: "on", ValueData: true, Priority: 2}},
+			{FractionalRule: &rule.FractionalRule{Key: rule.TargetingKey, Percentage: 100, VariantID: "off", ValueData: false, Priority: 1}},
+		},
+	}})
+	require.Empty(t, issues)
+
+	targetingKey := map[string]any{"var": "targetingKey"}
+	assert.Equal(t, map[string]any{"if": []any{
+		map[string]any{"==": []any{targetingKey, "admin"}}, "on",
+		map[string]any{"!=": []any{targetingKey, nil}},
+		map[string]any{"fractional": []any{targetingKey, []any{"on", int64(20)}, []any{"off", int64(80)}}},
+	}}, exported.Flags["rollout"].Targeting)
+}
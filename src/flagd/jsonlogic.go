@@ -0,0 +1,642 @@
+package flagd
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+// defaultFractionalKey is the context key flagd buckets on when a
+// fractional operation does not name one.
+const defaultFractionalKey = rule.TargetingKey
+
+// outcome is one way a targeting expression can resolve: to variant, when
+// every condition matches. Outcomes are listed in the order JsonLogic would
+// reach them, which becomes the rule priority.
+type outcome struct {
+	conditions []rule.ConcreteRule
+	variant    string
+}
+
+// importTargeting translates a targeting expression into rules whose
+// priorities reproduce the evaluation order of the expression.
+func importTargeting(targeting any, variants map[string]any, defaultVariant string) ([]rule.ConcreteRule, error) {
+	if targeting == nil {
+		return nil, nil
+	}
+	if m, ok := targeting.(map[string]any); ok && len(m) == 0 {
+		return nil, nil
+	}
+
+	outcomes, err := importResult(targeting, nil, defaultVariant)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]rule.ConcreteRule, 0, len(outcomes))
+	for i, o := range outcomes {
+		value, ok := variants[o.variant]
+		if !ok {
+			return nil, fmt.Errorf("targeting returns unknown variant '%s'", o.variant)
+		}
+		r, err := outcomeRule(o, value, len(outcomes)-i)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// importResult translates an expression that evaluates to a variant name.
+func importResult(expr any, guard []rule.ConcreteRule, defaultVariant string) ([]outcome, error) {
+	switch x := expr.(type) {
+	case nil:
+		return []outcome{{conditions: guard, variant: defaultVariant}}, nil
+	case string:
+		return []outcome{{conditions: guard, variant: x}}, nil
+	case bool:
+		return []outcome{{conditions: guard, variant: fmt.Sprint(x)}}, nil
+	}
+
+	op, args, err := operation(expr)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "if", "?:":
+		var outcomes []outcome
+		for i := 0; i+1 < len(args); i += 2 {
+			condition, err := importCondition(args[i])
+			if err != nil {
+				return nil, err
+			}
+			branchGuard := append(slices.Clone(guard), condition)
+			branch, err := importResult(args[i+1], branchGuard, defaultVariant)
+			if err != nil {
+				return nil, err
+			}
+			outcomes = append(outcomes, branch...)
+			if !isLiteral(args[i+1]) {
+				// A nested expression that resolves to nothing falls back
+				// to the default variant instead of the next branch.
+				outcomes = append(outcomes, outcome{conditions: branchGuard, variant: defaultVariant})
+			}
+		}
+		if len(args)%2 == 1 {
+			rest, err := importResult(args[len(args)-1], guard, defaultVariant)
+			if err != nil {
+				return nil, err
+			}
+			outcomes = append(outcomes, rest...)
+		}
+		return outcomes, nil
+	case "fractional":
+		return importFractional(args, guard)
+	default:
+		return nil, fmt.Errorf("'%s' does not resolve to a variant", op)
+	}
+}
+
+// importFractional maps the buckets of a fractional operation onto
+// fractional rules with cumulative percentages. They share a key, so each
+// context falls in the same bucket for every rule and the highest priority
+// rule whose percentage covers it wins. The bucketing hash differs from
+// flagd, so contexts may land in different buckets than they did there.
+func importFractional(args []any, guard []rule.ConcreteRule) ([]outcome, error) {
+	key := defaultFractionalKey
+	if len(args) > 0 {
+		if _, isBucket := args[0].([]any); !isBucket {
+			k, ok := varKey(args[0])
+			if !ok {
+				return nil, fmt.Errorf("fractional can only bucket on a single context key")
+			}
+			key = k
+			args = args[1:]
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("fractional has no buckets")
+	}
+
+	type bucket struct {
+		variant string
+		weight  float64
+	}
+	var buckets []bucket
+	total := 0.0
+	for _, arg := range args {
+		b, ok := arg.([]any)
+		if !ok || len(b) == 0 || len(b) > 2 {
+			return nil, fmt.Errorf("invalid fractional bucket %v", arg)
+		}
+		variant, ok := b[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("fractional bucket variant must be a string, got %v", b[0])
+		}
+		weight := 1.0
+		if len(b) == 2 {
+			w, ok := toFloat(b[1])
+			if !ok || w < 0 {
+				return nil, fmt.Errorf("invalid fractional weight %v", b[1])
+			}
+			weight = w
+		}
+		buckets = append(buckets, bucket{variant, weight})
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("fractional weights add up to zero")
+	}
+
+	outcomes := make([]outcome, 0, len(buckets))
+	cumulative := 0.0
+	for i, b := range buckets {
+		cumulative += b.weight
+		percentage := 100 * cumulative / total
+		if i == len(buckets)-1 {
+			percentage = 100
+		}
+		fractional := rule.ConcreteRule{FractionalRule: &rule.FractionalRule{Key: key, Percentage: percentage}}
+		outcomes = append(outcomes, outcome{conditions: append(slices.Clone(guard), fractional), variant: b.variant})
+	}
+	return outcomes, nil
+}
+
+// importCondition translates a JsonLogic condition into a rule.
+func importCondition(expr any) (rule.ConcreteRule, error) {
+	if b, ok := expr.(bool); ok && b {
+		return rule.ConcreteRule{OverrideRule: &rule.OverrideRule{}}, nil
+	}
+	op, args, err := operation(expr)
+	if err != nil {
+		return rule.ConcreteRule{}, err
+	}
+
+	switch op {
+	case "and", "or":
+		children := make([]rule.ConcreteRule, 0, len(args))
+		for _, arg := range args {
+			child, err := importCondition(arg)
+			if err != nil {
+				return rule.ConcreteRule{}, err
+			}
+			children = append(children, child)
+		}
+		if op == "and" {
+			return rule.ConcreteRule{AndRule: &rule.AndRule{Rules: children}}, nil
+		}
+		return rule.ConcreteRule{OrRule: &rule.OrRule{Rules: children}}, nil
+	case "!":
+		if len(args) != 1 {
+			return rule.ConcreteRule{}, fmt.Errorf("'!' takes one argument")
+		}
+		child, err := importCondition(args[0])
+		if err != nil {
+			return rule.ConcreteRule{}, err
+		}
+		return rule.ConcreteRule{NotRule: &rule.NotRule{Rule: child}}, nil
+	case "==", "===", "!=", "!==":
+		key, value, ok := varAndLiteral(args)
+		if !ok {
+			return rule.ConcreteRule{}, fmt.Errorf("'%s' must compare a context key with a literal", op)
+		}
+		var r rule.ConcreteRule
+		switch {
+		case value == nil:
+			// Comparing with null tests whether the key is set.
+			r = rule.ConcreteRule{NotRule: &rule.NotRule{Rule: rule.ConcreteRule{ExistsRule: &rule.ExistsRule{Key: key}}}}
+		case isString(value):
+			r = rule.ConcreteRule{ExactMatchRule: &rule.ExactMatchRule{Key: key, KeyValue: value.(string)}}
+		default:
+			r = rule.ConcreteRule{InListRule: &rule.InListRule{Key: key, Items: []any{value}}}
+		}
+		if op == "!=" || op == "!==" {
+			if r.NotRule != nil {
+				return r.NotRule.Rule, nil
+			}
+			return rule.ConcreteRule{NotRule: &rule.NotRule{Rule: r}}, nil
+		}
+		return r, nil
+	case "in":
+		if len(args) != 2 {
+			return rule.ConcreteRule{}, fmt.Errorf("'in' takes two arguments")
+		}
+		if key, ok := varKey(args[0]); ok {
+			if items, ok := args[1].([]any); ok {
+				return rule.ConcreteRule{InListRule: &rule.InListRule{Key: key, Items: items}}, nil
+			}
+		}
+		if substring, ok := args[0].(string); ok {
+			if key, ok := varKey(args[1]); ok {
+				return rule.ConcreteRule{ContainsRule: &rule.ContainsRule{Key: key, Substring: substring}}, nil
+			}
+		}
+		return rule.ConcreteRule{}, fmt.Errorf("'in' must test a context key against a list, or a string against a context key")
+	case "starts_with", "ends_with":
+		key, ok := varKey(at(args, 0))
+		value, isStr := at(args, 1).(string)
+		if len(args) != 2 || !ok || !isStr {
+			return rule.ConcreteRule{}, fmt.Errorf("'%s' must compare a context key with a string", op)
+		}
+		if op == "starts_with" {
+			return rule.ConcreteRule{PrefixRule: &rule.PrefixRule{Key: key, Prefix: value}}, nil
+		}
+		return rule.ConcreteRule{SuffixRule: &rule.SuffixRule{Key: key, Suffix: value}}, nil
+	case "sem_ver":
+		key, ok := varKey(at(args, 0))
+		operator, opOK := at(args, 1).(string)
+		version, versionOK := at(args, 2).(string)
+		if len(args) != 3 || !ok || !opOK || !versionOK {
+			return rule.ConcreteRule{}, fmt.Errorf("'sem_ver' must compare a context key with an operator and a version")
+		}
+		switch operator {
+		case "=", "!=", "<", "<=", ">", ">=":
+			return rule.ConcreteRule{SemVerRule: &rule.SemVerRule{Key: key, Constraint: operator + " " + version}}, nil
+		case "^", "~":
+			return rule.ConcreteRule{SemVerRule: &rule.SemVerRule{Key: key, Constraint: operator + version}}, nil
+		default:
+			return rule.ConcreteRule{}, fmt.Errorf("unsupported sem_ver operator '%s'", operator)
+		}
+	case "<", "<=", ">", ">=":
+		return importComparison(op, args)
+	default:
+		return rule.ConcreteRule{}, fmt.Errorf("unsupported operator '%s'", op)
+	}
+}
+
+// importComparison maps numeric comparisons, including the three argument
+// "between" form of < and <=, onto a range rule.
+func importComparison(op string, args []any) (rule.ConcreteRule, error) {
+	exclusive := op == "<" || op == ">"
+	r := &rule.RangeRule{Min: -math.MaxFloat64, Max: math.MaxFloat64}
+
+	if len(args) == 3 && (op == "<" || op == "<=") {
+		low, lowOK := toFloat(args[0])
+		key, keyOK := varKey(args[1])
+		high, highOK := toFloat(args[2])
+		if !lowOK || !keyOK || !highOK {
+			return rule.ConcreteRule{}, fmt.Errorf("'%s' between must be number, context key, number", op)
+		}
+		r.Key, r.Min, r.Max, r.ExclusiveMin, r.ExclusiveMax = key, low, high, exclusive, exclusive
+		return rule.ConcreteRule{RangeRule: r}, nil
+	}
+	if len(args) != 2 {
+		return rule.ConcreteRule{}, fmt.Errorf("'%s' takes two arguments", op)
+	}
+
+	// Normalise to "key op number".
+	key, keyFirst := varKey(args[0])
+	number, numberOK := toFloat(args[1])
+	if !keyFirst {
+		key, keyFirst = varKey(args[1])
+		number, numberOK = toFloat(args[0])
+		if !keyFirst {
+			return rule.ConcreteRule{}, fmt.Errorf("'%s' must compare a context key with a number", op)
+		}
+		op = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[op]
+	}
+	if !numberOK {
+		return rule.ConcreteRule{}, fmt.Errorf("'%s' must compare a context key with a number", op)
+	}
+
+	r.Key = key
+	switch op {
+	case "<", "<=":
+		r.Max, r.ExclusiveMax = number, exclusive
+	default:
+		r.Min, r.ExclusiveMin = number, exclusive
+	}
+	return rule.ConcreteRule{RangeRule: r}, nil
+}
+
+// outcomeRule builds the rule for an outcome: the single condition itself,
+// an and of several, or an override for an unconditional outcome.
+func outcomeRule(o outcome, value any, priority int) (rule.ConcreteRule, error) {
+	conditions := slices.DeleteFunc(slices.Clone(o.conditions), func(r rule.ConcreteRule) bool {
+		return r.IsOverride()
+	})
+	switch len(conditions) {
+	case 0:
+		return rule.ConcreteRule{OverrideRule: &rule.OverrideRule{VariantID: o.variant, ValueData: value, Priority: priority}}, nil
+	case 1:
+		return withOutcome(conditions[0], o.variant, value, priority)
+	default:
+		return rule.ConcreteRule{AndRule: &rule.AndRule{Rules: conditions, VariantID: o.variant, ValueData: value, Priority: priority}}, nil
+	}
+}
+
+// withOutcome returns a copy of r with its variant, value and priority set.
+// Conditions are shared between outcomes, so r itself is not modified.
+func withOutcome(r rule.ConcreteRule, variant string, value any, priority int) (rule.ConcreteRule, error) {
+	out := rule.ConcreteRule{}
+	src := reflect.ValueOf(r)
+	dst := reflect.ValueOf(&out).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if field.IsNil() {
+			continue
+		}
+		copied := reflect.New(field.Type().Elem())
+		copied.Elem().Set(field.Elem())
+		copied.Elem().FieldByName("VariantID").SetString(variant)
+		copied.Elem().FieldByName("Priority").SetInt(int64(priority))
+		if value != nil {
+			copied.Elem().FieldByName("ValueData").Set(reflect.ValueOf(value))
+		}
+		dst.Field(i).Set(copied)
+		return out, nil
+	}
+	return out, fmt.Errorf("empty rule")
+}
+
+// exportTargeting translates rules into an if/else chain in the order the
+// evaluator considers them.
+func exportTargeting(rules []rule.ConcreteRule) (any, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	// Higher priorities win; at equal priority an override beats earlier
+	// rules, otherwise the first matching rule wins.
+	ordered := slices.Clone(rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].GetPriority() != ordered[j].GetPriority() {
+			return ordered[i].GetPriority() > ordered[j].GetPriority()
+		}
+		return ordered[i].IsOverride() && !ordered[j].IsOverride()
+	})
+
+	var chain []any
+	for i := 0; i < len(ordered); i++ {
+		r := ordered[i]
+		if r.IsOverride() {
+			chain = append(chain, r.Variant())
+			break
+		}
+
+		if guard, fractional, ok := fractionalPart(r); ok {
+			end := i + 1
+			for end < len(ordered) {
+				nextGuard, next, ok := fractionalPart(ordered[end])
+				if !ok || rule.KeyOrTargetingKey(next.Key) != rule.KeyOrTargetingKey(fractional.Key) || !jsonEqual(nextGuard, guard) {
+					break
+				}
+				end++
+			}
+			condition, result, err := exportFractional(guard, ordered[i:end])
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, condition, result)
+			i = end - 1
+			continue
+		}
+
+		condition, err := exportCondition(r)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, condition, r.Variant())
+	}
+
+	if len(chain) == 1 {
+		return map[string]any{"if": []any{true, chain[0]}}, nil
+	}
+	return map[string]any{"if": chain}, nil
+}
+
+// fractionalPart splits a rule produced for a fractional bucket into its
+// guard conditions and the fractional rule.
+func fractionalPart(r rule.ConcreteRule) ([]rule.ConcreteRule, *rule.FractionalRule, bool) {
+	if r.FractionalRule != nil {
+		return nil, r.FractionalRule, true
+	}
+	if r.AndRule != nil && len(r.AndRule.Rules) > 0 {
+		last := r.AndRule.Rules[len(r.AndRule.Rules)-1]
+		if last.FractionalRule != nil {
+			return r.AndRule.Rules[:len(r.AndRule.Rules)-1], last.FractionalRule, true
+		}
+	}
+	return nil, nil, false
+}
+
+// exportFractional turns a run of fractional rules with cumulative
+// percentages back into a single fractional operation.
+func exportFractional(guard []rule.ConcreteRule, group []rule.ConcreteRule) (any, any, error) {
+	_, first, _ := fractionalPart(group[0])
+	key := rule.KeyOrTargetingKey(first.Key)
+	var buckets [][2]any
+	previous := 0.0
+	whole := true
+	for _, r := range group {
+		_, fractional, _ := fractionalPart(r)
+		weight := fractional.Percentage - previous
+		if weight < 0 {
+			return nil, nil, fmt.Errorf("fractional rules on '%s' are not in increasing order", key)
+		}
+		if weight != math.Trunc(weight) {
+			whole = false
+		}
+		buckets = append(buckets, [2]any{r.Variant(), weight})
+		previous = fractional.Percentage
+	}
+	if previous < 100 {
+		return nil, nil, fmt.Errorf("fractional rules on '%s' only cover %g%%", key, previous)
+	}
+
+	args := []any{map[string]any{"var": key}}
+	for _, b := range buckets {
+		weight := b[1].(float64)
+		if whole {
+			args = append(args, []any{b[0], int64(weight)})
+		} else {
+			// flagd weights are integers, so fractional percentages are
+			// written in hundredths.
+			args = append(args, []any{b[0], int64(math.Round(weight * 100))})
+		}
+	}
+
+	conditions := []any{map[string]any{"!=": []any{map[string]any{"var": key}, nil}}}
+	for _, g := range guard {
+		condition, err := exportCondition(g)
+		if err != nil {
+			return nil, nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	var condition any = conditions[0]
+	if len(conditions) > 1 {
+		condition = map[string]any{"and": conditions}
+	}
+	return condition, map[string]any{"fractional": args}, nil
+}
+
+var semVerConstraint = regexp.MustCompile(`^\s*(=|!=|<=|>=|<|>|\^|~)?\s*(v?\d[^\s,|]*)\s*$`)
+
+// exportCondition translates the matching part of a rule into JsonLogic.
+func exportCondition(r rule.ConcreteRule) (any, error) {
+	// The string rules below read the targeting key when their key is empty.
+	variable := func(key string) any { return map[string]any{"var": rule.KeyOrTargetingKey(key)} }
+	switch {
+	case r.ExactMatchRule != nil:
+		return map[string]any{"==": []any{variable(r.ExactMatchRule.Key), r.ExactMatchRule.KeyValue}}, nil
+	case r.InListRule != nil:
+		return map[string]any{"in": []any{variable(r.InListRule.Key), r.InListRule.Items}}, nil
+	case r.ContainsRule != nil:
+		return map[string]any{"in": []any{r.ContainsRule.Substring, variable(r.ContainsRule.Key)}}, nil
+	case r.PrefixRule != nil:
+		return map[string]any{"starts_with": []any{variable(r.PrefixRule.Key), r.PrefixRule.Prefix}}, nil
+	case r.SuffixRule != nil:
+		return map[string]any{"ends_with": []any{variable(r.SuffixRule.Key), r.SuffixRule.Suffix}}, nil
+	case r.ExistsRule != nil:
+		return map[string]any{"!=": []any{variable(r.ExistsRule.Key), nil}}, nil
+	case r.SemVerRule != nil:
+		m := semVerConstraint.FindStringSubmatch(r.SemVerRule.Constraint)
+		if m == nil {
+			return nil, fmt.Errorf("semver constraint '%s' has no sem_ver equivalent", r.SemVerRule.Constraint)
+		}
+		operator := m[1]
+		if operator == "" {
+			operator = "="
+		}
+		return map[string]any{"sem_ver": []any{variable(r.SemVerRule.Key), operator, m[2]}}, nil
+	case r.RangeRule != nil:
+		return exportRange(r.RangeRule), nil
+	case r.AndRule != nil, r.OrRule != nil:
+		op, children := "and", []rule.ConcreteRule(nil)
+		if r.AndRule != nil {
+			children = r.AndRule.Rules
+		} else {
+			op, children = "or", r.OrRule.Rules
+		}
+		args := make([]any, 0, len(children))
+		for _, child := range children {
+			condition, err := exportCondition(child)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, condition)
+		}
+		return map[string]any{op: args}, nil
+	case r.NotRule != nil:
+		condition, err := exportCondition(r.NotRule.Rule)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"!": []any{condition}}, nil
+	default:
+		return nil, fmt.Errorf("%s has no flagd equivalent", r.RuleType())
+	}
+}
+
+func exportRange(r *rule.RangeRule) any {
+	variable := map[string]any{"var": r.Key}
+	lowOp, highOp := "<=", "<="
+	if r.ExclusiveMin {
+		lowOp = "<"
+	}
+	if r.ExclusiveMax {
+		highOp = "<"
+	}
+	hasLow, hasHigh := r.Min > -math.MaxFloat64, r.Max < math.MaxFloat64
+	switch {
+	case hasLow && hasHigh && lowOp == highOp:
+		return map[string]any{lowOp: []any{r.Min, variable, r.Max}}
+	case hasLow && hasHigh:
+		return map[string]any{"and": []any{
+			map[string]any{lowOp: []any{r.Min, variable}},
+			map[string]any{highOp: []any{variable, r.Max}},
+		}}
+	case hasLow:
+		return map[string]any{lowOp: []any{r.Min, variable}}
+	case hasHigh:
+		return map[string]any{highOp: []any{variable, r.Max}}
+	default:
+		return map[string]any{"!=": []any{variable, nil}}
+	}
+}
+
+// operation splits a JsonLogic operation into its operator and arguments.
+func operation(expr any) (string, []any, error) {
+	m, ok := expr.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", nil, fmt.Errorf("unsupported expression %v", expr)
+	}
+	for op, raw := range m {
+		if args, ok := raw.([]any); ok {
+			return op, args, nil
+		}
+		return op, []any{raw}, nil
+	}
+	return "", nil, nil
+}
+
+// varKey returns the context key of a {"var": key} expression.
+func varKey(expr any) (string, bool) {
+	m, ok := expr.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	switch v := m["var"].(type) {
+	case string:
+		return v, v != ""
+	case []any:
+		if len(v) > 0 {
+			key, ok := v[0].(string)
+			return key, ok && key != ""
+		}
+	}
+	return "", false
+}
+
+// varAndLiteral returns the key and literal of a comparison written either
+// way around.
+func varAndLiteral(args []any) (string, any, bool) {
+	if len(args) != 2 {
+		return "", nil, false
+	}
+	if key, ok := varKey(args[0]); ok && isLiteral(args[1]) {
+		return key, args[1], true
+	}
+	if key, ok := varKey(args[1]); ok && isLiteral(args[0]) {
+		return key, args[0], true
+	}
+	return "", nil, false
+}
+
+func isLiteral(v any) bool {
+	switch v.(type) {
+	case nil, string, bool, int64, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	default:
+		return 0, false
+	}
+}
+
+func at(args []any, i int) any {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Package flagset is a portable, storage independent format for a set of
+// flag definitions, used to export flags from one deployment and import them
+// into another. Sets are encoded as JSON or YAML; both use the same field
+// names as the JSON form of flag.Definition and rule.ConcreteRule.
+package flagset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the format written by Encode.
+const Version = 1
+
+// Format is an encoding of a Set.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat returns the format with the given name, accepting "yml" as an
+// alias for YAML.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unknown format '%s', expected json or yaml", name)
+	}
+}
+
+// FormatFromPath picks the format from a file extension, defaulting to JSON.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// Set is a portable collection of flag definitions. Revisions are not part
+// of the format: they belong to the deployment a flag is stored in.
+type Set struct {
+	Version int               `json:"version"`
+	Flags   []flag.Definition `json:"flags"`
+}
+
+// New returns a set of the given definitions, sorted by name and with their
+// revisions cleared.
+func New(definitions []flag.Definition) *Set {
+	flags := make([]flag.Definition, len(definitions))
+	copy(flags, definitions)
+	for i := range flags {
+		flags[i].Revision = 0
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].FlagName < flags[j].FlagName })
+	return &Set{Version: Version, Flags: flags}
+}
+
+// Validate checks that every flag has a unique, non-empty name.
+func (s *Set) Validate() error {
+	if s.Version > Version {
+		return fmt.Errorf("flag set version %d is newer than the supported version %d", s.Version, Version)
+	}
+	seen := make(map[string]bool, len(s.Flags))
+	for i, def := range s.Flags {
+		if def.FlagName == "" {
+			return fmt.Errorf("flag %d is missing a name", i)
+		}
+		if seen[def.FlagName] {
+			return fmt.Errorf("flag '%s' appears more than once", def.FlagName)
+		}
+		seen[def.FlagName] = true
+	}
+	return nil
+}
+
+// Definitions returns the flags in the set keyed by name.
+func (s *Set) Definitions() map[string]flag.Definition {
+	definitions := make(map[string]flag.Definition, len(s.Flags))
+	for _, def := range s.Flags {
+		definitions[def.FlagName] = def
+	}
+	return definitions
+}
+
+// Encode writes the set to w in the given format.
+func Encode(w io.Writer, s *Set, format Format) error {
+	doc, err := toDocument(s)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unknown format '%s'", format)
+	}
+}
+
+// Decode reads a set in the given format from r and validates it.
+func Decode(r io.Reader, format Format) (*Set, error) {
+	var s Set
+	if err := decode(r, format, &s); err != nil {
+		return nil, fmt.Errorf("decoding flag set: %w", err)
+	}
+	for i := range s.Flags {
+		s.Flags[i].Revision = 0
+		normalizeNumbers(reflect.ValueOf(&s.Flags[i]).Elem())
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid flag set: %w", err)
+	}
+	return &s, nil
+}
+
+// DecodeDefinition reads a single flag definition in the given format from
+// r. Unlike Decode it keeps the revision, so a definition that was fetched,
+// edited and written back stays conditional.
+func DecodeDefinition(r io.Reader, format Format) (*flag.Definition, error) {
+	var def flag.Definition
+	if err := decode(r, format, &def); err != nil {
+		return nil, fmt.Errorf("decoding flag definition: %w", err)
+	}
+	if def.FlagName == "" {
+		return nil, errors.New("flag definition is missing a name")
+	}
+	normalizeNumbers(reflect.ValueOf(&def).Elem())
+	return &def, nil
+}
+
+// Unmarshal decodes JSON into v like json.Unmarshal, except that numbers in
+// untyped fields such as flag values become int64 when they are whole and
+// float64 otherwise, as they do in Decode.
+func Unmarshal(data []byte, v any) error {
+	if err := decode(bytes.NewReader(data), FormatJSON, v); err != nil {
+		return err
+	}
+	normalizeNumbers(reflect.ValueOf(v))
+	return nil
+}
+
+// IsSet reports whether data holds a flag set rather than a single
+// definition, by checking for a top-level "flags" field.
+func IsSet(data []byte, format Format) bool {
+	var doc map[string]any
+	if format == FormatYAML {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return false
+		}
+	} else if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, ok := doc["flags"]
+	return ok
+}
+
+// decode unmarshals r into v. YAML is converted to JSON first so both formats
+// share the JSON field names and decoding rules of the definitions. Numbers
+// in untyped fields are left as json.Number for normalizeNumbers.
+func decode(r io.Reader, format Format, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading: %w", err)
+	}
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("decoding yaml: %w", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("converting yaml: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format '%s'", format)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// toDocument converts the set to its generic JSON form without revisions.
+func toDocument(s *Set) (map[string]any, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling flag set: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshalling flag set: %w", err)
+	}
+	if doc["version"] == float64(0) {
+		doc["version"] = Version
+	}
+	flags, _ := doc["flags"].([]any)
+	for _, f := range flags {
+		if m, ok := f.(map[string]any); ok {
+			delete(m, "Revision")
+		}
+	}
+	if flags == nil {
+		doc["flags"] = []any{}
+	}
+	return doc, nil
+}
+
+// normalizeNumbers replaces the json.Number values left in untyped fields by
+// UseNumber with int64 when they are whole numbers and float64 otherwise, so
+// integer flags still evaluate as integers after a round trip.
+func normalizeNumbers(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			normalizeNumbers(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				normalizeNumbers(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			normalizeNumbers(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			normalizeNumbers(elem)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		if n, ok := v.Interface().(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				v.Set(reflect.ValueOf(i))
+			} else if f, err := n.Float64(); err == nil {
+				v.Set(reflect.ValueOf(f))
+			}
+			return
+		}
+		inner := reflect.New(v.Elem().Type()).Elem()
+		inner.Set(v.Elem())
+		normalizeNumbers(inner)
+		v.Set(inner)
+	}
+}
@@ -0,0 +1,201 @@
+package flagset
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func testDefinitions() []flag.Definition {
+	return []flag.Definition{
+		{
+			FlagName:       "max-items",
+			DefaultValue:   int64(10),
+			DefaultVariant: "default",
+			Rules: []rule.ConcreteRule{
+				{InListRule: &rule.InListRule{Key: "plan", Items: []any{"pro", "team"}, VariantID: "large", ValueData: int64(100)}},
+			},
+			Revision: 7,
+		},
+		{
+			FlagName:       "checkout",
+			DefaultValue:   false,
+			DefaultVariant: "off",
+			Category:       "payments",
+			Rules: []rule.ConcreteRule{
+				{FractionalRule: &rule.FractionalRule{Key: "user_id", Percentage: 12.5, VariantID: "on", ValueData: true}},
+			},
+			Environments: map[string]flag.Environment{
+				"prod": {DefaultValue: false, DefaultVariant: "off"},
+			},
+			Revision: 3,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	set := New(testDefinitions())
+
+	assert.Equal(t, Version, set.Version)
+	require.Len(t, set.Flags, 2)
+	assert.Equal(t, "checkout", set.Flags[0].FlagName)
+	assert.Equal(t, "max-items", set.Flags[1].FlagName)
+	for _, def := range set.Flags {
+		assert.Zero(t, def.Revision)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			set := New(testDefinitions())
+
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, set, format))
+			assert.NotContains(t, buf.String(), "Revision")
+
+			decoded, err := Decode(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, set, decoded)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("YAMLFieldNames", func(t *testing.T) {
+		input := `
+version: 1
+flags:
+  - FlagName: banner
+    DefaultValue: hello
+    DefaultVariant: default
+    Rules:
+      - exactMatchRule:
+          Key: country
+          KeyValue: FR
+          VariantID: french
+          ValueData: bonjour
+`
+		set, err := Decode(strings.NewReader(input), FormatYAML)
+		require.NoError(t, err)
+		require.Len(t, set.Flags, 1)
+		require.Len(t, set.Flags[0].Rules, 1)
+		assert.Equal(t, "bonjour", set.Flags[0].Rules[0].ExactMatchRule.ValueData)
+	})
+
+	t.Run("Numbers", func(t *testing.T) {
+		input := `{"flags": [{"FlagName": "ratio", "DefaultValue": 0.5}, {"FlagName": "limit", "DefaultValue": 3}]}`
+		set, err := Decode(strings.NewReader(input), FormatJSON)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, set.Flags[0].DefaultValue)
+		assert.Equal(t, int64(3), set.Flags[1].DefaultValue)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		input := `{"flags": [{"FlagName": "a"}, {"FlagName": "a"}]}`
+		_, err := Decode(strings.NewReader(input), FormatJSON)
+		assert.ErrorContains(t, err, "more than once")
+	})
+
+	t.Run("MissingName", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(`{"flags": [{"DefaultValue": true}]}`), FormatJSON)
+		assert.ErrorContains(t, err, "missing a name")
+	})
+
+	t.Run("NewerVersion", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(`{"version": 99, "flags": []}`), FormatJSON)
+		assert.ErrorContains(t, err, "newer")
+	})
+}
+
+func TestDecodeDefinition(t *testing.T) {
+	input := `{"FlagName": "limit", "DefaultValue": 5, "Revision": 3}`
+	def, err := DecodeDefinition(strings.NewReader(input), FormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), def.DefaultValue)
+	assert.Equal(t, int64(3), def.Revision)
+
+	assert.False(t, IsSet([]byte(input), FormatJSON))
+	assert.True(t, IsSet([]byte("flags: []\n"), FormatYAML))
+
+	_, err = DecodeDefinition(strings.NewReader(`{"DefaultValue": 5}`), FormatJSON)
+	assert.ErrorContains(t, err, "missing a name")
+}
+
+func TestUnmarshal(t *testing.T) {
+	var msg struct {
+		Flags map[string]flag.Definition `json:"flags"`
+	}
+	input := `{"flags": {"limit": {"FlagName": "limit", "DefaultValue": 5, "Rules": [{"exactMatchRule": {"Key": "plan", "KeyValue": "pro", "ValueData": 2.5}}]}}}`
+	require.NoError(t, Unmarshal([]byte(input), &msg))
+	assert.Equal(t, int64(5), msg.Flags["limit"].DefaultValue)
+	assert.Equal(t, 2.5, msg.Flags["limit"].Rules[0].Value())
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatFromPath("flags.yaml"))
+	assert.Equal(t, FormatYAML, FormatFromPath("flags.YML"))
+	assert.Equal(t, FormatJSON, FormatFromPath("flags.json"))
+	assert.Equal(t, FormatJSON, FormatFromPath("-"))
+}
+
+func TestNewPlan(t *testing.T) {
+	current := map[string]flag.Definition{
+		"checkout": {FlagName: "checkout", DefaultValue: false, DefaultVariant: "off", Revision: 4},
+		"legacy":   {FlagName: "legacy", DefaultValue: true, Revision: 9},
+		"stable":   {FlagName: "stable", DefaultValue: "x", Revision: 2},
+	}
+	incoming := New([]flag.Definition{
+		{FlagName: "checkout", DefaultValue: true, DefaultVariant: "off"},
+		{FlagName: "stable", DefaultValue: "x"},
+		{FlagName: "new-flag", DefaultValue: int64(1)},
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		plan := NewPlan(current, incoming, ModeMerge)
+
+		kinds := map[string]DiffKind{}
+		for _, diff := range plan.Flags {
+			kinds[diff.FlagName] = diff.Kind
+		}
+		assert.Equal(t, map[string]DiffKind{
+			"checkout": DiffChanged,
+			"new-flag": DiffAdded,
+			"stable":   DiffUnchanged,
+		}, kinds)
+		assert.True(t, plan.HasChanges())
+
+		checkout := plan.Flags[0]
+		assert.Equal(t, "checkout", checkout.FlagName)
+		assert.Equal(t, []flag.Change{{Kind: flag.ChangeChanged, Path: "DefaultValue", Old: false, New: true}}, checkout.Changes)
+		assert.Equal(t, int64(4), checkout.Current.Revision)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		plan := NewPlan(current, incoming, ModeReplace)
+
+		assert.Equal(t, 1, plan.Count(DiffRemoved))
+		assert.Equal(t, []string{"checkout", "legacy", "new-flag", "stable"}, flagNames(plan))
+		assert.Contains(t, plan.String(), "- legacy (removed)")
+		assert.Contains(t, plan.String(), "~ checkout (changed)\n    ~ DefaultValue: false -> true")
+		assert.Contains(t, plan.String(), "1 added, 1 changed, 1 removed, 1 unchanged")
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		plan := NewPlan(map[string]flag.Definition{"stable": current["stable"]}, New([]flag.Definition{current["stable"]}), ModeReplace)
+		assert.False(t, plan.HasChanges())
+	})
+}
+
+func flagNames(plan *Plan) []string {
+	var names []string
+	for _, diff := range plan.Flags {
+		names = append(names, diff.FlagName)
+	}
+	return names
+}
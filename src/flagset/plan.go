@@ -0,0 +1,149 @@
+package flagset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+// Mode controls what happens to stored flags that are not in an imported set.
+type Mode string
+
+const (
+	// ModeMerge adds and updates the flags in the set and leaves every other
+	// stored flag alone.
+	ModeMerge Mode = "merge"
+	// ModeReplace makes the stored flags match the set exactly, removing
+	// flags that are not in it.
+	ModeReplace Mode = "replace"
+)
+
+// ParseMode returns the mode with the given name.
+func ParseMode(name string) (Mode, error) {
+	switch Mode(strings.ToLower(name)) {
+	case ModeMerge:
+		return ModeMerge, nil
+	case ModeReplace:
+		return ModeReplace, nil
+	default:
+		return "", fmt.Errorf("unknown import mode '%s', expected merge or replace", name)
+	}
+}
+
+// DiffKind is what an import does to a single flag.
+type DiffKind string
+
+const (
+	DiffAdded     DiffKind = "added"
+	DiffChanged   DiffKind = "changed"
+	DiffRemoved   DiffKind = "removed"
+	DiffUnchanged DiffKind = "unchanged"
+)
+
+// FlagDiff is the effect of an import on one flag.
+type FlagDiff struct {
+	FlagName string   `json:"flagName"`
+	Kind     DiffKind `json:"kind"`
+	// Changes lists the field-level changes for added and changed flags.
+	Changes []flag.Change `json:"changes,omitempty"`
+
+	// Current is the stored flag, nil for added flags. Its revision is
+	// used to make the import conditional on nothing having changed since
+	// the plan was made.
+	Current *flag.Definition `json:"-"`
+	// Incoming is the definition from the set, nil for removed flags.
+	Incoming *flag.Definition `json:"-"`
+}
+
+// Plan is the per-flag outcome of importing a set, sorted by flag name.
+type Plan struct {
+	Mode  Mode       `json:"mode"`
+	Flags []FlagDiff `json:"flags"`
+}
+
+// NewPlan compares the stored flags with an incoming set and returns what an
+// import in the given mode would do.
+func NewPlan(current map[string]flag.Definition, incoming *Set, mode Mode) *Plan {
+	plan := &Plan{Mode: mode}
+	for _, def := range incoming.Flags {
+		def := def
+		existing, ok := current[def.FlagName]
+		if !ok {
+			plan.Flags = append(plan.Flags, FlagDiff{
+				FlagName: def.FlagName,
+				Kind:     DiffAdded,
+				Changes:  flag.Diff(nil, &def),
+				Incoming: &def,
+			})
+			continue
+		}
+		changes := flag.Diff(&existing, &def)
+		kind := DiffChanged
+		if len(changes) == 0 {
+			kind = DiffUnchanged
+		}
+		plan.Flags = append(plan.Flags, FlagDiff{
+			FlagName: def.FlagName,
+			Kind:     kind,
+			Changes:  changes,
+			Current:  &existing,
+			Incoming: &def,
+		})
+	}
+
+	if mode == ModeReplace {
+		incomingNames := incoming.Definitions()
+		for name, def := range current {
+			if _, ok := incomingNames[name]; ok {
+				continue
+			}
+			def := def
+			plan.Flags = append(plan.Flags, FlagDiff{FlagName: name, Kind: DiffRemoved, Current: &def})
+		}
+	}
+
+	sort.Slice(plan.Flags, func(i, j int) bool { return plan.Flags[i].FlagName < plan.Flags[j].FlagName })
+	return plan
+}
+
+// Count returns how many flags the plan touches with the given kind.
+func (p *Plan) Count(kind DiffKind) int {
+	count := 0
+	for _, diff := range p.Flags {
+		if diff.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// HasChanges reports whether applying the plan would write anything.
+func (p *Plan) HasChanges() bool {
+	return len(p.Flags) > p.Count(DiffUnchanged)
+}
+
+// String renders the plan as a readable per-flag diff, omitting unchanged
+// flags.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, diff := range p.Flags {
+		switch diff.Kind {
+		case DiffAdded:
+			fmt.Fprintf(&b, "+ %s (added)\n", diff.FlagName)
+		case DiffRemoved:
+			fmt.Fprintf(&b, "- %s (removed)\n", diff.FlagName)
+		case DiffChanged:
+			fmt.Fprintf(&b, "~ %s (changed)\n", diff.FlagName)
+		default:
+			continue
+		}
+		for _, change := range diff.Changes {
+			fmt.Fprintf(&b, "    %s\n", change)
+		}
+	}
+	fmt.Fprintf(&b, "%d added, %d changed, %d removed, %d unchanged\n",
+		p.Count(DiffAdded), p.Count(DiffChanged), p.Count(DiffRemoved), p.Count(DiffUnchanged))
+	return b.String()
+}
@@ -0,0 +1,380 @@
+// Package impressions records which variant of a flag each user was served,
+// and the tracking events that experiments are measured by, for analysing
+// experiments. The Recorder is an OpenFeature hook and tracker that keeps
+// both in memory and writes them to MongoDB in batches from a background
+// goroutine, so evaluations never wait on the database.
+package impressions
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+const (
+	// writeTimeout bounds a single batch write.
+	writeTimeout = 30 * time.Second
+	// sampleBuckets is how finely users are divided when sampling.
+	sampleBuckets = 10000
+)
+
+var _ openfeature.Hook = (*Recorder)(nil)
+var _ openfeature.Tracker = (*Recorder)(nil)
+
+// store is where the recorder writes; it is implemented by *client.Client.
+type store interface {
+	AddImpressions(ctx context.Context, impressions []client.Impression) error
+	AddTrackingEvents(ctx context.Context, events []client.TrackingEvent) error
+	RecentImpressions(ctx context.Context, targetingKeys []string, since time.Time) ([]client.Impression, error)
+}
+
+func New(opts *Options) (*Recorder, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating options: %w", err)
+	}
+	return start(opts, opts.Client), nil
+}
+
+// start creates a recorder that writes to store and starts it.
+func start(opts *Options, store store) *Recorder {
+	r := &Recorder{
+		sampleRate:        *opts.SampleRate,
+		dedupWindow:       opts.DedupWindow,
+		attributionWindow: opts.AttributionWindow,
+		batchSize:         opts.BatchSize,
+		flushInterval:     opts.FlushInterval,
+		logger:            opts.Logger,
+		store:             store,
+		impressions:       make(chan client.Impression, opts.BufferSize),
+		events:            make(chan client.TrackingEvent, opts.BufferSize),
+		seen:              map[dedupKey]time.Time{},
+		done:              make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Recorder is a hook that records an impression after every successful
+// evaluation, and a tracker that records tracking events linked to them.
+type Recorder struct {
+	openfeature.UnimplementedHook
+
+	sampleRate        float64
+	dedupWindow       time.Duration
+	attributionWindow time.Duration
+	batchSize         int
+	flushInterval     time.Duration
+	logger            *slog.Logger
+	store             store
+
+	// mu guards closed, so nothing is sent on the queues once they are
+	// closed.
+	mu          sync.RWMutex
+	closed      bool
+	impressions chan client.Impression
+	events      chan client.TrackingEvent
+	// done is closed once the last batches are written.
+	done chan struct{}
+
+	seenMu sync.Mutex
+	// seen is when each user was last recorded being served a variant.
+	seen map[dedupKey]time.Time
+
+	dropped atomic.Uint64
+}
+
+type dedupKey struct {
+	flagName     string
+	variant      string
+	targetingKey string
+}
+
+// After records the impression of a successful evaluation, unless it is not
+// sampled, was recorded within the dedup window, or the buffer is full.
+func (r *Recorder) After(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, hookHints openfeature.HookHints) error {
+	impression := client.Impression{
+		FlagName:     details.FlagKey,
+		Variant:      details.Variant,
+		TargetingKey: hookContext.EvaluationContext().TargetingKey(),
+		Reason:       string(details.Reason),
+		Timestamp:    time.Now().UTC(),
+	}
+	if ruleIndex, err := details.FlagMetadata.GetInt(flag.MetadataRuleIndex); err == nil {
+		index := int(ruleIndex)
+		impression.RuleIndex = &index
+	}
+	if !r.sampled(impression.TargetingKey) || r.duplicate(impression) {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return nil
+	}
+	select {
+	case r.impressions <- impression:
+	default:
+		r.dropped.Add(1)
+		// The impression was not recorded, so the next one for this user
+		// must not be deduplicated against it.
+		r.forget(impression)
+	}
+	return nil
+}
+
+// Track records a tracking event, unless the user is not sampled or the
+// buffer is full. Users are sampled the same way as impressions, so the
+// events of every sampled user can be linked to what they were served.
+func (r *Recorder) Track(ctx context.Context, trackingEventName string, evaluationContext openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	event := client.TrackingEvent{
+		Name:         trackingEventName,
+		TargetingKey: evaluationContext.TargetingKey(),
+		Value:        details.Value(),
+		Attributes:   details.Attributes(),
+		Timestamp:    time.Now().UTC(),
+	}
+	if len(event.Attributes) == 0 {
+		event.Attributes = nil
+	}
+	if !r.sampled(event.TargetingKey) {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many impressions and tracking events were dropped
+// because the buffer was full or they could not be written.
+func (r *Recorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Close stops recording and writes the impressions and events still
+// waiting.
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.impressions)
+		close(r.events)
+	}
+	r.mu.Unlock()
+	<-r.done
+}
+
+// sampled reports whether the user's impressions are recorded. Users are
+// placed by a hash of their targeting key so the decision is the same for
+// every evaluation; impressions without one are sampled at random.
+func (r *Recorder) sampled(targetingKey string) bool {
+	if r.sampleRate >= 1 {
+		return true
+	}
+	if targetingKey == "" {
+		return rand.Float64() < r.sampleRate
+	}
+	h := fnv.New64a()
+	h.Write([]byte(targetingKey))
+	return float64(h.Sum64()%sampleBuckets)/sampleBuckets < r.sampleRate
+}
+
+// duplicate reports whether the user was already recorded being served this
+// variant within the dedup window, and otherwise remembers that they now
+// were. Impressions without a targeting key are never duplicates.
+func (r *Recorder) duplicate(impression client.Impression) bool {
+	if r.dedupWindow <= 0 || impression.TargetingKey == "" {
+		return false
+	}
+	key := dedupKey{flagName: impression.FlagName, variant: impression.Variant, targetingKey: impression.TargetingKey}
+
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	if last, ok := r.seen[key]; ok && impression.Timestamp.Sub(last) < r.dedupWindow {
+		return true
+	}
+	r.seen[key] = impression.Timestamp
+	return false
+}
+
+// forget removes a dropped impression from the dedup window, unless a later
+// impression has been recorded for the user since.
+func (r *Recorder) forget(impression client.Impression) {
+	if r.dedupWindow <= 0 || impression.TargetingKey == "" {
+		return
+	}
+	key := dedupKey{flagName: impression.FlagName, variant: impression.Variant, targetingKey: impression.TargetingKey}
+
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	if r.seen[key].Equal(impression.Timestamp) {
+		delete(r.seen, key)
+	}
+}
+
+// pruneSeen forgets users whose dedup window has passed.
+func (r *Recorder) pruneSeen() {
+	if r.dedupWindow <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-r.dedupWindow)
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	for key, last := range r.seen {
+		if last.Before(cutoff) {
+			delete(r.seen, key)
+		}
+	}
+}
+
+// run writes impressions and events whenever a batch fills up or the flush
+// interval passes, until the queues are closed. Waiting impressions are
+// written before every batch of events, so events can be linked to
+// impressions recorded just before them.
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	impressions := make([]client.Impression, 0, r.batchSize)
+	events := make([]client.TrackingEvent, 0, r.batchSize)
+	flushImpressions := func() {
+		if len(impressions) > 0 {
+			r.writeImpressions(impressions)
+			impressions = make([]client.Impression, 0, r.batchSize)
+		}
+	}
+	flushEvents := func() {
+		flushImpressions()
+		if len(events) > 0 {
+			r.writeEvents(events)
+			events = make([]client.TrackingEvent, 0, r.batchSize)
+		}
+	}
+
+	impressionQueue, eventQueue := r.impressions, r.events
+	for impressionQueue != nil || eventQueue != nil {
+		select {
+		case impression, ok := <-impressionQueue:
+			if !ok {
+				impressionQueue = nil
+				continue
+			}
+			impressions = append(impressions, impression)
+			if len(impressions) >= r.batchSize {
+				flushImpressions()
+			}
+		case event, ok := <-eventQueue:
+			if !ok {
+				eventQueue = nil
+				continue
+			}
+			events = append(events, event)
+			if len(events) >= r.batchSize {
+				flushEvents()
+			}
+		case <-ticker.C:
+			flushEvents()
+			r.pruneSeen()
+		}
+	}
+	flushEvents()
+}
+
+func (r *Recorder) writeImpressions(batch []client.Impression) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	if err := r.store.AddImpressions(ctx, batch); err != nil {
+		r.dropped.Add(uint64(len(batch)))
+		r.logger.Error("error writing impressions, dropping them", "impressions", len(batch), "error", err)
+	}
+}
+
+// writeEvents links the events to their users' impressions and writes them.
+// Events that cannot be linked are still written.
+func (r *Recorder) writeEvents(batch []client.TrackingEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	var targetingKeys []string
+	seen := map[string]bool{}
+	earliest := batch[0].Timestamp
+	for _, event := range batch {
+		if event.TargetingKey != "" && !seen[event.TargetingKey] {
+			seen[event.TargetingKey] = true
+			targetingKeys = append(targetingKeys, event.TargetingKey)
+		}
+		if event.Timestamp.Before(earliest) {
+			earliest = event.Timestamp
+		}
+	}
+	if len(targetingKeys) > 0 {
+		impressions, err := r.store.RecentImpressions(ctx, targetingKeys, earliest.Add(-r.attributionWindow))
+		if err != nil {
+			r.logger.Error("error getting impressions to link tracking events to", "events", len(batch), "error", err)
+		} else {
+			link(batch, impressions, r.attributionWindow)
+		}
+	}
+
+	if err := r.store.AddTrackingEvents(ctx, batch); err != nil {
+		r.dropped.Add(uint64(len(batch)))
+		r.logger.Error("error writing tracking events, dropping them", "events", len(batch), "error", err)
+	}
+}
+
+// link sets each event's impressions to the variant of every flag its user
+// was most recently served within window before the event. impressions must
+// be ordered oldest first.
+func link(events []client.TrackingEvent, impressions []client.Impression, window time.Duration) {
+	byUser := map[string][]client.Impression{}
+	for _, impression := range impressions {
+		byUser[impression.TargetingKey] = append(byUser[impression.TargetingKey], impression)
+	}
+	for i := range events {
+		event := &events[i]
+		if event.TargetingKey == "" {
+			continue
+		}
+		latest := map[string]client.Impression{}
+		var order []string
+		for _, impression := range byUser[event.TargetingKey] {
+			if impression.Timestamp.After(event.Timestamp) {
+				break
+			}
+			if event.Timestamp.Sub(impression.Timestamp) > window {
+				continue
+			}
+			if _, ok := latest[impression.FlagName]; !ok {
+				order = append(order, impression.FlagName)
+			}
+			latest[impression.FlagName] = impression
+		}
+		event.Impressions = nil
+		for _, flagName := range order {
+			impression := latest[flagName]
+			event.Impressions = append(event.Impressions, client.LinkedImpression{
+				FlagName:  impression.FlagName,
+				Variant:   impression.Variant,
+				Timestamp: impression.Timestamp,
+			})
+		}
+	}
+}
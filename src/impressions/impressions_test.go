@@ -0,0 +1,361 @@
+package impressions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+// fakeStore keeps what a recorder writes in memory.
+type fakeStore struct {
+	mu      sync.Mutex
+	batches [][]client.Impression
+	events  []client.TrackingEvent
+	// beforeWrite, when set, is called before every impression batch is
+	// written, and its error fails the write.
+	beforeWrite func() error
+}
+
+func (s *fakeStore) AddImpressions(ctx context.Context, batch []client.Impression) error {
+	if s.beforeWrite != nil {
+		if err := s.beforeWrite(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeStore) AddTrackingEvents(ctx context.Context, events []client.TrackingEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *fakeStore) RecentImpressions(ctx context.Context, targetingKeys []string, since time.Time) ([]client.Impression, error) {
+	var recent []client.Impression
+	for _, impression := range s.impressions() {
+		if slices.Contains(targetingKeys, impression.TargetingKey) && !impression.Timestamp.Before(since) {
+			recent = append(recent, impression)
+		}
+	}
+	return recent, nil
+}
+
+func (s *fakeStore) impressions() []client.Impression {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var impressions []client.Impression
+	for _, batch := range s.batches {
+		impressions = append(impressions, batch...)
+	}
+	return impressions
+}
+
+// newRecorder starts a recorder that only flushes when a batch fills up or
+// it is closed.
+func newRecorder(t *testing.T, opts *Options, store store) *Recorder {
+	t.Helper()
+	opts.Client = &client.Client{}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = time.Hour
+	}
+	require.NoError(t, opts.Validate())
+	return start(opts, store)
+}
+
+// serve records an impression of variant being served to targetingKey.
+func serve(t *testing.T, r *Recorder, flagName, variant, targetingKey string) {
+	t.Helper()
+	hookContext := openfeature.NewHookContext(flagName, openfeature.Boolean, false,
+		openfeature.ClientMetadata{}, openfeature.Metadata{}, openfeature.NewEvaluationContext(targetingKey, nil))
+	details := openfeature.InterfaceEvaluationDetails{
+		EvaluationDetails: openfeature.EvaluationDetails{
+			FlagKey:  flagName,
+			FlagType: openfeature.Boolean,
+			ResolutionDetail: openfeature.ResolutionDetail{
+				Variant: variant,
+				Reason:  openfeature.TargetingMatchReason,
+			},
+		},
+	}
+	require.NoError(t, r.After(context.Background(), hookContext, details, openfeature.HookHints{}))
+}
+
+// testProvider evaluates flags from a cache with the recorder as its hook,
+// the way mongoprovider does.
+type testProvider struct {
+	*cache.CacheEvaluator
+	recorder *Recorder
+}
+
+func (p *testProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: "test-provider"}
+}
+
+func (p *testProvider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{p.recorder}
+}
+
+func TestRecorder(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil), store)
+
+	c := cache.New()
+	require.NoError(t, c.SetAll(map[string]flag.Definition{
+		"checkout": {
+			FlagName:     "checkout",
+			DefaultValue: false,
+			Rules: []rule.ConcreteRule{
+				{ExactMatchRule: &rule.ExactMatchRule{Key: "plan", KeyValue: "free", VariantID: "free", ValueData: false}},
+				{ExactMatchRule: &rule.ExactMatchRule{Key: "plan", KeyValue: "pro", VariantID: "pro", ValueData: true}},
+			},
+		},
+	}))
+	provider := &testProvider{CacheEvaluator: cache.NewEvaluator(c), recorder: recorder}
+	require.NoError(t, openfeature.SetNamedProviderAndWait(t.Name(), provider))
+	ofClient := openfeature.NewClient(t.Name())
+
+	ctx := context.Background()
+	value, err := ofClient.BooleanValue(ctx, "checkout", false, openfeature.NewEvaluationContext("alice", map[string]any{"plan": "pro"}))
+	require.NoError(t, err)
+	assert.True(t, value)
+	_, err = ofClient.BooleanValue(ctx, "checkout", false, openfeature.NewEvaluationContext("bob", nil))
+	require.NoError(t, err)
+
+	recorder.Close()
+	impressions := store.impressions()
+	require.Len(t, impressions, 2)
+
+	assert.Equal(t, "checkout", impressions[0].FlagName)
+	assert.Equal(t, "pro", impressions[0].Variant)
+	assert.Equal(t, "alice", impressions[0].TargetingKey)
+	assert.Equal(t, string(openfeature.TargetingMatchReason), impressions[0].Reason)
+	require.NotNil(t, impressions[0].RuleIndex)
+	assert.Equal(t, 1, *impressions[0].RuleIndex)
+	assert.False(t, impressions[0].Timestamp.IsZero())
+
+	assert.Equal(t, "bob", impressions[1].TargetingKey)
+	assert.Nil(t, impressions[1].RuleIndex)
+}
+
+func TestBatching(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil).WithBatchSize(2), store)
+	for i := range 5 {
+		serve(t, recorder, "checkout", "on", fmt.Sprintf("user-%d", i))
+	}
+	recorder.Close()
+
+	require.Len(t, store.batches, 3)
+	assert.Len(t, store.batches[0], 2)
+	assert.Len(t, store.batches[1], 2)
+	assert.Len(t, store.batches[2], 1)
+}
+
+func TestFlushInterval(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil).WithFlushInterval(10*time.Millisecond), store)
+	defer recorder.Close()
+
+	serve(t, recorder, "checkout", "on", "alice")
+	assert.Eventually(t, func() bool { return len(store.impressions()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestDedup(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil).WithDedupWindow(time.Hour), store)
+	serve(t, recorder, "checkout", "on", "alice")
+	serve(t, recorder, "checkout", "on", "alice")
+	serve(t, recorder, "checkout", "off", "alice")
+	serve(t, recorder, "search", "on", "alice")
+	serve(t, recorder, "checkout", "on", "bob")
+	// Impressions without a targeting key can't be told apart.
+	serve(t, recorder, "checkout", "on", "")
+	serve(t, recorder, "checkout", "on", "")
+	recorder.Close()
+
+	assert.Len(t, store.impressions(), 6)
+}
+
+func TestSampling(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil).WithSampleRate(0.25), store)
+	defer recorder.Close()
+
+	sampled := 0
+	for i := range 1000 {
+		key := fmt.Sprintf("user-%d", i)
+		decision := recorder.sampled(key)
+		assert.Equal(t, decision, recorder.sampled(key), "the decision for %s changed", key)
+		if decision {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 250, sampled, 50)
+}
+
+func TestInvalidSampleRate(t *testing.T) {
+	_, err := New(NewOptions(&client.Client{}).WithSampleRate(1.5))
+	assert.Error(t, err)
+}
+
+func TestSampleRateDefaults(t *testing.T) {
+	opts := NewOptions(&client.Client{})
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, 1.0, *opts.SampleRate, "an unset rate records everyone")
+
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil).WithSampleRate(0), store)
+	defer recorder.Close()
+	assert.Equal(t, 0.0, recorder.sampleRate, "a rate of 0 is kept")
+	assert.False(t, recorder.sampled("user-1"))
+	assert.False(t, recorder.sampled(""))
+}
+
+func TestDropsWhenFull(t *testing.T) {
+	writing := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	store := &fakeStore{beforeWrite: func() error {
+		once.Do(func() {
+			close(writing)
+			<-release
+		})
+		return nil
+	}}
+	recorder := newRecorder(t, NewOptions(nil).WithBatchSize(1).WithBufferSize(1), store)
+
+	serve(t, recorder, "checkout", "on", "alice")
+	<-writing
+	// The writer is stuck on alice, so carol does not fit in the buffer.
+	serve(t, recorder, "checkout", "on", "bob")
+	serve(t, recorder, "checkout", "on", "carol")
+	assert.Equal(t, uint64(1), recorder.Dropped())
+
+	close(release)
+	recorder.Close()
+	impressions := store.impressions()
+	require.Len(t, impressions, 2)
+	assert.Equal(t, "bob", impressions[1].TargetingKey)
+}
+
+func TestDropDoesNotDedup(t *testing.T) {
+	writing := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	store := &fakeStore{beforeWrite: func() error {
+		once.Do(func() {
+			close(writing)
+			<-release
+		})
+		return nil
+	}}
+	recorder := newRecorder(t, NewOptions(nil).WithBatchSize(1).WithBufferSize(1).WithDedupWindow(time.Hour), store)
+
+	serve(t, recorder, "checkout", "on", "alice")
+	<-writing
+	serve(t, recorder, "checkout", "on", "bob")
+	// The buffer is full, so carol's first impression is dropped.
+	serve(t, recorder, "checkout", "on", "carol")
+	require.Equal(t, uint64(1), recorder.Dropped())
+
+	close(release)
+	require.Eventually(t, func() bool { return len(store.impressions()) == 2 }, time.Second, time.Millisecond)
+	serve(t, recorder, "checkout", "on", "carol")
+	recorder.Close()
+
+	impressions := store.impressions()
+	require.Len(t, impressions, 3)
+	assert.Equal(t, "carol", impressions[2].TargetingKey)
+}
+
+func TestWriteFailure(t *testing.T) {
+	store := &fakeStore{beforeWrite: func() error { return assert.AnError }}
+	recorder := newRecorder(t, NewOptions(nil), store)
+	serve(t, recorder, "checkout", "on", "alice")
+	serve(t, recorder, "checkout", "on", "bob")
+	recorder.Close()
+	assert.Equal(t, uint64(2), recorder.Dropped())
+}
+
+func TestAfterClose(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil), store)
+	recorder.Close()
+	serve(t, recorder, "checkout", "on", "alice")
+	recorder.Track(context.Background(), "purchase", openfeature.NewEvaluationContext("alice", nil), openfeature.NewTrackingEventDetails(1))
+	recorder.Close()
+	assert.Empty(t, store.impressions())
+	assert.Empty(t, store.events)
+}
+
+func TestTrack(t *testing.T) {
+	store := &fakeStore{}
+	recorder := newRecorder(t, NewOptions(nil), store)
+	serve(t, recorder, "checkout", "off", "alice")
+	serve(t, recorder, "checkout", "on", "alice")
+	serve(t, recorder, "search", "v2", "alice")
+	serve(t, recorder, "checkout", "off", "bob")
+
+	ctx := context.Background()
+	recorder.Track(ctx, "purchase", openfeature.NewEvaluationContext("alice", nil), openfeature.NewTrackingEventDetails(42.5).Add("currency", "EUR"))
+	recorder.Track(ctx, "purchase", openfeature.NewEvaluationContext("carol", nil), openfeature.NewTrackingEventDetails(0))
+	recorder.Close()
+
+	require.Len(t, store.events, 2)
+	alice := store.events[0]
+	assert.Equal(t, "purchase", alice.Name)
+	assert.Equal(t, "alice", alice.TargetingKey)
+	assert.Equal(t, 42.5, alice.Value)
+	assert.Equal(t, map[string]any{"currency": "EUR"}, alice.Attributes)
+	require.Len(t, alice.Impressions, 2)
+	assert.Equal(t, "checkout", alice.Impressions[0].FlagName)
+	assert.Equal(t, "on", alice.Impressions[0].Variant)
+	assert.Equal(t, "search", alice.Impressions[1].FlagName)
+	assert.Equal(t, "v2", alice.Impressions[1].Variant)
+
+	assert.Equal(t, "carol", store.events[1].TargetingKey)
+	assert.Empty(t, store.events[1].Impressions)
+	assert.Nil(t, store.events[1].Attributes)
+}
+
+func TestLink(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	impressions := []client.Impression{
+		{FlagName: "checkout", Variant: "off", TargetingKey: "alice", Timestamp: start},
+		{FlagName: "checkout", Variant: "on", TargetingKey: "alice", Timestamp: start.Add(2 * time.Hour)},
+		{FlagName: "checkout", Variant: "off", TargetingKey: "alice", Timestamp: start.Add(5 * time.Hour)},
+	}
+	events := []client.TrackingEvent{
+		// Before any impression.
+		{Name: "signup", TargetingKey: "alice", Timestamp: start.Add(-time.Hour)},
+		// Only the impression within the window.
+		{Name: "purchase", TargetingKey: "alice", Timestamp: start.Add(3 * time.Hour)},
+		// Outside the window of the earlier impressions, and before the
+		// later one.
+		{Name: "refund", TargetingKey: "alice", Timestamp: start.Add(4 * time.Hour)},
+		{Name: "purchase", Timestamp: start.Add(3 * time.Hour)},
+	}
+	link(events, impressions, 90*time.Minute)
+
+	assert.Empty(t, events[0].Impressions)
+	require.Len(t, events[1].Impressions, 1)
+	assert.Equal(t, "on", events[1].Impressions[0].Variant)
+	assert.Empty(t, events[2].Impressions)
+	assert.Empty(t, events[3].Impressions)
+}
@@ -0,0 +1,142 @@
+package impressions
+
+import (
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client writes the impressions and events. mongoprovider replaces
+	// it with the provider's own client.
+	Client *client.Client
+
+	// ===== Optional =====
+
+	// SampleRate is the fraction of users, between 0 and 1, whose
+	// impressions and events are recorded. Users are sampled by targeting
+	// key, so a user's impressions and events are either all recorded or
+	// all skipped, and 0 records none. If not provided, it defaults to 1.
+	SampleRate *float64
+	// DedupWindow skips an impression when the same user was served the
+	// same variant of the flag within the window. If not provided, every
+	// impression is recorded.
+	DedupWindow time.Duration
+	// AttributionWindow is how long before a tracking event an
+	// impression is linked to it. If not provided, it defaults to 7 days.
+	AttributionWindow time.Duration
+	// BatchSize is how many impressions or events are written at once. If not
+	// provided, it defaults to 500.
+	BatchSize int
+	// FlushInterval is the longest an impression or event waits before
+	// it is written. If not provided, it defaults to 5 seconds.
+	FlushInterval time.Duration
+	// BufferSize is how many impressions, and separately how many events,
+	// can wait to be written. They are dropped rather than slowing the
+	// application down when the buffer is full. If not provided, it
+	// defaults to 10000.
+	BufferSize int
+	// Logger is the logger to use for the recorder.
+	Logger *slog.Logger
+}
+
+func NewOptions(client *client.Client) *Options {
+	return &Options{
+		Client: client,
+	}
+}
+
+func (opts *Options) WithSampleRate(sampleRate float64) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.SampleRate = &sampleRate
+	return opts
+}
+
+func (opts *Options) WithDedupWindow(window time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.DedupWindow = window
+	return opts
+}
+
+func (opts *Options) WithAttributionWindow(window time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.AttributionWindow = window
+	return opts
+}
+
+func (opts *Options) WithBatchSize(batchSize int) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.BatchSize = batchSize
+	return opts
+}
+
+func (opts *Options) WithFlushInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.FlushInterval = interval
+	return opts
+}
+
+func (opts *Options) WithBufferSize(bufferSize int) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.BufferSize = bufferSize
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+	if opts.SampleRate != nil && (*opts.SampleRate < 0 || *opts.SampleRate > 1) {
+		return mongoopenfeature.ErrInvalidSampleRate
+	}
+
+	// Setting defaults
+	if opts.SampleRate == nil {
+		sampleRate := 1.0
+		opts.SampleRate = &sampleRate
+	}
+	if opts.AttributionWindow <= 0 {
+		opts.AttributionWindow = 7 * 24 * time.Hour
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 10000
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package metrics describes what the provider reports about its own workings,
+// such as cache size, watch state and retries, so it can be monitored. Nop
+// discards everything; Prometheus keeps the values and serves them in the
+// Prometheus text format.
+package metrics
+
+import (
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WatchState is how a watch handler is keeping the cache up to date.
+type WatchState string
+
+const (
+	WatchStateChangeStream WatchState = "change_stream"
+	WatchStatePolling      WatchState = "polling"
+	// WatchStateStopped means every change stream and polling attempt
+	// failed, so the cache is no longer updated.
+	WatchStateStopped WatchState = "stopped"
+)
+
+// Metrics receives measurements from the cache, watch handler, event handler
+// and client. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// CacheSize reports how many flags the cache holds.
+	CacheSize(size int)
+	// WatchState reports how the watch handler is keeping the cache up
+	// to date.
+	WatchState(state WatchState)
+	// WatchReconnect counts the watch handler restarting its change
+	// stream or polling after an error.
+	WatchReconnect(state WatchState)
+	// Synced records that the cache was brought up to date with MongoDB:
+	// a change was applied, a poll succeeded, or a change stream opened.
+	Synced(at time.Time)
+	// EventDropped counts an event dropped because the event channel was
+	// full.
+	EventDropped(eventType openfeature.EventType)
+	// Retry counts a failed attempt of a client operation, such as
+	// "set_flag", that is tried up to MaxTries times.
+	Retry(operation string)
+}
+
+var _ Metrics = Nop{}
+
+// Nop discards every measurement. It is the default everywhere metrics are
+// accepted.
+type Nop struct{}
+
+func (Nop) CacheSize(int)                      {}
+func (Nop) WatchState(WatchState)              {}
+func (Nop) WatchReconnect(WatchState)          {}
+func (Nop) Synced(time.Time)                   {}
+func (Nop) EventDropped(openfeature.EventType) {}
+func (Nop) Retry(string)                       {}
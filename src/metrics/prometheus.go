@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Namespace prefixes every metric name Prometheus serves.
+const Namespace = "mongo_openfeature"
+
+var _ Metrics = (*Prometheus)(nil)
+var _ http.Handler = (*Prometheus)(nil)
+
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		now:             time.Now,
+		watchReconnects: map[string]uint64{},
+		eventsDropped:   map[string]uint64{},
+		retries:         map[string]uint64{},
+	}
+}
+
+// Prometheus keeps the latest measurements and serves them in the Prometheus
+// text exposition format. Give each provider its own, as the gauges hold a
+// single value.
+type Prometheus struct {
+	now func() time.Time
+
+	mu              sync.Mutex
+	cacheSize       int
+	watchState      WatchState
+	watchReconnects map[string]uint64
+	lastSync        time.Time
+	eventsDropped   map[string]uint64
+	retries         map[string]uint64
+}
+
+func (p *Prometheus) CacheSize(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cacheSize = size
+}
+
+func (p *Prometheus) WatchState(state WatchState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.watchState = state
+}
+
+func (p *Prometheus) WatchReconnect(state WatchState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.watchReconnects[string(state)]++
+}
+
+func (p *Prometheus) Synced(at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if at.After(p.lastSync) {
+		p.lastSync = at
+	}
+}
+
+func (p *Prometheus) EventDropped(eventType openfeature.EventType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.eventsDropped[string(eventType)]++
+}
+
+func (p *Prometheus) Retry(operation string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[operation]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(p.render())
+}
+
+func (p *Prometheus) render() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "cache_flags", "gauge", "Number of flags in the cache.")
+	fmt.Fprintf(&buf, "%s_cache_flags %d\n", Namespace, p.cacheSize)
+
+	writeHeader(&buf, "watch_state", "gauge", "How the cache is kept up to date; 1 for the current state.")
+	for _, state := range []WatchState{WatchStateChangeStream, WatchStatePolling, WatchStateStopped} {
+		value := 0
+		if state == p.watchState {
+			value = 1
+		}
+		fmt.Fprintf(&buf, "%s_watch_state{state=\"%s\"} %d\n", Namespace, state, value)
+	}
+
+	writeHeader(&buf, "watch_reconnects_total", "counter", "Times the change stream or polling was restarted after an error.")
+	writeCounters(&buf, "watch_reconnects_total", "state", p.watchReconnects)
+
+	// Before the first sync there is no sample rather than a made-up one.
+	writeHeader(&buf, "last_sync_timestamp_seconds", "gauge", "Unix time the cache was last brought up to date with MongoDB.")
+	if !p.lastSync.IsZero() {
+		fmt.Fprintf(&buf, "%s_last_sync_timestamp_seconds %g\n", Namespace, float64(p.lastSync.UnixNano())/1e9)
+	}
+	writeHeader(&buf, "seconds_since_last_sync", "gauge", "Seconds since the cache was last brought up to date with MongoDB.")
+	if !p.lastSync.IsZero() {
+		fmt.Fprintf(&buf, "%s_seconds_since_last_sync %g\n", Namespace, p.now().Sub(p.lastSync).Seconds())
+	}
+
+	writeHeader(&buf, "events_dropped_total", "counter", "Provider events dropped because the event channel was full.")
+	writeCounters(&buf, "events_dropped_total", "type", p.eventsDropped)
+
+	writeHeader(&buf, "client_retries_total", "counter", "Failed attempts of client operations, by operation.")
+	writeCounters(&buf, "client_retries_total", "operation", p.retries)
+	return buf.Bytes()
+}
+
+func writeHeader(buf *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(buf, "# HELP %s_%s %s\n", Namespace, name, help)
+	fmt.Fprintf(buf, "# TYPE %s_%s %s\n", Namespace, name, kind)
+}
+
+// writeCounters writes one sample per label value, in label order so the
+// output is stable.
+func writeCounters(buf *bytes.Buffer, name, label string, counters map[string]uint64) {
+	values := make([]string, 0, len(counters))
+	for value := range counters {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		fmt.Fprintf(buf, "%s_%s{%s=\"%s\"} %d\n", Namespace, name, label, escapeLabel(value), counters[value])
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	p.CacheSize(12)
+	p.WatchState(WatchStateChangeStream)
+	p.WatchReconnect(WatchStateChangeStream)
+	p.WatchReconnect(WatchStateChangeStream)
+	p.WatchState(WatchStatePolling)
+	p.Synced(now.Add(-90 * time.Second))
+	p.Synced(now.Add(-time.Hour)) // Older syncs do not move the time back.
+	p.EventDropped(openfeature.ProviderConfigChange)
+	p.Retry("set_flag")
+	p.Retry("set_flag")
+	p.Retry(`odd "name"`)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE mongo_openfeature_cache_flags gauge",
+		"mongo_openfeature_cache_flags 12",
+		`mongo_openfeature_watch_state{state="change_stream"} 0`,
+		`mongo_openfeature_watch_state{state="polling"} 1`,
+		`mongo_openfeature_watch_state{state="stopped"} 0`,
+		`mongo_openfeature_watch_reconnects_total{state="change_stream"} 2`,
+		"mongo_openfeature_last_sync_timestamp_seconds 1.74083031e+09",
+		"mongo_openfeature_seconds_since_last_sync 90",
+		`mongo_openfeature_events_dropped_total{type="PROVIDER_CONFIGURATION_CHANGED"} 1`,
+		"# TYPE mongo_openfeature_client_retries_total counter",
+		`mongo_openfeature_client_retries_total{operation="set_flag"} 2`,
+		`mongo_openfeature_client_retries_total{operation="odd \"name\""} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+}
+
+func TestPrometheusBeforeSync(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewPrometheus().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "mongo_openfeature_cache_flags 0\n")
+	assert.NotContains(t, body, "\nmongo_openfeature_seconds_since_last_sync ")
+	assert.Contains(t, body, "# TYPE mongo_openfeature_seconds_since_last_sync gauge\n")
+}
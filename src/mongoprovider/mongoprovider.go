@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
@@ -12,6 +13,9 @@ import (
 	"github.com/zackarysantana/mongo-openfeature-go/internal/watchhandler"
 	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
 	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextsampler"
+	"github.com/zackarysantana/mongo-openfeature-go/src/impressions"
+	"github.com/zackarysantana/mongo-openfeature-go/src/usage"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -22,23 +26,25 @@ const (
 var _ openfeature.FeatureProvider = (*Provider)(nil)
 var _ openfeature.EventHandler = (*Provider)(nil)
 var _ openfeature.StateHandler = (*Provider)(nil)
+var _ openfeature.Tracker = (*Provider)(nil)
 
 func New(opts *Options) (*Provider, *client.Client, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, nil, fmt.Errorf("validating options: %w", err)
 	}
-	cacheHandler := cache.New()
+	cacheHandler := cache.NewForEnvironment(opts.Environment).WithMetrics(opts.Metrics)
 
 	eventHandler, err := eventhandler.New(eventhandler.NewOptions(
 		eventhandler.CreateDroppedEventLogger(opts.Logger, ProviderName),
-	))
+	).WithMetrics(opts.Metrics))
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating event handler: %w", err)
 	}
 	watchHandler, err := watchhandler.New(watchhandler.NewOptions(opts.Client, opts.Database, opts.Collection, cacheHandler).
 		WithEventHandler(eventHandler).
 		WithDocumentID(opts.DocumentID).
-		WithLogger(opts.Logger),
+		WithLogger(opts.Logger).
+		WithMetrics(opts.Metrics),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating watch handler: %w", err)
@@ -46,17 +52,51 @@ func New(opts *Options) (*Provider, *client.Client, error) {
 
 	client, err := client.New(client.NewOptions(opts.Client, opts.Database, opts.Collection).
 		WithDocumentID(opts.DocumentID).
-		WithLogger(opts.Logger),
+		WithLogger(opts.Logger).
+		WithMetrics(opts.Metrics),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating mongo openfeature client: %w", err)
 	}
 
+	evaluator := cache.NewEvaluator(cacheHandler)
+	hooks := []openfeature.Hook{}
+	if opts.Telemetry != nil {
+		evaluator.WithObserver(opts.Telemetry)
+		hooks = append(hooks, opts.Telemetry)
+	}
+	var recorder *impressions.Recorder
+	if opts.Impressions != nil {
+		opts.Impressions.Client = client
+		if recorder, err = impressions.New(opts.Impressions); err != nil {
+			return nil, nil, fmt.Errorf("creating impression recorder: %w", err)
+		}
+		hooks = append(hooks, recorder)
+	}
+	var counter *usage.Counter
+	if opts.Usage != nil {
+		opts.Usage.Client = client
+		if counter, err = usage.New(opts.Usage); err != nil {
+			return nil, nil, fmt.Errorf("creating usage counter: %w", err)
+		}
+		hooks = append(hooks, counter)
+	}
+	var sampler *contextsampler.Sampler
+	if opts.ContextSampling != nil {
+		opts.ContextSampling.Client = client
+		if sampler, err = contextsampler.New(opts.ContextSampling); err != nil {
+			return nil, nil, fmt.Errorf("creating context sampler: %w", err)
+		}
+		hooks = append(hooks, sampler)
+	}
+
 	p := &Provider{
 		EventHandler:   eventHandler,
 		StateHandler:   statehandler.New(),
-		CacheEvaluator: cache.NewEvaluator(cacheHandler),
+		CacheEvaluator: evaluator,
 		cache:          cacheHandler,
+		hooks:          hooks,
+		recorder:       recorder,
 		logger:         opts.Logger,
 	}
 	p.StateHandler.RegisterShutdownFunc(p.EventHandler.Close)
@@ -66,6 +106,15 @@ func New(opts *Options) (*Provider, *client.Client, error) {
 		return nil
 	})
 	p.StateHandler.RegisterShutdownFunc(watchHandler.Close)
+	if recorder != nil {
+		p.StateHandler.RegisterShutdownFunc(recorder.Close)
+	}
+	if counter != nil {
+		p.StateHandler.RegisterShutdownFunc(counter.Close)
+	}
+	if sampler != nil {
+		p.StateHandler.RegisterShutdownFunc(sampler.Close)
+	}
 
 	p.StateHandler.RegisterStartupFunc(func() error {
 		// TODO: Edit all contexts to use a timeout and add it to the options.
@@ -73,6 +122,7 @@ func New(opts *Options) (*Provider, *client.Client, error) {
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
 				fmt.Println("No flags found in the document, initializing cache with empty values.")
+				opts.Metrics.Synced(time.Now())
 				return nil
 			}
 			return fmt.Errorf("getting all flags: %w", err)
@@ -80,6 +130,7 @@ func New(opts *Options) (*Provider, *client.Client, error) {
 		if err := p.cache.SetAll(flags); err != nil {
 			return fmt.Errorf("setting all flags in cache: %w", err)
 		}
+		opts.Metrics.Synced(time.Now())
 
 		return nil
 	})
@@ -91,6 +142,10 @@ type Provider struct {
 	*statehandler.StateHandler
 	*cache.CacheEvaluator
 	cache *cache.Cache
+	hooks []openfeature.Hook
+	// recorder records impressions and tracking events; nil when
+	// impressions are off.
+	recorder *impressions.Recorder
 
 	logger *slog.Logger
 }
@@ -100,5 +155,14 @@ func (s *Provider) Metadata() openfeature.Metadata {
 }
 
 func (s *Provider) Hooks() []openfeature.Hook {
-	return []openfeature.Hook{}
+	return s.hooks
+}
+
+// Track records a tracking event, such as a conversion, linked to the
+// variants the user was recently served. Events are only recorded when
+// impressions are.
+func (s *Provider) Track(ctx context.Context, trackingEventName string, evaluationContext openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	if s.recorder != nil {
+		s.recorder.Track(ctx, trackingEventName, evaluationContext, details)
+	}
 }
@@ -4,6 +4,11 @@ import (
 	"log/slog"
 
 	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/contextsampler"
+	"github.com/zackarysantana/mongo-openfeature-go/src/impressions"
+	"github.com/zackarysantana/mongo-openfeature-go/src/metrics"
+	"github.com/zackarysantana/mongo-openfeature-go/src/otelhook"
+	"github.com/zackarysantana/mongo-openfeature-go/src/usage"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -27,6 +32,36 @@ type Options struct {
 	// Logger is the logger to use for the provider.
 	// This is only used for logging service-fatal errors.
 	Logger *slog.Logger
+	// Environment is the environment to evaluate flags in. Flags
+	// without an override for it use their base definition, as do
+	// all flags when it is not provided.
+	Environment string
+	// Telemetry records every evaluation with OpenTelemetry: span
+	// events, evaluation and error counts, and latency. Telemetry is
+	// off when it is not provided.
+	Telemetry *otelhook.Hook
+	// Metrics records the provider's internals: cache size, watch state,
+	// reconnects, dropped events, syncs and client retries. Use
+	// metrics.NewPrometheus to serve them to Prometheus. If not provided,
+	// it defaults to metrics.Nop.
+	Metrics metrics.Metrics
+	// Impressions records which variant each user was served to the
+	// impressions collection, and events sent through the Track API to
+	// the tracking collection, for analysing experiments. Its Client is
+	// replaced with the provider's own. Neither is recorded when it is
+	// not provided.
+	Impressions *impressions.Options
+	// Usage counts the evaluations of every flag to the usage collection,
+	// so flags that are no longer used can be found. Its Client is
+	// replaced with the provider's own. Usage is not counted when it is
+	// not provided.
+	Usage *usage.Options
+	// ContextSampling counts the keys and value types of a sample of
+	// evaluation contexts to the context observations collection, so keys
+	// missing from the context schema or sent with the wrong type can be
+	// reported. Its Client is replaced with the provider's own. Contexts
+	// are not sampled when it is not provided.
+	ContextSampling *contextsampler.Options
 }
 
 func NewOptions(client *mongo.Client, database, collection string) *Options {
@@ -53,6 +88,54 @@ func (opts *Options) WithLogger(logger *slog.Logger) *Options {
 	return opts
 }
 
+func (opts *Options) WithEnvironment(environment string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Environment = environment
+	return opts
+}
+
+func (opts *Options) WithTelemetry(telemetry *otelhook.Hook) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Telemetry = telemetry
+	return opts
+}
+
+func (opts *Options) WithMetrics(m metrics.Metrics) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Metrics = m
+	return opts
+}
+
+func (opts *Options) WithImpressions(impressionOpts *impressions.Options) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Impressions = impressionOpts
+	return opts
+}
+
+func (opts *Options) WithUsage(usageOpts *usage.Options) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Usage = usageOpts
+	return opts
+}
+
+func (opts *Options) WithContextSampling(samplingOpts *contextsampler.Options) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ContextSampling = samplingOpts
+	return opts
+}
+
 func (opts *Options) Validate() error {
 	if opts == nil {
 		return mongoopenfeature.ErrNilOptions
@@ -72,5 +155,8 @@ func (opts *Options) Validate() error {
 	if opts.Logger == nil {
 		opts.Logger = slog.Default()
 	}
+	if opts.Metrics == nil {
+		opts.Metrics = metrics.Nop{}
+	}
 	return nil
 }
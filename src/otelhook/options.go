@@ -0,0 +1,51 @@
+package otelhook
+
+import (
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type Options struct {
+	// ===== Optional =====
+
+	// MeterProvider creates the instruments the hook records metrics
+	// with. If not provided, it defaults to the global meter provider.
+	MeterProvider metric.MeterProvider
+	// ContextID adds the evaluation's targeting key to span events as
+	// feature_flag.context.id. It is off by default because targeting
+	// keys often identify users.
+	ContextID bool
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (opts *Options) WithMeterProvider(meterProvider metric.MeterProvider) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.MeterProvider = meterProvider
+	return opts
+}
+
+func (opts *Options) WithContextID(contextID bool) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ContextID = contextID
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+
+	// Setting defaults
+	if opts.MeterProvider == nil {
+		opts.MeterProvider = otel.GetMeterProvider()
+	}
+	return nil
+}
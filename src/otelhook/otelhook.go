@@ -0,0 +1,184 @@
+// Package otelhook is an OpenFeature hook that records flag evaluations with
+// OpenTelemetry, following the feature flag semantic conventions. Each
+// evaluation adds a feature_flag.evaluation event to the span in its context
+// and is counted by flag, variant and reason. Failed evaluations are also
+// counted by error type.
+//
+// Hooks cannot time an evaluation, so latency is recorded by the provider,
+// which reports each evaluation to the hook as a cache.EvaluationObserver.
+package otelhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// EventName is the name of the span event recorded for every
+	// evaluation.
+	EventName = "feature_flag.evaluation"
+
+	// MetricEvaluations counts evaluations by flag key, provider,
+	// variant and reason.
+	MetricEvaluations = "feature_flag.evaluations"
+	// MetricErrors counts failed evaluations by flag key, provider and
+	// error type.
+	MetricErrors = "feature_flag.evaluation.errors"
+	// MetricDuration is the time the provider took to resolve a flag, in
+	// seconds, by flag key and reason.
+	MetricDuration = "feature_flag.evaluation.duration"
+
+	instrumentationName = "github.com/zackarysantana/mongo-openfeature-go/src/otelhook"
+)
+
+var _ openfeature.Hook = (*Hook)(nil)
+var _ cache.EvaluationObserver = (*Hook)(nil)
+
+func New(opts *Options) (*Hook, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating options: %w", err)
+	}
+	meter := opts.MeterProvider.Meter(instrumentationName)
+
+	evaluations, err := meter.Int64Counter(MetricEvaluations,
+		metric.WithDescription("Number of flag evaluations."),
+		metric.WithUnit("{evaluation}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s counter: %w", MetricEvaluations, err)
+	}
+	evaluationErrors, err := meter.Int64Counter(MetricErrors,
+		metric.WithDescription("Number of flag evaluations that failed."),
+		metric.WithUnit("{evaluation}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s counter: %w", MetricErrors, err)
+	}
+	duration, err := meter.Float64Histogram(MetricDuration,
+		metric.WithDescription("Time taken to resolve a flag."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s histogram: %w", MetricDuration, err)
+	}
+
+	return &Hook{
+		contextID:   opts.ContextID,
+		evaluations: evaluations,
+		errors:      evaluationErrors,
+		duration:    duration,
+	}, nil
+}
+
+type Hook struct {
+	openfeature.UnimplementedHook
+
+	contextID   bool
+	evaluations metric.Int64Counter
+	errors      metric.Int64Counter
+	duration    metric.Float64Histogram
+}
+
+// Error counts the failed evaluation by its error type.
+func (h *Hook) Error(ctx context.Context, hookContext openfeature.HookContext, err error, hookHints openfeature.HookHints) {
+	h.errors.Add(ctx, 1, metric.WithAttributes(
+		semconv.FeatureFlagKey(hookContext.FlagKey()),
+		semconv.FeatureFlagProviderName(hookContext.ProviderMetadata().Name),
+		semconv.ErrorTypeKey.String(errorType(err)),
+	))
+}
+
+// Finally counts the evaluation and records it as an event on the span in
+// ctx, whether it succeeded or not.
+func (h *Hook) Finally(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, hookHints openfeature.HookHints) {
+	attrs := []attribute.KeyValue{
+		semconv.FeatureFlagKey(hookContext.FlagKey()),
+		semconv.FeatureFlagProviderName(hookContext.ProviderMetadata().Name),
+		semconv.FeatureFlagEvaluationReasonKey.String(reason(details.ErrorCode, details.Reason)),
+	}
+	if details.Variant != "" {
+		attrs = append(attrs, semconv.FeatureFlagVariant(details.Variant))
+	}
+	h.evaluations.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if details.ErrorCode != "" {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(strings.ToLower(string(details.ErrorCode))))
+		if details.ErrorMessage != "" {
+			attrs = append(attrs, semconv.FeatureFlagEvaluationErrorMessage(details.ErrorMessage))
+		}
+	}
+	if h.contextID {
+		if targetingKey := hookContext.EvaluationContext().TargetingKey(); targetingKey != "" {
+			attrs = append(attrs, semconv.FeatureFlagContextID(targetingKey))
+		}
+	}
+	span.AddEvent(EventName, trace.WithAttributes(attrs...))
+}
+
+// ObserveEvaluation records how long the provider took to resolve a flag.
+func (h *Hook) ObserveEvaluation(ctx context.Context, flagKey string, detail openfeature.ProviderResolutionDetail, duration time.Duration) {
+	h.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		semconv.FeatureFlagKey(flagKey),
+		semconv.FeatureFlagEvaluationReasonKey.String(reason(detail.ResolutionDetail().ErrorCode, detail.Reason)),
+	))
+}
+
+// reason returns the semantic convention value for an evaluation's reason:
+// the OpenFeature reason in lower case, or "error" if the evaluation failed.
+func reason(errorCode openfeature.ErrorCode, reason openfeature.Reason) string {
+	switch {
+	case errorCode != "":
+		return strings.ToLower(string(openfeature.ErrorReason))
+	case reason == "":
+		return strings.ToLower(string(openfeature.UnknownReason))
+	default:
+		return strings.ToLower(string(reason))
+	}
+}
+
+// errorCodes are the OpenFeature error codes, used to recognise them in the
+// errors passed to Error.
+var errorCodes = []openfeature.ErrorCode{
+	openfeature.ProviderNotReadyCode,
+	openfeature.ProviderFatalCode,
+	openfeature.FlagNotFoundCode,
+	openfeature.ParseErrorCode,
+	openfeature.TypeMismatchCode,
+	openfeature.TargetingKeyMissingCode,
+	openfeature.InvalidContextCode,
+	openfeature.GeneralCode,
+}
+
+// errorType returns the semantic convention value for an evaluation error:
+// the OpenFeature error code in lower case, or "general" for errors that
+// did not come from resolving the flag, such as a failing hook. The SDK
+// passes resolution errors to hooks as text ("error code: CODE: message"),
+// so the code is read from the message.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, openfeature.ProviderNotReadyError):
+		return strings.ToLower(string(openfeature.ProviderNotReadyCode))
+	case errors.Is(err, openfeature.ProviderFatalError):
+		return strings.ToLower(string(openfeature.ProviderFatalCode))
+	}
+	message := strings.TrimPrefix(err.Error(), "error code: ")
+	if code, _, ok := strings.Cut(message, ":"); ok && slices.Contains(errorCodes, openfeature.ErrorCode(code)) {
+		return strings.ToLower(code)
+	}
+	return strings.ToLower(string(openfeature.GeneralCode))
+}
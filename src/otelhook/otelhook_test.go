@@ -0,0 +1,158 @@
+package otelhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testProvider evaluates flags from a cache, reporting evaluations to the
+// hook the way mongoprovider does.
+type testProvider struct {
+	*cache.CacheEvaluator
+	hook *Hook
+}
+
+func (p *testProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: "test-provider"}
+}
+
+func (p *testProvider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{p.hook}
+}
+
+func setup(t *testing.T, opts *Options) (*openfeature.Client, *tracetest.InMemoryExporter, *sdktrace.TracerProvider, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	hook, err := New(opts.WithMeterProvider(meterProvider))
+	require.NoError(t, err)
+
+	c := cache.New()
+	require.NoError(t, c.SetAll(map[string]flag.Definition{
+		"checkout": {
+			FlagName:     "checkout",
+			DefaultValue: false,
+			Rules: []rule.ConcreteRule{
+				{ExactMatchRule: &rule.ExactMatchRule{Key: "user", KeyValue: "alice", VariantID: "on", ValueData: true}},
+			},
+		},
+	}))
+	provider := &testProvider{CacheEvaluator: cache.NewEvaluator(c).WithObserver(hook), hook: hook}
+	require.NoError(t, openfeature.SetNamedProviderAndWait(t.Name(), provider))
+	return openfeature.NewClient(t.Name()), spans, tracerProvider, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	metrics := map[string]metricdata.Aggregation{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func attr(set attribute.Set, key string) string {
+	value, _ := set.Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func TestHook(t *testing.T) {
+	client, spans, tracerProvider, reader := setup(t, NewOptions().WithContextID(true))
+
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "request")
+	alice := openfeature.NewEvaluationContext("alice", map[string]any{"user": "alice"})
+	assert.True(t, client.Boolean(ctx, "checkout", false, alice))
+	assert.False(t, client.Boolean(ctx, "checkout", false, openfeature.NewTargetlessEvaluationContext(map[string]any{"user": "bob"})))
+	assert.Equal(t, "fallback", client.String(ctx, "checkout", "fallback", alice))
+	span.End()
+
+	t.Run("SpanEvents", func(t *testing.T) {
+		recorded := spans.GetSpans()
+		require.Len(t, recorded, 1)
+		events := recorded[0].Events
+		require.Len(t, events, 3)
+
+		matched := attribute.NewSet(events[0].Attributes...)
+		assert.Equal(t, EventName, events[0].Name)
+		assert.Equal(t, "checkout", attr(matched, "feature_flag.key"))
+		assert.Equal(t, "test-provider", attr(matched, "feature_flag.provider_name"))
+		assert.Equal(t, "on", attr(matched, "feature_flag.variant"))
+		assert.Equal(t, "targeting_match", attr(matched, "feature_flag.evaluation.reason"))
+		assert.Equal(t, "alice", attr(matched, "feature_flag.context.id"))
+
+		unmatched := attribute.NewSet(events[1].Attributes...)
+		assert.Equal(t, "default", attr(unmatched, "feature_flag.evaluation.reason"))
+		assert.False(t, unmatched.HasValue("feature_flag.context.id"))
+
+		mismatch := attribute.NewSet(events[2].Attributes...)
+		assert.Equal(t, "error", attr(mismatch, "feature_flag.evaluation.reason"))
+		assert.Equal(t, "type_mismatch", attr(mismatch, "error.type"))
+		assert.NotEmpty(t, attr(mismatch, "feature_flag.evaluation.error.message"))
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		metrics := collect(t, reader)
+
+		evaluations, ok := metrics[MetricEvaluations].(metricdata.Sum[int64])
+		require.True(t, ok)
+		counts := map[string]int64{}
+		for _, point := range evaluations.DataPoints {
+			counts[attr(point.Attributes, "feature_flag.key")+"/"+attr(point.Attributes, "feature_flag.evaluation.reason")] += point.Value
+		}
+		assert.Equal(t, map[string]int64{"checkout/targeting_match": 1, "checkout/default": 1, "checkout/error": 1}, counts)
+
+		evaluationErrors, ok := metrics[MetricErrors].(metricdata.Sum[int64])
+		require.True(t, ok)
+		require.Len(t, evaluationErrors.DataPoints, 1)
+		assert.Equal(t, int64(1), evaluationErrors.DataPoints[0].Value)
+		assert.Equal(t, "type_mismatch", attr(evaluationErrors.DataPoints[0].Attributes, "error.type"))
+
+		duration, ok := metrics[MetricDuration].(metricdata.Histogram[float64])
+		require.True(t, ok)
+		var total uint64
+		for _, point := range duration.DataPoints {
+			total += point.Count
+		}
+		assert.Equal(t, uint64(3), total)
+	})
+}
+
+func TestHookWithoutSpan(t *testing.T) {
+	client, spans, _, reader := setup(t, NewOptions())
+
+	assert.False(t, client.Boolean(context.Background(), "checkout", false, openfeature.EvaluationContext{}))
+	assert.Empty(t, spans.GetSpans())
+
+	evaluations, ok := collect(t, reader)[MetricEvaluations].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, evaluations.DataPoints, 1)
+	assert.Equal(t, int64(1), evaluations.DataPoints[0].Value)
+}
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "provider_not_ready", errorType(openfeature.ProviderNotReadyError))
+	assert.Equal(t, "type_mismatch", errorType(openfeature.NewTypeMismatchResolutionError("not a bool")))
+	assert.Equal(t, "flag_not_found", errorType(openfeature.ProviderResolutionDetail{
+		ResolutionError: openfeature.NewFlagNotFoundResolutionError("missing"),
+	}.Error()))
+	assert.Equal(t, "general", errorType(context.DeadlineExceeded))
+}
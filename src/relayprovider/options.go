@@ -0,0 +1,92 @@
+package relayprovider
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// URL is the base URL of the relay, such as http://flag-relay:8017.
+	URL string
+
+	// ===== Optional =====
+
+	// Token is sent as a bearer token with every request to the relay.
+	Token string
+	// HTTPClient is the client used to reach the relay. It must not have
+	// a timeout, since the event stream is held open. If not provided, it
+	// defaults to a new http.Client.
+	HTTPClient *http.Client
+	// ReconnectDelay is how long to wait before reconnecting after the
+	// event stream is lost. It doubles after every failed attempt, up to
+	// 30 seconds. If not provided, it defaults to 1 second.
+	ReconnectDelay time.Duration
+	// Logger is the logger to use for the provider.
+	// This is only used for logging service-fatal errors.
+	Logger *slog.Logger
+}
+
+func NewOptions(url string) *Options {
+	return &Options{
+		URL: url,
+	}
+}
+
+func (opts *Options) WithToken(token string) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Token = token
+	return opts
+}
+
+func (opts *Options) WithHTTPClient(client *http.Client) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.HTTPClient = client
+	return opts
+}
+
+func (opts *Options) WithReconnectDelay(delay time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.ReconnectDelay = delay
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.URL == "" {
+		return mongoopenfeature.ErrMissingURL
+	}
+
+	// Setting defaults
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{}
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
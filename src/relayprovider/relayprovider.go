@@ -0,0 +1,293 @@
+// Package relayprovider is an OpenFeature provider that gets its flags from a
+// relay (see cmd/relay) instead of MongoDB, so many service instances can
+// share one change stream. Flags are evaluated locally from a cache, exactly
+// as mongoprovider does.
+package relayprovider
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/eventhandler"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/relay"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/statehandler"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+)
+
+const (
+	ProviderName = "MongoDBRelayFeatureProvider"
+
+	snapshotTimeout   = 10 * time.Second
+	maxReconnectDelay = 30 * time.Second
+	// maxEventSize bounds a single event, which for snapshots holds every flag.
+	maxEventSize = 64 << 20
+)
+
+var _ openfeature.FeatureProvider = (*Provider)(nil)
+var _ openfeature.EventHandler = (*Provider)(nil)
+var _ openfeature.StateHandler = (*Provider)(nil)
+
+func New(opts *Options) (*Provider, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating options: %w", err)
+	}
+	cacheHandler := cache.New()
+
+	eventHandler, err := eventhandler.New(eventhandler.NewOptions(
+		eventhandler.CreateDroppedEventLogger(opts.Logger, ProviderName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("creating event handler: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Provider{
+		EventHandler:   eventHandler,
+		StateHandler:   statehandler.New(),
+		CacheEvaluator: cache.NewEvaluator(cacheHandler),
+		cache:          cacheHandler,
+
+		url:            strings.TrimSuffix(opts.URL, "/"),
+		token:          opts.Token,
+		httpClient:     opts.HTTPClient,
+		reconnectDelay: opts.ReconnectDelay,
+		logger:         opts.Logger,
+
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	p.StateHandler.RegisterStartupFunc(func() error {
+		snapshotCtx, cancel := context.WithTimeout(p.ctx, snapshotTimeout)
+		defer cancel()
+		if err := p.loadSnapshot(snapshotCtx); err != nil {
+			return fmt.Errorf("loading snapshot from relay: %w", err)
+		}
+		go p.follow()
+		return nil
+	})
+	p.StateHandler.RegisterShutdownFunc(func() {
+		p.cancel()
+		<-p.done
+	})
+	p.StateHandler.RegisterShutdownFunc(p.EventHandler.Close)
+	return p, nil
+}
+
+type Provider struct {
+	*eventhandler.EventHandler
+	*statehandler.StateHandler
+	*cache.CacheEvaluator
+	cache *cache.Cache
+
+	url            string
+	token          string
+	httpClient     *http.Client
+	reconnectDelay time.Duration
+	logger         *slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is closed when follow returns.
+	done chan struct{}
+
+	mu sync.Mutex
+	// version is the relay version the cache holds.
+	version uint64
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: ProviderName}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+func (p *Provider) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	return req, nil
+}
+
+func (p *Provider) loadSnapshot(ctx context.Context) error {
+	req, err := p.newRequest(ctx, relay.SnapshotPath)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("requesting snapshot: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	return p.applyEvent(relay.EventSnapshot, data)
+}
+
+// follow keeps the cache up to date from the relay's event stream until the
+// provider is shut down, reconnecting with backoff when the stream is lost.
+func (p *Provider) follow() {
+	defer close(p.done)
+	delay := p.reconnectDelay
+	for {
+		received, err := p.stream()
+		if p.ctx.Err() != nil {
+			return
+		}
+		if received {
+			delay = p.reconnectDelay
+		}
+		p.logger.Error("relay stream lost, reconnecting", "error", err, "delay", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-p.ctx.Done():
+			return
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// stream reads the event stream from the version the cache holds until it
+// ends. It reports whether any event was applied, so a stream that worked
+// for a while resets the reconnect backoff.
+func (p *Provider) stream() (bool, error) {
+	p.mu.Lock()
+	since := p.version
+	p.mu.Unlock()
+
+	req, err := p.newRequest(p.ctx, relay.StreamPath+"?since="+strconv.FormatUint(since, 10))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("connecting to stream: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("connecting to stream: unexpected status %s", resp.Status)
+	}
+
+	received := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+	var name string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if name != "" {
+				if err := p.applyEvent(name, []byte(data.String())); err != nil {
+					return received, err
+				}
+				received = true
+			}
+			name = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return received, fmt.Errorf("reading stream: %w", err)
+	}
+	return received, errors.New("stream closed by relay")
+}
+
+// applyEvent applies a snapshot or delta to the cache as one change and
+// publishes a configuration change event naming the flags it touched.
+func (p *Provider) applyEvent(name string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var version uint64
+	var set []flag.Definition
+	var deleted []string
+	switch name {
+	case relay.EventSnapshot:
+		snapshot, err := relay.DecodeSnapshot(data)
+		if err != nil {
+			return err
+		}
+		version, set = snapshot.Version, snapshot.Flags
+		incoming := make(map[string]bool, len(set))
+		for _, definition := range set {
+			incoming[definition.FlagName] = true
+		}
+		for flagKey := range p.cache.All() {
+			if !incoming[flagKey] {
+				deleted = append(deleted, flagKey)
+			}
+		}
+	case relay.EventDelta:
+		delta, err := relay.DecodeDelta(data)
+		if err != nil {
+			return err
+		}
+		if delta.Version != p.version+1 {
+			// Reconnecting from p.version gets the missing deltas or a
+			// new snapshot.
+			return fmt.Errorf("expected version %d, got %d", p.version+1, delta.Version)
+		}
+		version, set, deleted = delta.Version, delta.Set, delta.Deleted
+	default:
+		return nil
+	}
+
+	sets := make(map[string]any, len(set))
+	changed := make([]string, 0, len(set)+len(deleted))
+	for _, definition := range set {
+		sets[definition.FlagName] = definition
+		changed = append(changed, definition.FlagName)
+	}
+	changed = append(changed, deleted...)
+	if err := p.cache.Apply(sets, deleted); err != nil {
+		return fmt.Errorf("applying %s: %w", name, err)
+	}
+	initial := p.version == 0
+	p.version = version
+	if initial {
+		return nil
+	}
+
+	p.EventHandler.Publish(openfeature.Event{
+		ProviderName: ProviderName,
+		EventType:    openfeature.ProviderConfigChange,
+		ProviderEventDetails: openfeature.ProviderEventDetails{
+			Message:     fmt.Sprintf("Relay version %d", version),
+			FlagChanges: changed,
+		},
+	})
+	return nil
+}
@@ -0,0 +1,95 @@
+package relayprovider
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/internal/relay"
+	"github.com/zackarysantana/mongo-openfeature-go/src/cache"
+	"github.com/zackarysantana/mongo-openfeature-go/src/flag"
+	"github.com/zackarysantana/mongo-openfeature-go/src/rule"
+)
+
+func newRelay(t *testing.T, tokens ...string) (*relay.Server, *cache.Cache, string) {
+	t.Helper()
+	c := cache.New()
+	require.NoError(t, c.SetAll(map[string]flag.Definition{
+		"checkout": {
+			FlagName:     "checkout",
+			DefaultValue: false,
+			Rules: []rule.ConcreteRule{
+				{ExactMatchRule: &rule.ExactMatchRule{Key: "user", KeyValue: "alice", VariantID: "on", ValueData: true}},
+			},
+		},
+		"max-items": {FlagName: "max-items", DefaultValue: int64(10)},
+	}))
+	server, err := relay.New(relay.NewOptions(c).WithTokens(tokens...))
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return server, c, httpServer.URL
+}
+
+func waitForChange(t *testing.T, p *Provider) openfeature.Event {
+	t.Helper()
+	select {
+	case event := <-p.EventChannel():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a configuration change")
+		return openfeature.Event{}
+	}
+}
+
+func TestProvider(t *testing.T) {
+	server, c, url := newRelay(t, "secret")
+
+	p, err := New(NewOptions(url).WithToken("secret").WithReconnectDelay(10 * time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, p.Init(openfeature.EvaluationContext{}))
+	t.Cleanup(p.Shutdown)
+
+	ctx := context.Background()
+	assert.True(t, p.BooleanEvaluation(ctx, "checkout", false, openfeature.FlattenedContext{"user": "alice"}).Value)
+	assert.False(t, p.BooleanEvaluation(ctx, "checkout", true, openfeature.FlattenedContext{"user": "bob"}).Value)
+	assert.Equal(t, int64(10), p.IntEvaluation(ctx, "max-items", 0, nil).Value)
+
+	t.Run("Delta", func(t *testing.T) {
+		require.NoError(t, c.Set("max-items", flag.Definition{FlagName: "max-items", DefaultValue: int64(20)}))
+		require.NoError(t, c.Apply(nil, []string{"checkout"}))
+		require.NoError(t, server.Refresh())
+
+		event := waitForChange(t, p)
+		assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+		assert.ElementsMatch(t, []string{"max-items", "checkout"}, event.FlagChanges)
+		assert.Equal(t, int64(20), p.IntEvaluation(ctx, "max-items", 0, nil).Value)
+		assert.Equal(t, openfeature.DefaultReason, p.BooleanEvaluation(ctx, "checkout", true, nil).Reason)
+	})
+
+	t.Run("NoChange", func(t *testing.T) {
+		require.NoError(t, server.Refresh())
+		select {
+		case event := <-p.EventChannel():
+			t.Fatalf("unexpected event %+v", event)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, _, url := newRelay(t, "secret")
+
+	p, err := New(NewOptions(url).WithToken("wrong"))
+	require.NoError(t, err)
+	assert.ErrorContains(t, p.Init(openfeature.EvaluationContext{}), "401")
+}
+
+func TestOptions(t *testing.T) {
+	_, err := New(NewOptions(""))
+	assert.Error(t, err)
+}
@@ -25,7 +25,9 @@ func CollectContextKeyFields(rules []ConcreteRule) []ContextKeyField {
 	byKey := make(map[string][]ContextKeyRef)
 
 	for i, cr := range rules {
-		walkRule(cr, i, "", byKey)
+		walkRule(cr, i, "", func(use ContextKeyUse) {
+			byKey[use.Key] = appendRefIfNew(byKey[use.Key], use.Ref)
+		})
 	}
 
 	if len(byKey) == 0 {
@@ -62,28 +64,51 @@ func CollectContextKeys(rules []ConcreteRule) []string {
 	return keys
 }
 
-func walkRule(cr ConcreteRule, topLevel int, nestedIn string, byKey map[string][]ContextKeyRef) {
+// ContextKeyUse is a read of a context key by a single rule.
+type ContextKeyUse struct {
+	Key string
+	Ref ContextKeyRef
+	// RuleType is the type of the rule reading the key, such as "regexRule".
+	RuleType string
+}
+
+// CollectContextKeyUses returns every read of a context key by the given
+// rules (including nested composite rules), in rule order.
+func CollectContextKeyUses(rules []ConcreteRule) []ContextKeyUse {
+	var uses []ContextKeyUse
+	for i, cr := range rules {
+		walkRule(cr, i, "", func(use ContextKeyUse) {
+			uses = append(uses, use)
+		})
+	}
+	return uses
+}
+
+func walkRule(cr ConcreteRule, topLevel int, nestedIn string, visit func(ContextKeyUse)) {
 	ruleType := cr.RuleType()
 
 	for _, k := range directContextKeys(cr) {
-		ref := ContextKeyRef{
-			TopLevelIndex: topLevel,
-			Label:         formatContextKeyRefLabel(topLevel, ruleType, nestedIn),
-		}
-		byKey[k] = appendRefIfNew(byKey[k], ref)
+		visit(ContextKeyUse{
+			Key: k,
+			Ref: ContextKeyRef{
+				TopLevelIndex: topLevel,
+				Label:         formatContextKeyRefLabel(topLevel, ruleType, nestedIn),
+			},
+			RuleType: ruleType,
+		})
 	}
 
 	switch {
 	case cr.AndRule != nil:
 		for _, child := range cr.AndRule.Rules {
-			walkRule(child, topLevel, ruleType, byKey)
+			walkRule(child, topLevel, ruleType, visit)
 		}
 	case cr.OrRule != nil:
 		for _, child := range cr.OrRule.Rules {
-			walkRule(child, topLevel, ruleType, byKey)
+			walkRule(child, topLevel, ruleType, visit)
 		}
 	case cr.NotRule != nil:
-		walkRule(cr.NotRule.Rule, topLevel, ruleType, byKey)
+		walkRule(cr.NotRule.Rule, topLevel, ruleType, visit)
 	}
 }
 
@@ -115,27 +140,27 @@ func dedupeRefs(refs []ContextKeyRef) []ContextKeyRef {
 }
 
 // directContextKeys returns context keys read directly by this rule (not via
-// composite children).
+// composite children). Rules that default to the targeting key report it.
 func directContextKeys(cr ConcreteRule) []string {
 	switch {
 	case cr.ExactMatchRule != nil:
-		return []string{cr.ExactMatchRule.Key}
+		return []string{KeyOrTargetingKey(cr.ExactMatchRule.Key)}
 	case cr.RegexRule != nil:
-		return []string{cr.RegexRule.Key}
+		return []string{KeyOrTargetingKey(cr.RegexRule.Key)}
 	case cr.ExistsRule != nil:
-		return []string{cr.ExistsRule.Key}
+		return []string{KeyOrTargetingKey(cr.ExistsRule.Key)}
 	case cr.FractionalRule != nil:
-		return []string{cr.FractionalRule.Key}
+		return []string{KeyOrTargetingKey(cr.FractionalRule.Key)}
 	case cr.RangeRule != nil:
 		return []string{cr.RangeRule.Key}
 	case cr.InListRule != nil:
-		return []string{cr.InListRule.Key}
+		return []string{KeyOrTargetingKey(cr.InListRule.Key)}
 	case cr.PrefixRule != nil:
-		return []string{cr.PrefixRule.Key}
+		return []string{KeyOrTargetingKey(cr.PrefixRule.Key)}
 	case cr.SuffixRule != nil:
-		return []string{cr.SuffixRule.Key}
+		return []string{KeyOrTargetingKey(cr.SuffixRule.Key)}
 	case cr.ContainsRule != nil:
-		return []string{cr.ContainsRule.Key}
+		return []string{KeyOrTargetingKey(cr.ContainsRule.Key)}
 	case cr.IPRangeRule != nil:
 		return []string{cr.IPRangeRule.Key}
 	case cr.GeoFenceRule != nil:
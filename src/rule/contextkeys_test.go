@@ -23,6 +23,15 @@ func TestCollectContextKeys(t *testing.T) {
 	assert.Equal(t, []string{"lat", "lng", "region", "user_id"}, keys)
 }
 
+func TestCollectContextKeysTargetingKey(t *testing.T) {
+	keys := CollectContextKeys([]ConcreteRule{
+		{FractionalRule: &FractionalRule{Percentage: 10}},
+		{DateTimeRule: &DateTimeRule{Key: "signup"}},
+	})
+
+	assert.Equal(t, []string{"signup", TargetingKey}, keys)
+}
+
 func TestCollectContextKeysEmpty(t *testing.T) {
 	assert.Nil(t, CollectContextKeys(nil))
 	assert.Nil(t, CollectContextKeys([]ConcreteRule{
@@ -57,3 +66,18 @@ func TestCollectContextKeyFieldsRuleRefs(t *testing.T) {
 		{TopLevelIndex: 1, Label: "#2 andRule · prefixRule"},
 	}, byKey["region"].Rules)
 }
+
+func TestCollectContextKeyUses(t *testing.T) {
+	rules := []ConcreteRule{
+		{RangeRule: &RangeRule{Key: "age"}},
+		{NotRule: &NotRule{Rule: ConcreteRule{
+			GeoFenceRule: &GeoFenceRule{LatKey: "lat", LngKey: "lng"},
+		}}},
+	}
+
+	assert.Equal(t, []ContextKeyUse{
+		{Key: "age", Ref: ContextKeyRef{TopLevelIndex: 0, Label: "#1 rangeRule"}, RuleType: "rangeRule"},
+		{Key: "lat", Ref: ContextKeyRef{TopLevelIndex: 1, Label: "#2 notRule · geoFenceRule"}, RuleType: "geoFenceRule"},
+		{Key: "lng", Ref: ContextKeyRef{TopLevelIndex: 1, Label: "#2 notRule · geoFenceRule"}, RuleType: "geoFenceRule"},
+	}, CollectContextKeyUses(rules))
+}
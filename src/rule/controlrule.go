@@ -5,10 +5,13 @@ import (
 	"strings"
 )
 
-// AndRule matches if ALL children match; Variant = "&(v1+v2+…)".
+// AndRule matches if ALL children match; Variant = VariantID or "&(v1+v2+…)".
 type AndRule struct {
 	Rules []ConcreteRule
 
+	// VariantID names the variant when set, instead of the combined
+	// variants of the children.
+	VariantID string `bson:",omitempty" json:",omitempty"`
 	Priority  int
 	ValueData any
 }
@@ -25,6 +28,9 @@ func (r *AndRule) Matches(ctx map[string]any) bool {
 func (r *AndRule) Value() any { return r.ValueData }
 
 func (r *AndRule) Variant() string {
+	if r.VariantID != "" {
+		return r.VariantID
+	}
 	parts := make([]string, len(r.Rules))
 	for i, c := range r.Rules {
 		parts[i] = c.Variant()
@@ -34,10 +40,13 @@ func (r *AndRule) Variant() string {
 
 func (r *AndRule) GetPriority() int { return r.Priority }
 
-// OrRule matches if ANY child matches; Variant = "|(v1+v2+…)".
+// OrRule matches if ANY child matches; Variant = VariantID or "|(v1+v2+…)".
 type OrRule struct {
 	Rules []ConcreteRule
 
+	// VariantID names the variant when set, instead of the combined
+	// variants of the children.
+	VariantID string `bson:",omitempty" json:",omitempty"`
 	Priority  int
 	ValueData any
 }
@@ -54,6 +63,9 @@ func (r *OrRule) Matches(ctx map[string]any) bool {
 func (r *OrRule) Value() any { return r.ValueData }
 
 func (r *OrRule) Variant() string {
+	if r.VariantID != "" {
+		return r.VariantID
+	}
 	parts := make([]string, len(r.Rules))
 	for i, c := range r.Rules {
 		parts[i] = c.Variant()
@@ -63,10 +75,13 @@ func (r *OrRule) Variant() string {
 
 func (r *OrRule) GetPriority() int { return r.Priority }
 
-// NotRule inverts a single child; Variant = "!(v)".
+// NotRule inverts a single child; Variant = VariantID or "!(v)".
 type NotRule struct {
 	Rule ConcreteRule
 
+	// VariantID names the variant when set, instead of the negated
+	// variant of the child.
+	VariantID string `bson:",omitempty" json:",omitempty"`
 	Priority  int
 	ValueData any
 }
@@ -75,8 +90,13 @@ func (r *NotRule) Matches(ctx map[string]any) bool {
 	return !r.Rule.Matches(ctx)
 }
 
-func (r *NotRule) Value() any       { return r.ValueData }
-func (r *NotRule) Variant() string  { return "!(" + r.Rule.Variant() + ")" }
+func (r *NotRule) Value() any { return r.ValueData }
+func (r *NotRule) Variant() string {
+	if r.VariantID != "" {
+		return r.VariantID
+	}
+	return "!(" + r.Rule.Variant() + ")"
+}
 func (r *NotRule) GetPriority() int { return r.Priority }
 
 type OverrideRule struct {
@@ -158,3 +158,14 @@ func TestOverrideRule(t *testing.T) {
 		})
 	}
 }
+
+func TestControlRuleVariant(t *testing.T) {
+	child := ConcreteRule{PrefixRule: &PrefixRule{Key: "email", Prefix: "admin", VariantID: "admin"}}
+
+	assert.Equal(t, "&(admin)", (&AndRule{Rules: []ConcreteRule{child}}).Variant())
+	assert.Equal(t, "on", (&AndRule{Rules: []ConcreteRule{child}, VariantID: "on"}).Variant())
+	assert.Equal(t, "|(admin)", (&OrRule{Rules: []ConcreteRule{child}}).Variant())
+	assert.Equal(t, "on", (&OrRule{Rules: []ConcreteRule{child}, VariantID: "on"}).Variant())
+	assert.Equal(t, "!(admin)", (&NotRule{Rule: child}).Variant())
+	assert.Equal(t, "off", (&NotRule{Rule: child, VariantID: "off"}).Variant())
+}
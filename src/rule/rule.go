@@ -15,7 +15,8 @@ import (
 	cron "github.com/robfig/cron/v3"
 )
 
-// ExactMatchRule fires if ctx[Key] deep‐equals ValueData.
+// ExactMatchRule fires if ctx[Key] deep‐equals KeyValue. An empty Key reads
+// the targeting key.
 type ExactMatchRule struct {
 	Key      string
 	KeyValue string
@@ -26,7 +27,7 @@ type ExactMatchRule struct {
 }
 
 func (r *ExactMatchRule) Matches(ctx map[string]any) bool {
-	v, ok := ctx[r.Key]
+	v, ok := ctx[KeyOrTargetingKey(r.Key)]
 	return ok && (v == r.KeyValue || reflect.DeepEqual(v, r.KeyValue))
 }
 
@@ -34,7 +35,8 @@ func (r *ExactMatchRule) Value() any       { return r.ValueData }
 func (r *ExactMatchRule) Variant() string  { return r.VariantID }
 func (r *ExactMatchRule) GetPriority() int { return r.Priority }
 
-// RegexRule fires if ctx[Key] (string) matches Pattern.
+// RegexRule fires if ctx[Key] (string) matches Pattern. An empty Key reads
+// the targeting key.
 type RegexRule struct {
 	Key     string
 	Pattern string
@@ -48,7 +50,7 @@ type RegexRule struct {
 }
 
 func (r *RegexRule) Matches(ctx map[string]any) bool {
-	v, ok := ctx[r.Key]
+	v, ok := ctx[KeyOrTargetingKey(r.Key)]
 	if !ok {
 		return false
 	}
@@ -69,7 +71,8 @@ func (r *RegexRule) Value() any       { return r.ValueData }
 func (r *RegexRule) Variant() string  { return r.VariantID }
 func (r *RegexRule) GetPriority() int { return r.Priority }
 
-// ExistsRule fires if ctx contains Key at all.
+// ExistsRule fires if ctx contains Key at all. An empty Key checks for the
+// targeting key.
 type ExistsRule struct {
 	Key string
 
@@ -79,7 +82,7 @@ type ExistsRule struct {
 }
 
 func (r *ExistsRule) Matches(ctx map[string]any) bool {
-	_, ok := ctx[r.Key]
+	_, ok := ctx[KeyOrTargetingKey(r.Key)]
 	return ok
 }
 
@@ -88,6 +91,8 @@ func (r *ExistsRule) Variant() string  { return r.VariantID }
 func (r *ExistsRule) GetPriority() int { return r.Priority }
 
 // FractionalRule fires a percentage of the time (deterministic via FNV+salt).
+// It buckets on ctx[Key], or on the targeting key if Key is empty, so the
+// same user always lands in the same bucket.
 type FractionalRule struct {
 	Key        string
 	Percentage float64 // in [0.0,100.0)
@@ -98,12 +103,13 @@ type FractionalRule struct {
 }
 
 func (r *FractionalRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key]
+	key := KeyOrTargetingKey(r.Key)
+	raw, ok := ctx[key]
 	if !ok {
 		return false
 	}
 	h := fnv.New32a()
-	fmt.Fprint(h, r.Key, raw)
+	fmt.Fprint(h, key, raw)
 	bucket := h.Sum32() % 100
 	return float64(bucket) < r.Percentage
 }
@@ -162,7 +168,8 @@ func (r *RangeRule) Value() any       { return r.ValueData }
 func (r *RangeRule) Variant() string  { return r.VariantID }
 func (r *RangeRule) GetPriority() int { return r.Priority }
 
-// InListRule fires if ctx[Key] is deep equal to one of Items.
+// InListRule fires if ctx[Key] is deep equal to one of Items. An empty Key
+// reads the targeting key.
 type InListRule struct {
 	Key   string
 	Items []any
@@ -173,7 +180,7 @@ type InListRule struct {
 }
 
 func (r *InListRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key]
+	raw, ok := ctx[KeyOrTargetingKey(r.Key)]
 	if !ok {
 		return false
 	}
@@ -189,7 +196,8 @@ func (r *InListRule) Value() any       { return r.ValueData }
 func (r *InListRule) Variant() string  { return r.VariantID }
 func (r *InListRule) GetPriority() int { return r.Priority }
 
-// PrefixRule fires if ctx[Key] (string) has the given prefix.
+// PrefixRule fires if ctx[Key] (string) has the given prefix. An empty Key
+// reads the targeting key.
 type PrefixRule struct {
 	Key    string
 	Prefix string
@@ -200,7 +208,7 @@ type PrefixRule struct {
 }
 
 func (r *PrefixRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key]
+	raw, ok := ctx[KeyOrTargetingKey(r.Key)]
 	if !ok {
 		return false
 	}
@@ -212,7 +220,8 @@ func (r *PrefixRule) Value() any       { return r.ValueData }
 func (r *PrefixRule) Variant() string  { return r.VariantID }
 func (r *PrefixRule) GetPriority() int { return r.Priority }
 
-// SuffixRule fires if ctx[Key] (string) has the given suffix.
+// SuffixRule fires if ctx[Key] (string) has the given suffix. An empty Key
+// reads the targeting key.
 type SuffixRule struct {
 	Key    string
 	Suffix string
@@ -223,7 +232,7 @@ type SuffixRule struct {
 }
 
 func (r *SuffixRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key]
+	raw, ok := ctx[KeyOrTargetingKey(r.Key)]
 	if !ok {
 		return false
 	}
@@ -235,7 +244,8 @@ func (r *SuffixRule) Value() any       { return r.ValueData }
 func (r *SuffixRule) Variant() string  { return r.VariantID }
 func (r *SuffixRule) GetPriority() int { return r.Priority }
 
-// ContainsRule fires if ctx[Key] (string) contains the given substring.
+// ContainsRule fires if ctx[Key] (string) contains the given substring. An
+// empty Key reads the targeting key.
 type ContainsRule struct {
 	Key       string
 	Substring string
@@ -246,7 +256,7 @@ type ContainsRule struct {
 }
 
 func (r *ContainsRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key]
+	raw, ok := ctx[KeyOrTargetingKey(r.Key)]
 	if !ok {
 		return false
 	}
@@ -357,7 +367,9 @@ func (r *GeoFenceRule) Value() any       { return r.ValueData }
 func (r *GeoFenceRule) Variant() string  { return r.VariantID }
 func (r *GeoFenceRule) GetPriority() int { return r.Priority }
 
-// DateTimeRule fires if ctx[Key] (time.Time) is between After and Before.
+// DateTimeRule fires if ctx[Key] is a time (see ParseTime) between After
+// and Before. Either bound may be left zero for an open-ended range, such as
+// "after launch" with only After.
 type DateTimeRule struct {
 	Key    string
 	After  time.Time
@@ -369,11 +381,11 @@ type DateTimeRule struct {
 }
 
 func (r *DateTimeRule) Matches(ctx map[string]any) bool {
-	raw, ok := ctx[r.Key].(time.Time)
+	raw, ok := ParseTime(ctx[r.Key])
 	if !ok {
 		return false
 	}
-	return raw.After(r.After) && raw.Before(r.Before)
+	return (r.After.IsZero() || raw.After(r.After)) && (r.Before.IsZero() || raw.Before(r.Before))
 }
 
 func (r *DateTimeRule) Value() any       { return r.ValueData }
@@ -419,8 +431,8 @@ func (r *SemVerRule) GetPriority() int { return r.Priority }
 // at a time defined by the CronSpec and lasts for the specified Duration.
 //
 // The time to be checked can be provided in two ways:
-//  1. From the context: If Key is set, the rule will look for a time.Time
-//     value in ctx[Key].
+//  1. From the context: If Key is set, the rule will look for a time in
+//     ctx[Key], in any form ParseTime accepts.
 //  2. From the system clock: If Key is an empty string (""), the rule will
 //     use time.Now() as the time to check.
 //
@@ -455,15 +467,11 @@ func (r *CronRule) Matches(ctx map[string]any) bool {
 		checkTime = time.Now()
 		ok = true
 	} else {
-		var raw any
-		raw, ok = ctx[r.Key]
-		if ok {
-			checkTime, ok = raw.(time.Time)
-		}
+		checkTime, ok = ParseTime(ctx[r.Key])
 	}
 
 	if !ok {
-		// This fails if the key was not found or the value was not a time.Time.
+		// This fails if the key was not found or the value was not a time.
 		return false
 	}
 
@@ -450,6 +450,30 @@ func TestDateTimeRule(t *testing.T) {
 			},
 			matches: false,
 		},
+		"InRangeRFC3339String": {
+			ctx: map[string]any{
+				"test_key": "2023-10-01T12:00:00Z",
+			},
+			matches: true,
+		},
+		"InRangeUnixSeconds": {
+			ctx: map[string]any{
+				"test_key": float64(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC).Unix()),
+			},
+			matches: true,
+		},
+		"InRangeUnixMilliseconds": {
+			ctx: map[string]any{
+				"test_key": time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC).UnixMilli(),
+			},
+			matches: true,
+		},
+		"InvalidString": {
+			ctx: map[string]any{
+				"test_key": "yesterday",
+			},
+			matches: false,
+		},
 	} {
 		t.Run(tName, func(t *testing.T) {
 			rule := &DateTimeRule{
@@ -464,6 +488,23 @@ func TestDateTimeRule(t *testing.T) {
 	}
 }
 
+func TestDateTimeRule_OpenEnded(t *testing.T) {
+	launch := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := map[string]any{"test_key": launch.Add(-time.Hour)}
+	after := map[string]any{"test_key": launch.Add(time.Hour)}
+
+	t.Run("OnlyAfter", func(t *testing.T) {
+		rule := &DateTimeRule{Key: "test_key", After: launch}
+		assert.False(t, rule.Matches(before))
+		assert.True(t, rule.Matches(after))
+	})
+	t.Run("OnlyBefore", func(t *testing.T) {
+		rule := &DateTimeRule{Key: "test_key", Before: launch}
+		assert.True(t, rule.Matches(before))
+		assert.False(t, rule.Matches(after))
+	})
+}
+
 func TestSemVerRule(t *testing.T) {
 	rule := &SemVerRule{
 		Key:        "app_version",
@@ -0,0 +1,14 @@
+package rule
+
+// TargetingKey is the context key the OpenFeature targeting key is flattened
+// into. Rules that identify a user, such as ExactMatchRule, InListRule and
+// FractionalRule, read it when their Key is empty.
+const TargetingKey = "targetingKey"
+
+// KeyOrTargetingKey returns key, or TargetingKey if key is empty.
+func KeyOrTargetingKey(key string) string {
+	if key == "" {
+		return TargetingKey
+	}
+	return key
+}
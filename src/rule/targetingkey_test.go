@@ -0,0 +1,43 @@
+package rule
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyOrTargetingKey(t *testing.T) {
+	assert.Equal(t, TargetingKey, KeyOrTargetingKey(""))
+	assert.Equal(t, "user_id", KeyOrTargetingKey("user_id"))
+}
+
+func TestRulesDefaultToTargetingKey(t *testing.T) {
+	ctx := map[string]any{TargetingKey: "user-123"}
+
+	for tName, r := range map[string]Rule{
+		"ExactMatch": &ExactMatchRule{KeyValue: "user-123"},
+		"Regex":      &RegexRule{Pattern: `^user-\d+$`},
+		"Exists":     &ExistsRule{},
+		"InList":     &InListRule{Items: []any{"user-1", "user-123"}},
+		"Prefix":     &PrefixRule{Prefix: "user-"},
+		"Suffix":     &SuffixRule{Suffix: "-123"},
+		"Contains":   &ContainsRule{Substring: "r-1"},
+		"Fractional": &FractionalRule{Percentage: 100},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			assert.True(t, r.Matches(ctx))
+			assert.False(t, r.Matches(map[string]any{"user_id": "user-123"}))
+		})
+	}
+}
+
+func TestFractionalRule_TargetingKeyBuckets(t *testing.T) {
+	implicit := &FractionalRule{Percentage: 30}
+	explicit := &FractionalRule{Key: TargetingKey, Percentage: 30}
+
+	for i := range 1000 {
+		ctx := map[string]any{TargetingKey: fmt.Sprintf("user-%d", i)}
+		assert.Equal(t, explicit.Matches(ctx), implicit.Matches(ctx), "user-%d", i)
+	}
+}
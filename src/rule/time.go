@@ -0,0 +1,75 @@
+package rule
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// millisecondThreshold separates Unix epoch seconds from milliseconds. As
+// seconds it is the year 5138, as milliseconds 1973, so any realistic time
+// sent in milliseconds, such as JavaScript's Date.now(), is above it.
+const millisecondThreshold = 1e11
+
+// ParseTime converts a context value to a time, so time-based rules work
+// with whatever callers can send. It accepts:
+//   - time.Time and *time.Time, from Go callers;
+//   - bson.DateTime, from values read from MongoDB;
+//   - RFC 3339 strings, with or without fractional seconds, from JSON
+//     contexts such as OFREP requests;
+//   - Unix epoch numbers, including json.Number, in seconds or, when above
+//     1e11, milliseconds.
+func ParseTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	case bson.DateTime:
+		return v.Time(), true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return epochTime(float64(i)), true
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return epochTime(f), true
+	case int:
+		return epochTime(float64(v)), true
+	case int32:
+		return epochTime(float64(v)), true
+	case int64:
+		return epochTime(float64(v)), true
+	case uint32:
+		return epochTime(float64(v)), true
+	case uint64:
+		return epochTime(float64(v)), true
+	case float32:
+		return epochTime(float64(v)), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return time.Time{}, false
+		}
+		return epochTime(v), true
+	}
+	return time.Time{}, false
+}
+
+// epochTime converts Unix epoch seconds or milliseconds to a UTC time.
+func epochTime(epoch float64) time.Time {
+	if math.Abs(epoch) >= millisecondThreshold {
+		return time.UnixMilli(int64(epoch)).UTC()
+	}
+	seconds, fraction := math.Modf(epoch)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+}
@@ -0,0 +1,52 @@
+package rule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for tName, tCase := range map[string]struct {
+		value any
+		ok    bool
+	}{
+		"Time":              {value: want, ok: true},
+		"TimePointer":       {value: &want, ok: true},
+		"NilTimePointer":    {value: (*time.Time)(nil), ok: false},
+		"BSONDateTime":      {value: bson.NewDateTimeFromTime(want), ok: true},
+		"RFC3339":           {value: "2023-10-01T12:00:00Z", ok: true},
+		"RFC3339Offset":     {value: "2023-10-01T14:00:00+02:00", ok: true},
+		"RFC3339Nano":       {value: "2023-10-01T12:00:00.000000000Z", ok: true},
+		"DateOnly":          {value: "2023-10-01", ok: false},
+		"UnixSecondsInt":    {value: int(want.Unix()), ok: true},
+		"UnixSecondsInt64":  {value: want.Unix(), ok: true},
+		"UnixSecondsFloat":  {value: float64(want.Unix()), ok: true},
+		"UnixMilliseconds":  {value: want.UnixMilli(), ok: true},
+		"UnixMillisFloat":   {value: float64(want.UnixMilli()), ok: true},
+		"JSONNumberSeconds": {value: json.Number("1696161600"), ok: true},
+		"JSONNumberMillis":  {value: json.Number("1696161600000"), ok: true},
+		"JSONNumberInvalid": {value: json.Number("soon"), ok: false},
+		"Bool":              {value: true, ok: false},
+		"Nil":               {value: nil, ok: false},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			got, ok := ParseTime(tCase.value)
+			assert.Equal(t, tCase.ok, ok)
+			if tCase.ok {
+				assert.True(t, want.Equal(got), "got %s", got)
+			}
+		})
+	}
+}
+
+func TestParseTime_FractionalSeconds(t *testing.T) {
+	got, ok := ParseTime(1696161600.5)
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, got.Sub(time.Unix(1696161600, 0)))
+}
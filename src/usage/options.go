@@ -0,0 +1,66 @@
+package usage
+
+import (
+	"log/slog"
+	"time"
+
+	mongoopenfeature "github.com/zackarysantana/mongo-openfeature-go/src"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+type Options struct {
+	// ===== Required =====
+
+	// Client writes the counts. mongoprovider replaces it with the
+	// provider's own client.
+	Client *client.Client
+
+	// ===== Optional =====
+
+	// FlushInterval is how often the counts are added to MongoDB. If not
+	// provided, it defaults to 1 minute.
+	FlushInterval time.Duration
+	// Logger is the logger to use for the counter.
+	Logger *slog.Logger
+}
+
+func NewOptions(client *client.Client) *Options {
+	return &Options{
+		Client: client,
+	}
+}
+
+func (opts *Options) WithFlushInterval(interval time.Duration) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.FlushInterval = interval
+	return opts
+}
+
+func (opts *Options) WithLogger(logger *slog.Logger) *Options {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Logger = logger
+	return opts
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return mongoopenfeature.ErrNilOptions
+	}
+	// Validating
+	if opts.Client == nil {
+		return mongoopenfeature.ErrMissingClient
+	}
+
+	// Setting defaults
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Minute
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Package usage counts how often each flag is evaluated, so flags nobody
+// evaluates any more, or that always resolve to the same variant, can be
+// found and removed. The Counter is an OpenFeature hook that keeps counts in
+// memory and adds them to MongoDB periodically, where the counts of every
+// provider add up.
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// writeTimeout bounds a single flush.
+const writeTimeout = 30 * time.Second
+
+var _ openfeature.Hook = (*Counter)(nil)
+
+func New(opts *Options) (*Counter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validating options: %w", err)
+	}
+	return start(opts, opts.Client.AddUsage), nil
+}
+
+// start creates a counter that flushes with write and starts it.
+func start(opts *Options, write func(context.Context, []client.UsageCount) error) *Counter {
+	c := &Counter{
+		flushInterval: opts.FlushInterval,
+		logger:        opts.Logger,
+		write:         write,
+		counts:        map[countKey]*client.UsageCount{},
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Counter is a hook that counts every successful evaluation by flag and
+// variant.
+type Counter struct {
+	openfeature.UnimplementedHook
+
+	flushInterval time.Duration
+	logger        *slog.Logger
+	write         func(context.Context, []client.UsageCount) error
+
+	mu     sync.Mutex
+	counts map[countKey]*client.UsageCount
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	// done is closed once the last counts are flushed.
+	done chan struct{}
+}
+
+type countKey struct {
+	flagName string
+	variant  string
+}
+
+// After counts a successful evaluation.
+func (c *Counter) After(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, hookHints openfeature.HookHints) error {
+	key := countKey{flagName: details.FlagKey, variant: details.Variant}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count, ok := c.counts[key]
+	if !ok {
+		count = &client.UsageCount{FlagName: key.flagName, Variant: key.variant}
+		c.counts[key] = count
+	}
+	count.Evaluations++
+	count.LastEvaluated = now
+	return nil
+}
+
+// Close stops counting and flushes the counts not yet written.
+func (c *Counter) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+func (c *Counter) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.stop:
+			c.flush()
+			return
+		}
+	}
+}
+
+// flush writes the counts and starts new ones. Counts that fail to write are
+// kept for the next flush.
+func (c *Counter) flush() {
+	c.mu.Lock()
+	pending := c.counts
+	c.counts = map[countKey]*client.UsageCount{}
+	c.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	counts := make([]client.UsageCount, 0, len(pending))
+	for _, count := range pending {
+		counts = append(counts, *count)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	err := c.write(ctx, counts)
+	if err == nil {
+		return
+	}
+	c.logger.Error("error writing flag usage, keeping it for the next flush", "flags", len(counts), "error", err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, old := range pending {
+		count, ok := c.counts[key]
+		if !ok {
+			c.counts[key] = old
+			continue
+		}
+		count.Evaluations += old.Evaluations
+		if old.LastEvaluated.After(count.LastEvaluated) {
+			count.LastEvaluated = old.LastEvaluated
+		}
+	}
+}
+
+// Reason is why a flag is a candidate for removal.
+type Reason string
+
+const (
+	// ReasonNeverEvaluated flags have no recorded evaluations.
+	ReasonNeverEvaluated Reason = "never_evaluated"
+	// ReasonNotEvaluated flags were not evaluated within the window.
+	ReasonNotEvaluated Reason = "not_evaluated"
+	// ReasonSingleVariant flags resolved to the same variant for every
+	// evaluation within the window.
+	ReasonSingleVariant Reason = "single_variant"
+)
+
+// Candidate is a flag that may be safe to remove.
+type Candidate struct {
+	FlagName string `json:"flagName"`
+	Reason   Reason `json:"reason"`
+	// LastEvaluated is zero for flags that were never evaluated.
+	LastEvaluated time.Time `json:"lastEvaluated,omitzero"`
+	// Evaluations is how many times the flag was evaluated within the
+	// window.
+	Evaluations int64 `json:"evaluations,omitempty"`
+	// Variant is the only variant served, for ReasonSingleVariant.
+	Variant string `json:"variant,omitempty"`
+}
+
+// StaleFlags reports the flags that were not evaluated within window, or
+// that always resolved to the same variant within it. It relies on every
+// provider counting usage; flags evaluated only by providers that don't are
+// reported as never evaluated.
+func StaleFlags(ctx context.Context, c *client.Client, window time.Duration) ([]Candidate, error) {
+	flags, err := c.GetAllFlags(ctx)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("getting all flags: %w", err)
+	}
+	now := time.Now()
+	usage, err := c.FlagUsage(ctx, now.Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("getting flag usage: %w", err)
+	}
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	return findStale(names, usage, now.Add(-window)), nil
+}
+
+// findStale returns the candidates among flagNames, ordered by name.
+func findStale(flagNames []string, usage map[string]client.FlagUsage, since time.Time) []Candidate {
+	sort.Strings(flagNames)
+	candidates := []Candidate{}
+	for _, name := range flagNames {
+		u, ok := usage[name]
+		switch {
+		case !ok || u.LastEvaluated.IsZero():
+			candidates = append(candidates, Candidate{FlagName: name, Reason: ReasonNeverEvaluated})
+		case u.LastEvaluated.Before(since):
+			candidates = append(candidates, Candidate{FlagName: name, Reason: ReasonNotEvaluated, LastEvaluated: u.LastEvaluated})
+		case len(u.Variants) == 1:
+			candidate := Candidate{FlagName: name, Reason: ReasonSingleVariant, LastEvaluated: u.LastEvaluated, Evaluations: u.Evaluations}
+			for variant := range u.Variants {
+				candidate.Variant = variant
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
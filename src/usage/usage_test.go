@@ -0,0 +1,144 @@
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zackarysantana/mongo-openfeature-go/src/client"
+)
+
+// fakeWriter records the counts a counter writes.
+type fakeWriter struct {
+	mu     sync.Mutex
+	counts []client.UsageCount
+	err    error
+}
+
+func (w *fakeWriter) write(ctx context.Context, counts []client.UsageCount) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.counts = append(w.counts, counts...)
+	return nil
+}
+
+func (w *fakeWriter) written() []client.UsageCount {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	counts := append([]client.UsageCount(nil), w.counts...)
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].FlagName != counts[j].FlagName {
+			return counts[i].FlagName < counts[j].FlagName
+		}
+		return counts[i].Variant < counts[j].Variant
+	})
+	return counts
+}
+
+func newCounter(t *testing.T, opts *Options, write func(context.Context, []client.UsageCount) error) *Counter {
+	t.Helper()
+	opts.Client = &client.Client{}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = time.Hour
+	}
+	require.NoError(t, opts.Validate())
+	return start(opts, write)
+}
+
+func evaluate(t *testing.T, c *Counter, flagName, variant string) {
+	t.Helper()
+	details := openfeature.InterfaceEvaluationDetails{
+		EvaluationDetails: openfeature.EvaluationDetails{
+			FlagKey:          flagName,
+			ResolutionDetail: openfeature.ResolutionDetail{Variant: variant},
+		},
+	}
+	require.NoError(t, c.After(context.Background(), openfeature.HookContext{}, details, openfeature.HookHints{}))
+}
+
+func TestCounter(t *testing.T) {
+	writer := &fakeWriter{}
+	counter := newCounter(t, NewOptions(nil), writer.write)
+	before := time.Now()
+	evaluate(t, counter, "checkout", "on")
+	evaluate(t, counter, "checkout", "on")
+	evaluate(t, counter, "checkout", "off")
+	evaluate(t, counter, "search", "v2")
+	counter.Close()
+
+	counts := writer.written()
+	require.Len(t, counts, 3)
+	assert.Equal(t, "checkout", counts[0].FlagName)
+	assert.Equal(t, "off", counts[0].Variant)
+	assert.Equal(t, int64(1), counts[0].Evaluations)
+	assert.Equal(t, "on", counts[1].Variant)
+	assert.Equal(t, int64(2), counts[1].Evaluations)
+	assert.Equal(t, "search", counts[2].FlagName)
+	assert.False(t, counts[2].LastEvaluated.Before(before))
+}
+
+func TestCounterFlushInterval(t *testing.T) {
+	writer := &fakeWriter{}
+	counter := newCounter(t, NewOptions(nil).WithFlushInterval(10*time.Millisecond), writer.write)
+	defer counter.Close()
+
+	evaluate(t, counter, "checkout", "on")
+	assert.Eventually(t, func() bool { return len(writer.written()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestCounterKeepsFailedCounts(t *testing.T) {
+	writer := &fakeWriter{err: assert.AnError}
+	counter := newCounter(t, NewOptions(nil), writer.write)
+	evaluate(t, counter, "checkout", "on")
+	counter.flush()
+	evaluate(t, counter, "checkout", "on")
+
+	writer.mu.Lock()
+	writer.err = nil
+	writer.mu.Unlock()
+	counter.Close()
+
+	counts := writer.written()
+	require.Len(t, counts, 1)
+	assert.Equal(t, int64(2), counts[0].Evaluations)
+}
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -30)
+	usage := map[string]client.FlagUsage{
+		"active": {
+			FlagName:      "active",
+			Evaluations:   300,
+			Variants:      map[string]int64{"on": 200, "off": 100},
+			LastEvaluated: now.Add(-time.Hour),
+		},
+		"forgotten": {
+			FlagName:      "forgotten",
+			Variants:      map[string]int64{},
+			LastEvaluated: now.AddDate(0, 0, -45),
+		},
+		"rolled-out": {
+			FlagName:      "rolled-out",
+			Evaluations:   1000,
+			Variants:      map[string]int64{"on": 1000},
+			LastEvaluated: now.Add(-time.Minute),
+		},
+		"deleted": {FlagName: "deleted", LastEvaluated: now},
+	}
+
+	candidates := findStale([]string{"rolled-out", "new", "forgotten", "active"}, usage, since)
+	assert.Equal(t, []Candidate{
+		{FlagName: "forgotten", Reason: ReasonNotEvaluated, LastEvaluated: now.AddDate(0, 0, -45)},
+		{FlagName: "new", Reason: ReasonNeverEvaluated},
+		{FlagName: "rolled-out", Reason: ReasonSingleVariant, LastEvaluated: now.Add(-time.Minute), Evaluations: 1000, Variant: "on"},
+	}, candidates)
+}